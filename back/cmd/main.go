@@ -21,44 +21,110 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/cauamistura/BNUPremios/internal/config"
 	"github.com/cauamistura/BNUPremios/internal/database"
 	"github.com/cauamistura/BNUPremios/internal/handlers"
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/payments"
 	"github.com/cauamistura/BNUPremios/internal/repository"
 	"github.com/cauamistura/BNUPremios/internal/routes"
 	"github.com/cauamistura/BNUPremios/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout limita a espera pelas requisições em andamento no encerramento do servidor
+const shutdownTimeout = 10 * time.Second
+
 func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
 
+// run inicia a API e bloqueia até o servidor parar. Retorna ao receber SIGINT/SIGTERM, depois de encerrar o servidor,
+// para que as tarefas em segundo plano e o banco sejam encerrados pelos defer
+func run() error {
 	// Carregar configurações
 	cfg := config.Load()
 
 	// Conectar ao banco de dados
 	db, err := database.Connect(cfg.Database)
 	if err != nil {
-		log.Fatal("Erro ao conectar ao banco de dados:", err)
+		return fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
 	}
 	defer db.Close()
 
 	// Executar migrações
 	if err := database.RunMigrations(cfg.Database); err != nil {
-		log.Fatal("Erro ao executar migrações:", err)
+		return fmt.Errorf("erro ao executar migrações: %w", err)
 	}
 
 	// Configurar repositórios
 	userRepo := repository.NewUserRepository(db)
 
 	// Configurar serviços
-	userService := services.NewUserService(userRepo, cfg.JWT.Secret)
+	referralService, err := services.NewReferralService(repository.NewReferralRepository(db), models.ReferralTerms{
+		RewardType:   cfg.Referral.RewardType,
+		BonusNumbers: cfg.Referral.BonusNumbers,
+		CreditAmount: cfg.Referral.CreditAmount,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao configurar programa de indicação: %w", err)
+	}
+	userService := services.NewUserService(userRepo, referralService, cfg.JWT.Secret)
+
+	// Configurar provedor de pagamento
+	paymentProvider, err := payments.NewProvider(cfg.Payment)
+	if err != nil {
+		return fmt.Errorf("erro ao configurar provedor de pagamento: %w", err)
+	}
+	if cfg.Payment.WebhookSecret == "" {
+		log.Println("PAYMENT_WEBHOOK_SECRET não configurado: webhooks de pagamento serão recusados")
+	}
+
+	purchaseRepo := repository.NewPurchaseRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	paymentService := services.NewPaymentService(purchaseRepo, userRepo, repository.NewRefundRepository(db), ledgerRepo, paymentProvider, cfg.Payment.WebhookSecret, cfg.Reservation.DirectPixTTL)
+	walletService := services.NewWalletService(ledgerRepo)
+	settlementService := services.NewSettlementService(repository.NewSettlementRepository(db), cfg.Settlement.DefaultFeePercent)
+	rewardRepo := repository.NewRewardRepository(db)
+	notificationService := services.NewNotificationService(repository.NewNotificationRepository(db))
+	claimService := services.NewPrizeClaimService(repository.NewPrizeClaimRepository(db), rewardRepo, notificationService, cfg.Draw.ClaimDeadline)
+	rewardService := services.NewRewardService(rewardRepo, purchaseRepo, paymentService, settlementService, notificationService, claimService, cfg.Reservation.TTL, cfg.Draw.MaxPostponements)
+	couponService := services.NewCouponService(repository.NewCouponRepository(db), rewardRepo)
+
+	// Configurar limpador de reservas vencidas
+	reservationSweeper := services.NewReservationSweeper(rewardService, cfg.Reservation.SweepInterval)
+	reservationSweeper.Start()
+	defer reservationSweeper.Stop()
+
+	// Configurar agendador de sorteios
+	drawScheduler := services.NewDrawScheduler(rewardService, repository.NewDrawScheduleRepository(db), cfg.Draw)
+	drawScheduler.Start()
+	defer drawScheduler.Stop()
 
 	// Configurar handlers
+	handlers.RegisterValidations()
 	userHandler := handlers.NewUserHandler(userService)
-	rewardHandler := handlers.NewRewardHandler(services.NewRewardService(repository.NewRewardRepository(db)))
+	rewardHandler := handlers.NewRewardHandler(rewardService)
+	drawSchedulerHandler := handlers.NewDrawSchedulerHandler(drawScheduler)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	walletHandler := handlers.NewWalletHandler(walletService)
+	settlementHandler := handlers.NewSettlementHandler(settlementService)
+	couponHandler := handlers.NewCouponHandler(couponService)
+	referralHandler := handlers.NewReferralHandler(referralService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	claimHandler := handlers.NewPrizeClaimHandler(claimService)
 
 	// Configurar Gin
 	if cfg.API.Mode == "release" {
@@ -68,7 +134,7 @@ func main() {
 	router := gin.Default()
 
 	// Configurar rotas
-	routes.SetupRoutes(router, userHandler, rewardHandler, cfg.JWT.Secret)
+	routes.SetupRoutes(router, userHandler, rewardHandler, drawSchedulerHandler, paymentHandler, walletHandler, settlementHandler, couponHandler, referralHandler, notificationHandler, claimHandler, cfg.JWT.Secret)
 
 	// Iniciar servidor
 	port := os.Getenv("API_PORT")
@@ -76,7 +142,37 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Servidor iniciado na porta %s", port)
-	log.Printf("Acesse http://localhost:%s/swagger/index.html para a documentação", port)
-	log.Fatal(router.Run(":" + port))
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
+	// Encerrar com SIGINT/SIGTERM para que as tarefas em segundo plano terminem o ciclo em andamento
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Servidor iniciado na porta %s", port)
+		log.Printf("Acesse http://localhost:%s/swagger/index.html para a documentação", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("erro no servidor: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		log.Println("Encerrando o servidor...")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("erro ao encerrar o servidor: %w", err)
+	}
+
+	return nil
 }
@@ -3,15 +3,23 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/cauamistura/BNUPremios/internal/money"
 	"github.com/joho/godotenv"
 )
 
 // Config representa as configurações da aplicação
 type Config struct {
-	Database DatabaseConfig
-	API      APIConfig
-	JWT      JWTConfig
+	Database    DatabaseConfig
+	API         APIConfig
+	JWT         JWTConfig
+	Draw        DrawConfig
+	Reservation ReservationConfig
+	Payment     PaymentConfig
+	Settlement  SettlementConfig
+	Referral    ReferralConfig
 }
 
 // DatabaseConfig representa as configurações do banco de dados
@@ -35,6 +43,44 @@ type JWTConfig struct {
 	Secret string
 }
 
+// DrawConfig representa as configurações do agendador de sorteios
+type DrawConfig struct {
+	SchedulerEnabled bool
+	Interval         time.Duration
+	MaxAttempts      int
+	MaxPostponements int
+	ClaimDeadline    time.Duration
+}
+
+// ReservationConfig representa as configurações das reservas de números
+type ReservationConfig struct {
+	TTL           time.Duration
+	SweepInterval time.Duration
+	DirectPixTTL  time.Duration
+}
+
+// PaymentConfig representa as configurações do provedor de pagamento
+type PaymentConfig struct {
+	Provider        string
+	WebhookSecret   string
+	FakeAutoApprove bool
+	PixGatewayURL   string
+	PixGatewayToken string
+	PixKey          string
+}
+
+// SettlementConfig representa as configurações dos repasses aos organizadores
+type SettlementConfig struct {
+	DefaultFeePercent float64
+}
+
+// ReferralConfig representa as configurações do bônus do programa de indicação
+type ReferralConfig struct {
+	RewardType   string
+	BonusNumbers int
+	CreditAmount money.Money
+}
+
 // Load carrega as configurações da aplicação
 func Load() *Config {
 	// Carregar arquivo .env
@@ -58,6 +104,34 @@ func Load() *Config {
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", "your-secret-key-here"),
 		},
+		Draw: DrawConfig{
+			SchedulerEnabled: getEnvBool("DRAW_SCHEDULER_ENABLED", true),
+			Interval:         getEnvDuration("DRAW_SCHEDULER_INTERVAL", time.Minute),
+			MaxAttempts:      getEnvInt("DRAW_SCHEDULER_MAX_ATTEMPTS", 5),
+			MaxPostponements: getEnvInt("DRAW_MAX_POSTPONEMENTS", 2),
+			ClaimDeadline:    getEnvDuration("PRIZE_CLAIM_DEADLINE", 7*24*time.Hour),
+		},
+		Reservation: ReservationConfig{
+			TTL:           getEnvDuration("RESERVATION_TTL", 15*time.Minute),
+			SweepInterval: getEnvDuration("RESERVATION_SWEEP_INTERVAL", time.Minute),
+			DirectPixTTL:  getEnvDuration("RESERVATION_DIRECT_PIX_TTL", 48*time.Hour),
+		},
+		Payment: PaymentConfig{
+			Provider:        getEnv("PAYMENT_PROVIDER", "fake"),
+			WebhookSecret:   getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+			FakeAutoApprove: getEnvBool("PAYMENT_FAKE_AUTO_APPROVE", true),
+			PixGatewayURL:   getEnv("PIX_GATEWAY_URL", ""),
+			PixGatewayToken: getEnv("PIX_GATEWAY_TOKEN", ""),
+			PixKey:          getEnv("PIX_KEY", ""),
+		},
+		Settlement: SettlementConfig{
+			DefaultFeePercent: getEnvFloat("PLATFORM_FEE_PERCENT", 10),
+		},
+		Referral: ReferralConfig{
+			RewardType:   getEnv("REFERRAL_REWARD_TYPE", "bonus_numbers"),
+			BonusNumbers: getEnvInt("REFERRAL_BONUS_NUMBERS", 1),
+			CreditAmount: getEnvMoney("REFERRAL_CREDIT_AMOUNT", money.FromCents(500)),
+		},
 	}
 }
 
@@ -67,4 +141,44 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
+}
+
+// getEnvBool obtém uma variável de ambiente booleana ou retorna um valor padrão
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt obtém uma variável de ambiente inteira ou retorna um valor padrão
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvFloat obtém uma variável de ambiente decimal ou retorna um valor padrão
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvMoney obtém uma variável de ambiente de valor em reais (ex: "5.00") ou retorna um valor padrão
+func getEnvMoney(key string, defaultValue money.Money) money.Money {
+	if value, err := money.Parse(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration obtém uma variável de ambiente de duração (ex: "30s", "5m") ou retorna um valor padrão
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
 }
\ No newline at end of file
@@ -0,0 +1,100 @@
+package draw
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Algorithm descreve como os números vencedores são derivados, para quem quiser verificar o sorteio
+const Algorithm = "seed_hash = sha256(seed); numbers_digest = sha256(números vendidos em ordem crescente separados por vírgula); " +
+	"na rodada 0: winner_index = int(sha256(seed + \":\" + numbers_digest)) mod total_restante; " +
+	"na rodada k > 0: winner_index = int(sha256(seed + \":\" + numbers_digest + \":\" + k)) mod total_restante; " +
+	"winner_number = restantes[winner_index], onde restantes são os números vendidos em ordem crescente sem os vencedores das rodadas anteriores"
+
+// GenerateSeed gera uma semente secreta aleatória em hexadecimal
+func GenerateSeed() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashSeed retorna o compromisso público (sha256 em hexadecimal) de uma semente
+func HashSeed(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// NumbersDigest calcula o resumo dos números vendidos, independente da ordem recebida
+func NumbersDigest(numbers []int) string {
+	sorted := append([]int(nil), numbers...)
+	sort.Ints(sorted)
+
+	parts := make([]string, len(sorted))
+	for i, number := range sorted {
+		parts[i] = strconv.Itoa(number)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+// WinnerIndex deriva de forma determinística o índice vencedor de uma rodada a partir da semente e do resumo dos números
+func WinnerIndex(seed, digest string, round, total int) (int, error) {
+	if total <= 0 {
+		return 0, errors.New("nenhum número disponível para o sorteio")
+	}
+
+	message := seed + ":" + digest
+	if round > 0 {
+		message += ":" + strconv.Itoa(round)
+	}
+
+	sum := sha256.Sum256([]byte(message))
+	value := new(big.Int).SetBytes(sum[:])
+	index := new(big.Int).Mod(value, big.NewInt(int64(total)))
+
+	return int(index.Int64()), nil
+}
+
+// Pick representa o resultado de uma rodada do sorteio
+type Pick struct {
+	Round  int
+	Index  int
+	Number int
+}
+
+// PickWinners sorteia números distintos, um por rodada, entre os números vendidos
+func PickWinners(seed string, numbers []int, count int) ([]Pick, string, error) {
+	remaining := append([]int(nil), numbers...)
+	sort.Ints(remaining)
+
+	digest := NumbersDigest(remaining)
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+
+	picks := make([]Pick, 0, count)
+	for round := 0; round < count; round++ {
+		index, err := WinnerIndex(seed, digest, round, len(remaining))
+		if err != nil {
+			return nil, "", err
+		}
+
+		picks = append(picks, Pick{Round: round, Index: index, Number: remaining[index]})
+		remaining = append(remaining[:index], remaining[index+1:]...)
+	}
+
+	if len(picks) == 0 {
+		return nil, "", errors.New("nenhum número disponível para o sorteio")
+	}
+
+	return picks, digest, nil
+}
@@ -0,0 +1,183 @@
+package draw
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+const testSeed = "5f2b8c1e9d4a7036b1e8f2c4a9d07e13c6b5a4f3e2d1c0b9a8f7e6d5c4b3a291"
+
+func TestHashSeed(t *testing.T) {
+	tests := []struct {
+		name string
+		seed string
+		want string
+	}{
+		{name: "vetor conhecido do sha256", seed: "abc", want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{name: "semente vazia", seed: "", want: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HashSeed(tt.seed); got != tt.want {
+				t.Errorf("HashSeed(%q) = %s, esperado %s", tt.seed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSeedCommitment(t *testing.T) {
+	seed, err := GenerateSeed()
+	if err != nil {
+		t.Fatalf("GenerateSeed() erro inesperado: %v", err)
+	}
+	if len(seed) != 64 {
+		t.Errorf("GenerateSeed() = %q, esperado 64 caracteres hexadecimais", seed)
+	}
+	other, err := GenerateSeed()
+	if err != nil {
+		t.Fatalf("GenerateSeed() erro inesperado: %v", err)
+	}
+	if other == seed {
+		t.Errorf("GenerateSeed() repetiu a semente %q", seed)
+	}
+	if hash := HashSeed(seed); len(hash) != 64 || hash == HashSeed(other) {
+		t.Errorf("HashSeed(%q) = %q, esperado um compromisso próprio de 64 caracteres", seed, hash)
+	}
+}
+
+func TestNumbersDigest(t *testing.T) {
+	sum := sha256.Sum256([]byte("1,2,10"))
+	want := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		numbers []int
+	}{
+		{name: "ordem crescente", numbers: []int{1, 2, 10}},
+		{name: "ordem decrescente", numbers: []int{10, 2, 1}},
+		{name: "ordem qualquer", numbers: []int{2, 10, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NumbersDigest(tt.numbers); got != want {
+				t.Errorf("NumbersDigest(%v) = %s, esperado %s", tt.numbers, got, want)
+			}
+		})
+	}
+}
+
+func TestWinnerIndex(t *testing.T) {
+	digest := NumbersDigest([]int{1, 2, 3, 4, 5})
+
+	tests := []struct {
+		name    string
+		round   int
+		total   int
+		message string
+		wantErr bool
+	}{
+		{name: "rodada 0 sem sufixo", round: 0, total: 5, message: testSeed + ":" + digest},
+		{name: "rodada 1 com sufixo", round: 1, total: 4, message: testSeed + ":" + digest + ":1"},
+		{name: "rodada 2 com sufixo", round: 2, total: 3, message: testSeed + ":" + digest + ":2"},
+		{name: "um número", round: 0, total: 1, message: testSeed + ":" + digest},
+		{name: "sem números", round: 0, total: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WinnerIndex(testSeed, digest, tt.round, tt.total)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("WinnerIndex() = %d, esperado erro", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WinnerIndex() erro inesperado: %v", err)
+			}
+
+			// Recalcular como descrito em Algorithm
+			sum := sha256.Sum256([]byte(tt.message))
+			want := int(new(big.Int).Mod(new(big.Int).SetBytes(sum[:]), big.NewInt(int64(tt.total))).Int64())
+			if got != want {
+				t.Errorf("WinnerIndex(rodada %d, total %d) = %d, esperado %d", tt.round, tt.total, got, want)
+			}
+			if again, _ := WinnerIndex(testSeed, digest, tt.round, tt.total); again != got {
+				t.Errorf("WinnerIndex() não é determinístico: %d e %d", got, again)
+			}
+		})
+	}
+}
+
+func TestPickWinners(t *testing.T) {
+	tests := []struct {
+		name      string
+		numbers   []int
+		count     int
+		wantPicks int
+		wantErr   bool
+	}{
+		{name: "um vencedor", numbers: []int{7, 3, 42, 15}, count: 1, wantPicks: 1},
+		{name: "vários vencedores", numbers: []int{7, 3, 42, 15, 8, 21}, count: 4, wantPicks: 4},
+		{name: "mais vencedores que números", numbers: []int{5, 9}, count: 5, wantPicks: 2},
+		{name: "sem números", numbers: nil, count: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			picks, digest, err := PickWinners(testSeed, tt.numbers, tt.count)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("PickWinners() = %v, esperado erro", picks)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PickWinners() erro inesperado: %v", err)
+			}
+			if len(picks) != tt.wantPicks {
+				t.Fatalf("PickWinners() retornou %d vencedores, esperado %d", len(picks), tt.wantPicks)
+			}
+			if want := NumbersDigest(tt.numbers); digest != want {
+				t.Errorf("PickWinners() digest = %s, esperado %s", digest, want)
+			}
+
+			sold := make(map[int]bool)
+			for _, number := range tt.numbers {
+				sold[number] = true
+			}
+			seen := make(map[int]bool)
+			for i, pick := range picks {
+				if pick.Round != i {
+					t.Errorf("vencedor %d na rodada %d", i, pick.Round)
+				}
+				if !sold[pick.Number] {
+					t.Errorf("número %d não foi vendido", pick.Number)
+				}
+				if seen[pick.Number] {
+					t.Errorf("número %d sorteado mais de uma vez", pick.Number)
+				}
+				seen[pick.Number] = true
+			}
+
+			// A ordem em que os números chegam não muda o resultado
+			reversed := make([]int, len(tt.numbers))
+			for i, number := range tt.numbers {
+				reversed[len(tt.numbers)-1-i] = number
+			}
+			again, _, err := PickWinners(testSeed, reversed, tt.count)
+			if err != nil {
+				t.Fatalf("PickWinners() erro inesperado: %v", err)
+			}
+			for i := range picks {
+				if again[i] != picks[i] {
+					t.Errorf("rodada %d: %+v com os números invertidos, esperado %+v", i, again[i], picks[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cauamistura/BNUPremios/internal/middleware"
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CouponHandler implementa os handlers HTTP dos cupons de desconto
+type CouponHandler struct {
+	couponService *services.CouponService
+}
+
+// NewCouponHandler cria uma nova instância do handler de cupons
+func NewCouponHandler(couponService *services.CouponService) *CouponHandler {
+	return &CouponHandler{couponService: couponService}
+}
+
+// Create godoc
+// @Summary Criar cupom
+// @Description Cria um cupom do organizador autenticado: percentual (discount_percent), valor fixo (discount_amount) ou números bônus (bonus_numbers a cada min_quantity números). Com reward_id vale apenas para esse prêmio; sem ele, para todos os prêmios do organizador
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateCouponRequest true "Dados do cupom"
+// @Success 201 {object} models.Coupon
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /coupons [post]
+func (h *CouponHandler) Create(c *gin.Context) {
+	ownerID, ok := couponUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	coupon, err := h.couponService.Create(ownerID, &req)
+	if err != nil {
+		c.JSON(couponErrorStatus(err), gin.H{
+			"error":   "Não foi possível criar o cupom",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, coupon)
+}
+
+// ListMine godoc
+// @Summary Listar meus cupons
+// @Description Lista os cupons do organizador autenticado com a quantidade de usos (pedidos pendentes ou pagos)
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CouponListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /coupons/mine [get]
+func (h *CouponHandler) ListMine(c *gin.Context) {
+	ownerID, ok := couponUser(c)
+	if !ok {
+		return
+	}
+
+	coupons, err := h.couponService.ListMine(ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, coupons)
+}
+
+// Update godoc
+// @Summary Atualizar cupom
+// @Description Ativa ou desativa um cupom e altera seus limites de uso e sua validade (apenas o organizador)
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do cupom"
+// @Param request body models.UpdateCouponRequest true "Dados a atualizar"
+// @Success 200 {object} models.Coupon
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /coupons/{id} [put]
+func (h *CouponHandler) Update(c *gin.Context) {
+	ownerID, ok := couponUser(c)
+	if !ok {
+		return
+	}
+
+	couponID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do cupom inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	var req models.UpdateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	coupon, err := h.couponService.Update(couponID, ownerID, &req)
+	if err != nil {
+		c.JSON(couponErrorStatus(err), gin.H{
+			"error":   "Não foi possível atualizar o cupom",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, coupon)
+}
+
+// ListRedemptions godoc
+// @Summary Listar resgates do cupom
+// @Description Lista os pedidos em que o cupom foi usado, com o desconto e os números bônus de cada um (apenas o organizador)
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do cupom"
+// @Success 200 {object} models.CouponRedemptionListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /coupons/{id}/redemptions [get]
+func (h *CouponHandler) ListRedemptions(c *gin.Context) {
+	ownerID, ok := couponUser(c)
+	if !ok {
+		return
+	}
+
+	couponID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do cupom inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	redemptions, err := h.couponService.ListRedemptions(couponID, ownerID)
+	if err != nil {
+		c.JSON(couponErrorStatus(err), gin.H{
+			"error":   "Não foi possível listar os resgates",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, redemptions)
+}
+
+// couponUser obtém o organizador autenticado, respondendo 401 se não houver
+func couponUser(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// couponErrorStatus mapeia os erros do serviço de cupons para o status HTTP
+func couponErrorStatus(err error) int {
+	switch err.Error() {
+	case "prêmio não encontrado", "cupom não encontrado":
+		return http.StatusNotFound
+	case "apenas o organizador do prêmio pode realizar esta operação", "apenas o organizador do cupom pode realizar esta operação":
+		return http.StatusForbidden
+	case "já existe um cupom com este código":
+		return http.StatusConflict
+	}
+	if strings.HasPrefix(err.Error(), "erro ao") {
+		return http.StatusInternalServerError
+	}
+	return http.StatusBadRequest
+}
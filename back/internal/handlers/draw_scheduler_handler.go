@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DrawSchedulerHandler implementa os handlers HTTP do agendador de sorteios
+type DrawSchedulerHandler struct {
+	scheduler *services.DrawScheduler
+}
+
+// NewDrawSchedulerHandler cria uma nova instância do handler do agendador de sorteios
+func NewDrawSchedulerHandler(scheduler *services.DrawScheduler) *DrawSchedulerHandler {
+	return &DrawSchedulerHandler{scheduler: scheduler}
+}
+
+// Status godoc
+// @Summary Status dos sorteios automáticos
+// @Description Lista os sorteios vencidos aguardando execução e as falhas registradas para nova tentativa (requer autenticação)
+// @Tags draws
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.DrawSchedulerStatusResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /draws/status [get]
+func (h *DrawSchedulerHandler) Status(c *gin.Context) {
+	status, err := h.scheduler.Status()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
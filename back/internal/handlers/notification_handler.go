@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cauamistura/BNUPremios/internal/middleware"
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler implementa os handlers HTTP dos avisos aos usuários
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler cria uma nova instância do handler de avisos
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// ListMine godoc
+// @Summary Meus avisos
+// @Description Lista os avisos do usuário autenticado, do mais recente para o mais antigo, com o total de não lidos
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão: 1)"
+// @Param limit query int false "Limite por página (padrão: 20, máximo: 100)"
+// @Success 200 {object} models.NotificationListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notifications/mine [get]
+func (h *NotificationHandler) ListMine(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+
+	notifications, err := h.notificationService.ListMine(userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkRead godoc
+// @Summary Marcar aviso como lido
+// @Description Marca um aviso do usuário autenticado como lido
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do aviso"
+// @Success 200 {object} models.Notification
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do aviso inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	notification, err := h.notificationService.MarkRead(id, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "aviso não encontrado" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível marcar o aviso como lido",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentHandler implementa os handlers HTTP de pagamentos
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+}
+
+// NewPaymentHandler cria uma nova instância do handler de pagamentos
+func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// Webhook godoc
+// @Summary Webhook de pagamentos
+// @Description Recebe as notificações de PIX do provedor de pagamento. O corpo deve ser assinado com HMAC-SHA256 (PAYMENT_WEBHOOK_SECRET) no header X-Webhook-Signature
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Signature header string true "Assinatura HMAC-SHA256 do corpo em hexadecimal"
+// @Param request body models.PaymentWebhookRequest true "Notificação de PIX recebidos"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	settled, err := h.paymentService.HandleWebhook(body, c.GetHeader("X-Webhook-Signature"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Assinatura inválida",
+				"message": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidWebhookPayload) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Dados inválidos",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notificação processada",
+		"settled": settled,
+	})
+}
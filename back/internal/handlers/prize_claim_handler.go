@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cauamistura/BNUPremios/internal/middleware"
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PrizeClaimHandler implementa os handlers HTTP do resgate e da entrega dos prêmios sorteados
+type PrizeClaimHandler struct {
+	claimService *services.PrizeClaimService
+}
+
+// NewPrizeClaimHandler cria uma nova instância do handler de resgates
+func NewPrizeClaimHandler(claimService *services.PrizeClaimService) *PrizeClaimHandler {
+	return &PrizeClaimHandler{claimService: claimService}
+}
+
+// ListByReward godoc
+// @Summary Resgates do prêmio
+// @Description Lista os resgates de um prêmio sorteado com os dados de entrega dos ganhadores (apenas o organizador ou um administrador)
+// @Tags claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.PrizeClaimListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /rewards/{id}/claims [get]
+func (h *PrizeClaimHandler) ListByReward(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	claims, err := h.claimService.ListByReward(rewardID, userID, middleware.IsAdmin(c))
+	if err != nil {
+		c.JSON(claimErrorStatus(err), gin.H{
+			"error":   "Não foi possível listar os resgates",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// ListMine godoc
+// @Summary Meus resgates
+// @Description Lista os resgates dos prêmios ganhos pelo usuário autenticado
+// @Tags claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PrizeClaimListResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /claims/mine [get]
+func (h *PrizeClaimHandler) ListMine(c *gin.Context) {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	claims, err := h.claimService.ListMine(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// GetByID godoc
+// @Summary Buscar resgate
+// @Description Busca um resgate (o ganhador, o organizador do prêmio ou um administrador)
+// @Tags claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do resgate"
+// @Success 200 {object} models.PrizeClaim
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /claims/{id} [get]
+func (h *PrizeClaimHandler) GetByID(c *gin.Context) {
+	id, userID, ok := claimRequest(c)
+	if !ok {
+		return
+	}
+
+	claim, err := h.claimService.Get(id, userID, middleware.IsAdmin(c))
+	if err != nil {
+		c.JSON(claimErrorStatus(err), gin.H{
+			"error":   "Não foi possível buscar o resgate",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// Confirm godoc
+// @Summary Confirmar dados de entrega
+// @Description O ganhador informa os dados de entrega do prêmio dentro do prazo de resgate
+// @Tags claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do resgate"
+// @Param request body models.ConfirmClaimRequest true "Dados de entrega"
+// @Success 200 {object} models.PrizeClaim
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /claims/{id}/confirm [post]
+func (h *PrizeClaimHandler) Confirm(c *gin.Context) {
+	id, userID, ok := claimRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.ConfirmClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	claim, err := h.claimService.Confirm(id, userID, &req)
+	if err != nil {
+		c.JSON(claimErrorStatus(err), gin.H{
+			"error":   "Não foi possível confirmar o resgate",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// Ship godoc
+// @Summary Registrar envio do prêmio
+// @Description Registra o envio (com código de rastreio) ou a entrega em mãos do prêmio, com fotos de comprovação (apenas o organizador ou um administrador)
+// @Tags claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do resgate"
+// @Param request body models.ShipClaimRequest true "Forma de entrega e comprovação"
+// @Success 200 {object} models.PrizeClaim
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /claims/{id}/ship [post]
+func (h *PrizeClaimHandler) Ship(c *gin.Context) {
+	id, userID, ok := claimRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.ShipClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	claim, err := h.claimService.Ship(id, userID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		c.JSON(claimErrorStatus(err), gin.H{
+			"error":   "Não foi possível registrar o envio",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// Receive godoc
+// @Summary Confirmar recebimento do prêmio
+// @Description O ganhador confirma o recebimento do prêmio; com todos os prêmios recebidos, o prêmio passa para delivered
+// @Tags claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do resgate"
+// @Success 200 {object} models.PrizeClaim
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /claims/{id}/receive [post]
+func (h *PrizeClaimHandler) Receive(c *gin.Context) {
+	id, userID, ok := claimRequest(c)
+	if !ok {
+		return
+	}
+
+	claim, err := h.claimService.Receive(id, userID)
+	if err != nil {
+		c.JSON(claimErrorStatus(err), gin.H{
+			"error":   "Não foi possível confirmar o recebimento",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// Redraw godoc
+// @Summary Sortear novo ganhador
+// @Description Sorteia um novo ganhador para um resgate cujo prazo venceu sem os dados de entrega, na próxima rodada do sorteio verificável (apenas o organizador ou um administrador)
+// @Tags claims
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do resgate"
+// @Success 200 {object} models.RedrawClaimResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /claims/{id}/redraw [post]
+func (h *PrizeClaimHandler) Redraw(c *gin.Context) {
+	id, userID, ok := claimRequest(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.claimService.Redraw(id, userID, middleware.IsAdmin(c))
+	if err != nil {
+		c.JSON(claimErrorStatus(err), gin.H{
+			"error":   "Não foi possível sortear um novo ganhador",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// claimRequest lê o ID do resgate da rota e o usuário autenticado, respondendo o erro quando algum falta
+func claimRequest(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do resgate inválido",
+			"message": "Formato de ID inválido",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return id, userID, true
+}
+
+// claimErrorStatus traduz os erros do resgate de prêmios para o status HTTP
+func claimErrorStatus(err error) int {
+	switch err.Error() {
+	case "resgate não encontrado", "prêmio não encontrado":
+		return http.StatusNotFound
+	case "apenas o ganhador pode realizar esta operação",
+		"apenas o organizador do prêmio pode realizar esta operação":
+		return http.StatusForbidden
+	case "código de rastreio é obrigatório para envios":
+		return http.StatusBadRequest
+	case "situação do resgate não permite esta operação",
+		"dados de entrega do prêmio já confirmados",
+		"prazo de resgate do prêmio encerrado",
+		"o ganhador ainda não confirmou os dados de entrega",
+		"o prêmio ainda não foi enviado",
+		"apenas prêmios sorteados e ainda não entregues podem ser sorteados novamente",
+		"apenas resgates não confirmados pelo ganhador podem ser sorteados novamente",
+		"prazo de resgate do prêmio ainda não venceu",
+		"nenhum número disponível para um novo sorteio":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cauamistura/BNUPremios/internal/middleware"
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReferralHandler implementa os handlers HTTP do programa de indicação
+type ReferralHandler struct {
+	referralService *services.ReferralService
+}
+
+// NewReferralHandler cria uma nova instância do handler de indicações
+func NewReferralHandler(referralService *services.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralService: referralService}
+}
+
+// GetCode godoc
+// @Summary Código de indicação
+// @Description Retorna o código de indicação do usuário, gerado no primeiro acesso (apenas o próprio usuário ou um administrador)
+// @Tags referrals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.ReferralCodeResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/referral [get]
+func (h *ReferralHandler) GetCode(c *gin.Context) {
+	userID, ok := authorizeReferralAccess(c)
+	if !ok {
+		return
+	}
+
+	code, err := h.referralService.GetCode(userID)
+	if err != nil {
+		referralError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, code)
+}
+
+// GetStats godoc
+// @Summary Indicações do usuário
+// @Description Lista as indicações do usuário, quais já renderam bônus e o total de números e créditos recebidos (apenas o próprio usuário ou um administrador)
+// @Tags referrals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.ReferralStatsResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/referral/stats [get]
+func (h *ReferralHandler) GetStats(c *gin.Context) {
+	userID, ok := authorizeReferralAccess(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.referralService.GetStats(userID)
+	if err != nil {
+		referralError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// referralError responde com o status correspondente a um erro do programa de indicação
+func referralError(c *gin.Context, err error) {
+	if err.Error() == "usuário não encontrado" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Usuário não encontrado",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Erro interno do servidor",
+		"message": err.Error(),
+	})
+}
+
+// authorizeReferralAccess garante que as indicações da rota são do usuário autenticado ou que ele é administrador
+func authorizeReferralAccess(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do usuário inválido",
+			"message": "Formato de ID inválido",
+		})
+		return uuid.Nil, false
+	}
+
+	authUserID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return uuid.Nil, false
+	}
+
+	if authUserID != userID && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Acesso negado",
+			"message": "Você só pode acessar suas próprias indicações",
+		})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
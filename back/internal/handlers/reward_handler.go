@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/cauamistura/BNUPremios/internal/middleware"
 	"github.com/cauamistura/BNUPremios/internal/models"
@@ -12,6 +14,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxAvailabilityRange limita quantos números a consulta de disponibilidade percorre por vez
+const maxAvailabilityRange = 1000
+
 type RewardHandler struct {
 	rewardService *services.RewardService
 }
@@ -54,6 +59,14 @@ func (h *RewardHandler) Create(c *gin.Context) {
 
 	reward, err := h.rewardService.Create(&req, userID)
 	if err != nil {
+		var validationErr *services.RewardValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Dados inválidos",
+				"message": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Erro interno do servidor",
 			"message": err.Error(),
@@ -131,20 +144,23 @@ func (h *RewardHandler) GetDetailsByID(c *gin.Context) {
 }
 
 // List @Summary Listar prêmios
-// @Description Lista todos os prêmios com paginação (rota pública)
+// @Description Lista os prêmios publicados, com vendas encerradas, sorteados e entregues com paginação (rota pública). Rascunhos e prêmios cancelados não aparecem sem o filtro de situação
 // @Tags rewards
 // @Accept json
 // @Produce json
 // @Param page query int false "Página (padrão: 1)"
 // @Param limit query int false "Limite por página (padrão: 10, máximo: 100)"
 // @Param search query string false "Termo de busca"
+// @Param status query string false "Situação do prêmio (published, sales_closed, drawn, cancelled ou delivered)"
 // @Success 200 {object} models.RewardListResponse
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /rewards [get]
 func (h *RewardHandler) List(c *gin.Context) {
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "10")
 	search := c.Query("search")
+	status := c.Query("status")
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
@@ -156,8 +172,15 @@ func (h *RewardHandler) List(c *gin.Context) {
 		limit = 10
 	}
 
-	rewards, err := h.rewardService.List(page, limit, search)
+	rewards, err := h.rewardService.List(page, limit, search, status)
 	if err != nil {
+		if err.Error() == "situação do prêmio inválida" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Filtro inválido",
+				"message": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Erro interno do servidor",
 			"message": err.Error(),
@@ -169,7 +192,7 @@ func (h *RewardHandler) List(c *gin.Context) {
 }
 
 // Update @Summary Atualizar prêmio
-// @Description Atualiza um prêmio existente (requer autenticação)
+// @Description Atualiza um prêmio existente (apenas o organizador ou um administrador)
 // @Tags rewards
 // @Accept json
 // @Produce json
@@ -179,7 +202,9 @@ func (h *RewardHandler) List(c *gin.Context) {
 // @Success 200 {object} models.RewardResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /rewards/{id} [put]
 func (h *RewardHandler) Update(c *gin.Context) {
@@ -202,8 +227,51 @@ func (h *RewardHandler) Update(c *gin.Context) {
 		return
 	}
 
-	reward, err := h.rewardService.Update(id, &req)
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	reward, err := h.rewardService.Update(id, userID, middleware.IsAdmin(c), &req)
 	if err != nil {
+		var validationErr *services.RewardValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Dados inválidos",
+				"message": err.Error(),
+			})
+			return
+		}
+		switch err.Error() {
+		case "prêmio não encontrado":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Prêmio não encontrado",
+				"message": err.Error(),
+			})
+			return
+		case "apenas o organizador do prêmio pode realizar esta operação":
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Acesso negado",
+				"message": err.Error(),
+			})
+			return
+		case "use o adiamento do sorteio para alterar a data de um prêmio publicado":
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Data do sorteio não pode ser editada",
+				"message": err.Error(),
+			})
+			return
+		case "não é possível editar um prêmio que já foi sorteado", "não é possível editar um prêmio cancelado":
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Prêmio não pode ser editado",
+				"message": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Erro interno do servidor",
 			"message": err.Error(),
@@ -215,7 +283,7 @@ func (h *RewardHandler) Update(c *gin.Context) {
 }
 
 // Delete @Summary Deletar prêmio
-// @Description Remove um prêmio do sistema (requer autenticação)
+// @Description Remove um prêmio do sistema (apenas o organizador ou um administrador)
 // @Tags rewards
 // @Accept json
 // @Produce json
@@ -224,7 +292,9 @@ func (h *RewardHandler) Update(c *gin.Context) {
 // @Success 204 "No Content"
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /rewards/{id} [delete]
 func (h *RewardHandler) Delete(c *gin.Context) {
@@ -238,7 +308,37 @@ func (h *RewardHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.rewardService.Delete(id); err != nil {
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	if err := h.rewardService.Delete(id, userID, middleware.IsAdmin(c)); err != nil {
+		if err.Error() == "apenas o organizador do prêmio pode realizar esta operação" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Acesso negado",
+				"message": err.Error(),
+			})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "não é possível deletar") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Prêmio não pode ser deletado",
+				"message": err.Error(),
+			})
+			return
+		}
+		if err.Error() == "prêmio não encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Prêmio não encontrado",
+				"message": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Erro interno do servidor",
 			"message": err.Error(),
@@ -249,20 +349,25 @@ func (h *RewardHandler) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-// AddBuyer @Summary Comprar números do prêmio
-// @Description Compra uma quantidade específica de números para um usuário (requer autenticação)
+// AddBuyer @Summary Reservar números do prêmio
+// @Description Reserva números para um usuário por tempo limitado; a compra é concluída na confirmação da reserva (requer autenticação). Com payment_method "wallet" o pedido é pago na hora com o saldo da carteira. Com coupon_code aplica um cupom do organizador (desconto ou números bônus). Com package_id o pedido é cobrado pelo preço do pacote
 // @Tags rewards
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID do prêmio"
 // @Param user_id path string true "ID do usuário"
-// @Param request body models.BuyNumbersRequest true "Quantidade de números"
+// @Param request body models.BuyNumbersRequest true "Quantidade de números ou números escolhidos"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 402 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
 // @Router /rewards/{id}/buyers/{user_id} [post]
 func (h *RewardHandler) AddBuyer(c *gin.Context) {
 	rewardIDStr := c.Param("id")
@@ -286,18 +391,68 @@ func (h *RewardHandler) AddBuyer(c *gin.Context) {
 		return
 	}
 
-	// Pegar a quantidade do body da requisição
+	actorID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	// Pegar a quantidade ou os números escolhidos do body da requisição
 	var req models.BuyNumbersRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Quantidade é obrigatória",
+			"error":   "Dados inválidos",
 			"message": err.Error(),
 		})
 		return
 	}
 
-	numbers, err := h.rewardService.BuyNumbers(rewardID, userID, req.Quantity)
+	purchase, instantPrizes, err := h.rewardService.BuyNumbers(rewardID, userID, actorID, middleware.IsAdmin(c), &req)
 	if err != nil {
+		if err.Error() == "apenas o próprio usuário ou um administrador pode comprar em seu nome" ||
+			err.Error() == "o pagamento com a carteira só pode ser feito pelo próprio comprador" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Acesso negado",
+				"message": err.Error(),
+			})
+			return
+		}
+		var unavailable *models.UnavailableNumbersError
+		if errors.As(err, &unavailable) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Números indisponíveis",
+				"message": err.Error(),
+				"numbers": unavailable.Numbers,
+			})
+			return
+		}
+		var violations *models.PurchaseRulesError
+		if errors.As(err, &violations) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":      "Regras de compra não atendidas",
+				"message":    err.Error(),
+				"violations": violations.Violations,
+			})
+			return
+		}
+		var couponErr *models.CouponError
+		if errors.As(err, &couponErr) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "Cupom inválido",
+				"message": err.Error(),
+			})
+			return
+		}
+		if err.Error() == "prêmio não encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Prêmio não encontrado",
+				"message": err.Error(),
+			})
+			return
+		}
 		if err.Error() == "não é possível comprar números de um prêmio já completado" {
 			c.JSON(http.StatusConflict, gin.H{
 				"error":   "Prêmio já completado",
@@ -305,31 +460,101 @@ func (h *RewardHandler) AddBuyer(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro interno do servidor",
+		if err.Error() == "prazo de vendas do prêmio encerrado" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Vendas encerradas",
+				"message": err.Error(),
+			})
+			return
+		}
+		if err.Error() == "prêmio ainda não está à venda" || err.Error() == "vendas do prêmio encerradas" || err.Error() == "prêmio cancelado" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Prêmio fora de venda",
+				"message": err.Error(),
+			})
+			return
+		}
+		if err.Error() == "pacote não encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Pacote não encontrado",
+				"message": err.Error(),
+			})
+			return
+		}
+		if err.Error() == "prêmio esgotado" || err.Error() == "quantidade solicitada excede os números disponíveis" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Números indisponíveis",
+				"message": err.Error(),
+			})
+			return
+		}
+		if err.Error() == "saldo insuficiente na carteira" {
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":   "Saldo insuficiente",
+				"message": err.Error(),
+			})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "erro ao criar cobrança") {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":   "Não foi possível criar a cobrança",
+				"message": err.Error(),
+			})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "erro ao comprar números") || strings.HasPrefix(err.Error(), "erro ao confirmar compra") ||
+			strings.HasPrefix(err.Error(), "erro ao pagar com a carteira") || strings.HasPrefix(err.Error(), "erro ao buscar pacote") {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Erro interno do servidor",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
 			"message": err.Error(),
 		})
 		return
 	}
 
+	message := "Números reservados. Conclua o pagamento antes do fim do prazo da reserva"
+	if purchase.PaymentStatus == models.PaymentPaid {
+		message = "Números comprados com sucesso"
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message":  "Números comprados com sucesso",
-		"numbers":  numbers,
-		"quantity": req.Quantity,
+		"message":          message,
+		"purchase_id":      purchase.ID,
+		"numbers":          purchase.Numbers,
+		"quantity":         purchase.Quantity,
+		"total_amount":     purchase.TotalAmount,
+		"discount_amount":  purchase.DiscountAmount,
+		"bonus_numbers":    purchase.BonusNumbers,
+		"coupon_code":      purchase.CouponCode,
+		"package_id":       purchase.PackageID,
+		"payment_status":   purchase.PaymentStatus,
+		"payment_provider": purchase.PaymentProvider,
+		"pix_copy_paste":   purchase.PixCopyPaste,
+		"expires_at":       purchase.ExpiresAt,
+		"instant_prizes":   instantPrizes,
 	})
 }
 
 // RemoveBuyer @Summary Remover comprador do prêmio
-// @Description Remove um usuário como comprador de um prêmio (requer autenticação)
+// @Description Cancela todos os pedidos pendentes ou pagos de um comprador no prêmio, devolvendo os números ao conjunto e os valores pagos (apenas o próprio comprador ou um administrador). Os pedidos continuam registrados como cancelados ou devolvidos
 // @Tags rewards
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID do prêmio"
 // @Param user_id path string true "ID do usuário"
+// @Param reason query string false "Motivo do cancelamento"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /rewards/{id}/buyers/{user_id} [delete]
 func (h *RewardHandler) RemoveBuyer(c *gin.Context) {
@@ -354,16 +579,32 @@ func (h *RewardHandler) RemoveBuyer(c *gin.Context) {
 		return
 	}
 
-	if err := h.rewardService.RemoveBuyer(rewardID, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro interno do servidor",
-			"message": err.Error(),
+	actorID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	cancelled, err := h.rewardService.RemoveBuyer(rewardID, userID, actorID, middleware.IsAdmin(c), c.Query("reason"))
+	if err != nil {
+		status := cancelPurchaseErrorStatus(err)
+		if err.Error() == "comprador não possui compras neste prêmio" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":     "Não foi possível remover o comprador",
+			"message":   err.Error(),
+			"cancelled": cancelled,
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Comprador removido com sucesso",
+		"message":   "Comprador removido com sucesso",
+		"cancelled": cancelled,
 	})
 }
 
@@ -554,13 +795,16 @@ func (h *RewardHandler) ListMyRewards(c *gin.Context) {
 
 // Draw realiza o sorteio de um prêmio
 // @Summary Realizar sorteio de um prêmio
-// @Description Realiza o sorteio aleatório de um prêmio baseado nos números comprados
+// @Description Realiza o sorteio aleatório de um prêmio com as vendas encerradas e a data do sorteio atingida (apenas o organizador ou um administrador)
 // @Tags rewards
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path string true "ID do prêmio"
 // @Success 200 {object} models.DrawRewardResponse
 // @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 409 {object} map[string]interface{}
 // @Router /rewards/{id}/draw [post]
@@ -576,16 +820,62 @@ func (h *RewardHandler) Draw(c *gin.Context) {
 		return
 	}
 
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
 	// Realizar o sorteio
-	result, err := h.rewardService.Draw(id)
+	result, err := h.rewardService.ManualDraw(id, userID, middleware.IsAdmin(c))
 	if err != nil {
-		if err.Error() == "prêmio já foi sorteado" {
+		switch err.Error() {
+		case "prêmio não encontrado":
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Prêmio não encontrado",
+				"message": err.Error(),
+			})
+			return
+		case "apenas o organizador do prêmio pode realizar esta operação":
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Acesso negado",
+				"message": err.Error(),
+			})
+			return
+		case "a data do sorteio ainda não chegou":
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Prêmio não pode ser sorteado",
+				"message": err.Error(),
+			})
+			return
+		}
+		if strings.HasSuffix(err.Error(), "prêmio já foi sorteado") {
 			c.JSON(http.StatusConflict, gin.H{
 				"error":   "Sorteio já realizado",
 				"message": err.Error(),
 			})
 			return
 		}
+		var statusErr *models.RewardStatusError
+		if errors.As(err, &statusErr) || strings.HasSuffix(err.Error(), "prazo de vendas do prêmio ainda não encerrado") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Prêmio não pode ser sorteado",
+				"message": err.Error(),
+			})
+			return
+		}
+		var belowMinimum *models.MinimumSalesError
+		if errors.As(err, &belowMinimum) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     "Vendas abaixo do mínimo",
+				"message":   err.Error(),
+				"cancelled": belowMinimum.Cancelled,
+			})
+			return
+		}
 		if err.Error() == "nenhum número foi comprado para este prêmio" {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":   "Nenhum número comprado",
@@ -602,3 +892,706 @@ func (h *RewardHandler) Draw(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// GetDrawProof busca a prova pública do sorteio de um prêmio
+// @Summary Prova do sorteio
+// @Description Retorna o hash publicado da semente, a semente revelada e o resumo dos números vendidos para que qualquer pessoa possa recalcular o número vencedor (rota pública)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.DrawProofResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /rewards/{id}/draw/proof [get]
+func (h *RewardHandler) GetDrawProof(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	proof, err := h.rewardService.GetDrawProof(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Prêmio não encontrado",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, proof)
+}
+
+// AddInstantPrizes @Summary Cadastrar cotas premiadas
+// @Description Cadastra números ocultos que dão um prêmio instantâneo a quem os comprar (apenas o organizador do prêmio)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Param request body models.CreateInstantPrizesRequest true "Cotas premiadas"
+// @Success 201 {array} models.InstantPrize
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /rewards/{id}/instant-prizes [post]
+func (h *RewardHandler) AddInstantPrizes(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	var req models.CreateInstantPrizesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	prizes, err := h.rewardService.AddInstantPrizes(rewardID, userID, &req)
+	if err != nil {
+		status := http.StatusConflict
+		switch err.Error() {
+		case "prêmio não encontrado":
+			status = http.StatusNotFound
+		case "apenas o organizador do prêmio pode realizar esta operação":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível cadastrar as cotas premiadas",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, prizes)
+}
+
+// ListInstantPrizes @Summary Listar cotas premiadas
+// @Description Lista as cotas premiadas de um prêmio como resgatadas ou não, sem revelar os números ainda ocultos (rota pública)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.InstantPrizeListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /rewards/{id}/instant-prizes [get]
+func (h *RewardHandler) ListInstantPrizes(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	prizes, err := h.rewardService.ListInstantPrizes(rewardID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "prêmio não encontrado" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Erro ao buscar cotas premiadas",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, prizes)
+}
+
+// AddPackage @Summary Cadastrar pacote de números
+// @Description Cadastra um pacote com quantidade fixa de números e preço próprio (apenas o organizador do prêmio)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Param request body models.CreateRewardPackageRequest true "Pacote"
+// @Success 201 {object} models.RewardPackage
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /rewards/{id}/packages [post]
+func (h *RewardHandler) AddPackage(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	var req models.CreateRewardPackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	pkg, err := h.rewardService.AddPackage(rewardID, userID, &req)
+	if err != nil {
+		status := http.StatusConflict
+		switch err.Error() {
+		case "prêmio não encontrado":
+			status = http.StatusNotFound
+		case "apenas o organizador do prêmio pode realizar esta operação":
+			status = http.StatusForbidden
+		case "quantidade do pacote excede os números do prêmio":
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível cadastrar o pacote",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pkg)
+}
+
+// RemovePackage @Summary Remover pacote de números
+// @Description Retira um pacote da venda; pedidos já feitos com ele não são alterados (apenas o organizador do prêmio)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Param package_id path string true "ID do pacote"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /rewards/{id}/packages/{package_id} [delete]
+func (h *RewardHandler) RemovePackage(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	packageID, err := uuid.Parse(c.Param("package_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do pacote inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	if err := h.rewardService.RemovePackage(rewardID, packageID, userID); err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "prêmio não encontrado", "pacote não encontrado":
+			status = http.StatusNotFound
+		case "apenas o organizador do prêmio pode realizar esta operação":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível remover o pacote",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pacote removido com sucesso",
+	})
+}
+
+// ListPackages @Summary Listar pacotes de números
+// @Description Lista os pacotes à venda de um prêmio, do menor para o maior (rota pública)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.RewardPackageListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /rewards/{id}/packages [get]
+func (h *RewardHandler) ListPackages(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	packages, err := h.rewardService.ListPackages(rewardID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "prêmio não encontrado" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "Erro ao buscar pacotes",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, packages)
+}
+
+// GetNumberAvailability @Summary Consultar números disponíveis
+// @Description Lista os números livres de um prêmio em um intervalo, para o comprador escolher seus números (rota pública)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do prêmio"
+// @Param from query int false "Primeiro número do intervalo" default(1)
+// @Param to query int false "Último número do intervalo (no máximo 1000 números por consulta)"
+// @Success 200 {object} models.NumberAvailabilityResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /rewards/{id}/numbers [get]
+func (h *RewardHandler) GetNumberAvailability(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	from, err := strconv.Atoi(c.DefaultQuery("from", "1"))
+	if err != nil || from < 1 {
+		from = 1
+	}
+
+	to, err := strconv.Atoi(c.DefaultQuery("to", strconv.Itoa(from+maxAvailabilityRange-1)))
+	if err != nil || to < from || to-from+1 > maxAvailabilityRange {
+		to = from + maxAvailabilityRange - 1
+	}
+
+	availability, err := h.rewardService.GetNumberAvailability(rewardID, from, to)
+	if err != nil {
+		if err.Error() == "prêmio não encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Prêmio não encontrado",
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// GetPurchase @Summary Buscar compra
+// @Description Busca um pedido de compra do usuário autenticado
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da compra"
+// @Success 200 {object} models.Purchase
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /purchases/{id} [get]
+func (h *RewardHandler) GetPurchase(c *gin.Context) {
+	purchaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID da compra inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	purchase, err := h.rewardService.GetPurchase(purchaseID, userID)
+	if err != nil {
+		status := http.StatusNotFound
+		if err.Error() == "compra pertence a outro usuário" {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível buscar a compra",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, purchase)
+}
+
+// GetPurchasePix @Summary Código PIX da compra
+// @Description Retorna o código PIX "copia e cola" (BR Code) para pagamento de um pedido pendente (apenas o usuário que comprou)
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da compra"
+// @Success 200 {object} models.PurchasePixResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /purchases/{id}/pix [get]
+func (h *RewardHandler) GetPurchasePix(c *gin.Context) {
+	purchaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID da compra inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	code, err := h.rewardService.GetPurchasePix(purchaseID, userID)
+	if err != nil {
+		c.JSON(purchasePixErrorStatus(err), gin.H{
+			"error":   "Não foi possível buscar o código PIX",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, code)
+}
+
+// GetPurchasePixQRCode @Summary QR Code PIX da compra
+// @Description Retorna a imagem PNG do QR Code PIX para pagamento de um pedido pendente (apenas o usuário que comprou)
+// @Tags purchases
+// @Produce png
+// @Security BearerAuth
+// @Param id path string true "ID da compra"
+// @Param size query int false "Largura da imagem em pixels (padrão: 256, entre 128 e 1024)"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /purchases/{id}/pix/qrcode [get]
+func (h *RewardHandler) GetPurchasePixQRCode(c *gin.Context) {
+	purchaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID da compra inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	size, err := strconv.Atoi(c.DefaultQuery("size", "256"))
+	if err != nil || size < 128 || size > 1024 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros inválidos",
+			"message": "size deve estar entre 128 e 1024",
+		})
+		return
+	}
+
+	png, err := h.rewardService.GetPurchasePixQRCode(purchaseID, userID, size)
+	if err != nil {
+		c.JSON(purchasePixErrorStatus(err), gin.H{
+			"error":   "Não foi possível gerar o QR Code PIX",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// purchasePixErrorStatus mapeia os erros da consulta do código PIX de uma compra para o status HTTP
+func purchasePixErrorStatus(err error) int {
+	switch err.Error() {
+	case "compra não encontrada", "compra não possui código PIX":
+		return http.StatusNotFound
+	case "compra pertence a outro usuário":
+		return http.StatusForbidden
+	case "compra não está pendente":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// CancelPurchase @Summary Cancelar compra
+// @Description Cancela um pedido pendente ou pago e devolve seus números ao conjunto (apenas o usuário que comprou ou um administrador). Pedidos pagos só podem ser cancelados antes do sorteio e geram uma devolução do valor pelo provedor de pagamento
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da compra"
+// @Param request body models.CancelPurchaseRequest false "Motivo do cancelamento"
+// @Success 200 {object} models.CancelPurchaseResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /purchases/{id}/cancel [post]
+func (h *RewardHandler) CancelPurchase(c *gin.Context) {
+	purchaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID da compra inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	// O motivo é opcional
+	var req models.CancelPurchaseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Dados inválidos",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	result, err := h.rewardService.CancelPurchase(purchaseID, userID, middleware.IsAdmin(c), req.Reason)
+	if err != nil {
+		c.JSON(cancelPurchaseErrorStatus(err), gin.H{
+			"error":   "Não foi possível cancelar a compra",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListPurchaseRefunds @Summary Listar devoluções da compra
+// @Description Lista as devoluções de um pedido e suas situações (apenas o usuário que comprou ou um administrador)
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da compra"
+// @Success 200 {object} models.PurchaseRefundListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /purchases/{id}/refunds [get]
+func (h *RewardHandler) ListPurchaseRefunds(c *gin.Context) {
+	purchaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID da compra inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	refunds, err := h.rewardService.ListPurchaseRefunds(purchaseID, userID, middleware.IsAdmin(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "compra não encontrada":
+			status = http.StatusNotFound
+		case "compra pertence a outro usuário":
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível buscar as devoluções",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, refunds)
+}
+
+// cancelPurchaseErrorStatus mapeia os erros do cancelamento de compras para o status HTTP
+func cancelPurchaseErrorStatus(err error) int {
+	switch err.Error() {
+	case "compra não encontrada":
+		return http.StatusNotFound
+	case "apenas o comprador ou um administrador pode cancelar a compra":
+		return http.StatusForbidden
+	case "compra não pode ser cancelada",
+		"não é possível cancelar compras de um prêmio já sorteado",
+		"compras com cota premiada resgatada não podem ser canceladas":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// ConfirmPurchase @Summary Confirmar compra
+// @Description Consulta o pagamento de um pedido pendente no provedor e, se confirmado, conclui a compra (apenas o usuário que comprou). Pedidos pagos por PIX direto na chave do organizador são confirmados pelo organizador do prêmio
+// @Tags purchases
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da compra"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /purchases/{id}/confirm [post]
+func (h *RewardHandler) ConfirmPurchase(c *gin.Context) {
+	purchaseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID da compra inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	result, err := h.rewardService.ConfirmPurchase(purchaseID, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "compra não encontrada":
+			status = http.StatusNotFound
+		case "compra pertence a outro usuário":
+			status = http.StatusForbidden
+		case "reserva expirada", "pagamento recebido após a liberação dos números; devolva o valor ao comprador":
+			status = http.StatusGone
+		case "compra não está pendente", "pagamento ainda não confirmado", "pagamento aguardando confirmação do organizador",
+			"não é possível comprar números de um prêmio já completado", "prêmio ainda não está à venda", "prêmio cancelado":
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível confirmar a compra",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	message := "Números comprados com sucesso"
+	if len(result.InstantPrizes) > 0 {
+		message = "Números comprados com sucesso. Você encontrou uma cota premiada!"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        message,
+		"purchase_id":    result.PurchaseID,
+		"numbers":        result.Numbers,
+		"quantity":       len(result.Numbers),
+		"instant_prizes": result.InstantPrizes,
+	})
+}
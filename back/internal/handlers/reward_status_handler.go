@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cauamistura/BNUPremios/internal/middleware"
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Publish @Summary Publicar prêmio
+// @Description Coloca um prêmio em rascunho à venda (apenas o organizador ou um administrador)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.RewardResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /rewards/{id}/publish [post]
+func (h *RewardHandler) Publish(c *gin.Context) {
+	h.changeStatus(c, "Não foi possível publicar o prêmio", h.rewardService.Publish)
+}
+
+// CloseSales @Summary Encerrar vendas do prêmio
+// @Description Encerra as vendas de um prêmio publicado; reservas já feitas ainda podem ser pagas (apenas o organizador ou um administrador)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.RewardResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /rewards/{id}/close-sales [post]
+func (h *RewardHandler) CloseSales(c *gin.Context) {
+	h.changeStatus(c, "Não foi possível encerrar as vendas", h.rewardService.CloseSales)
+}
+
+// ReopenSales @Summary Reabrir vendas do prêmio
+// @Description Volta a vender números de um prêmio com vendas encerradas (apenas o organizador ou um administrador)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.RewardResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /rewards/{id}/reopen-sales [post]
+func (h *RewardHandler) ReopenSales(c *gin.Context) {
+	h.changeStatus(c, "Não foi possível reabrir as vendas", h.rewardService.ReopenSales)
+}
+
+// Deliver @Summary Registrar entrega do prêmio
+// @Description Registra que o prêmio sorteado foi entregue aos ganhadores depois que todos os resgates foram recebidos ou perdidos (apenas o organizador ou um administrador)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Success 200 {object} models.RewardResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /rewards/{id}/deliver [post]
+func (h *RewardHandler) Deliver(c *gin.Context) {
+	h.changeStatus(c, "Não foi possível registrar a entrega", h.rewardService.MarkDelivered)
+}
+
+// Cancel @Summary Cancelar prêmio
+// @Description Cancela um prêmio ainda não sorteado, cancelando os pedidos pendentes e devolvendo os pagos (apenas o organizador ou um administrador)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Param request body models.CancelRewardRequest false "Motivo do cancelamento"
+// @Success 200 {object} models.CancelRewardResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /rewards/{id}/cancel [post]
+func (h *RewardHandler) Cancel(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	// O motivo é opcional
+	var req models.CancelRewardRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Dados inválidos",
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
+	result, err := h.rewardService.Cancel(rewardID, userID, middleware.IsAdmin(c), req.Reason)
+	if err != nil {
+		c.JSON(rewardStatusErrorStatus(err), gin.H{
+			"error":   "Não foi possível cancelar o prêmio",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Postpone @Summary Adiar sorteio do prêmio
+// @Description Adia o sorteio de um prêmio publicado para uma nova data, registrando o motivo no histórico e avisando os compradores (apenas o organizador ou um administrador)
+// @Tags rewards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Param request body models.PostponeDrawRequest true "Nova data e motivo do adiamento"
+// @Success 200 {object} models.PostponeDrawResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /rewards/{id}/postpone [post]
+func (h *RewardHandler) Postpone(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	var req models.PostponeDrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.rewardService.Postpone(rewardID, userID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		status := rewardStatusErrorStatus(err)
+		switch err.Error() {
+		case "a nova data do sorteio deve estar no futuro",
+			"a nova data do sorteio deve ser posterior à data atual":
+			status = http.StatusBadRequest
+		case "limite de adiamentos do sorteio atingido",
+			"a data do sorteio de um rascunho é alterada pela edição do prêmio",
+			"não é possível adiar o sorteio de um prêmio cancelado",
+			"não é possível adiar o sorteio de um prêmio já sorteado":
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível adiar o sorteio",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// changeStatus executa uma mudança de situação do prêmio informado na rota em nome do usuário autenticado
+func (h *RewardHandler) changeStatus(c *gin.Context, failure string, change func(rewardID, actorID uuid.UUID, isAdmin bool) (*models.RewardResponse, error)) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	userID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	reward, err := change(rewardID, userID, middleware.IsAdmin(c))
+	if err != nil {
+		c.JSON(rewardStatusErrorStatus(err), gin.H{
+			"error":   failure,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, reward)
+}
+
+// rewardStatusErrorStatus traduz os erros das mudanças de situação do prêmio para o status HTTP
+func rewardStatusErrorStatus(err error) int {
+	var statusErr *models.RewardStatusError
+	if errors.As(err, &statusErr) {
+		return http.StatusConflict
+	}
+	switch err.Error() {
+	case "prêmio não encontrado":
+		return http.StatusNotFound
+	case "apenas o organizador do prêmio pode realizar esta operação":
+		return http.StatusForbidden
+	case "prazo de vendas do prêmio encerrado",
+		"há resgates do prêmio ainda não recebidos pelos ganhadores":
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
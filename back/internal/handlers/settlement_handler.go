@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/cauamistura/BNUPremios/internal/middleware"
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SettlementHandler implementa os handlers HTTP dos repasses aos organizadores
+type SettlementHandler struct {
+	settlementService *services.SettlementService
+}
+
+// NewSettlementHandler cria uma nova instância do handler de repasses
+func NewSettlementHandler(settlementService *services.SettlementService) *SettlementHandler {
+	return &SettlementHandler{settlementService: settlementService}
+}
+
+// ListMine godoc
+// @Summary Relatório de repasses
+// @Description Lista os repasses do organizador autenticado (pendentes e pagos) com o valor bruto, a taxa da plataforma e o valor líquido de cada prêmio sorteado, além do valor em andamento dos prêmios ainda não sorteados
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PayoutReportResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /settlements/mine [get]
+func (h *SettlementHandler) ListMine(c *gin.Context) {
+	ownerID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return
+	}
+
+	report, err := h.settlementService.GetPayoutReport(ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// MarkPaid godoc
+// @Summary Registrar pagamento de repasse
+// @Description Marca um repasse pendente como pago ao organizador, com a referência da transferência (apenas administradores)
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do repasse"
+// @Param request body models.MarkSettlementPaidRequest true "Referência do pagamento"
+// @Success 200 {object} models.RewardSettlement
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /settlements/{id}/pay [post]
+func (h *SettlementHandler) MarkPaid(c *gin.Context) {
+	settlementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do repasse inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	var req models.MarkSettlementPaidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	settlement, err := h.settlementService.MarkPaid(settlementID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "apenas administradores podem realizar esta operação":
+			status = http.StatusForbidden
+		case "repasse não encontrado":
+			status = http.StatusNotFound
+		case "repasse já foi pago":
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   "Não foi possível registrar o pagamento do repasse",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settlement)
+}
+
+// SetRewardFee godoc
+// @Summary Definir taxa da plataforma do prêmio
+// @Description Define o percentual da taxa da plataforma de um prêmio, que prevalece sobre a do organizador e a padrão. Sem platform_fee_percent a taxa específica é removida (apenas administradores)
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do prêmio"
+// @Param request body models.UpdatePlatformFeeRequest true "Taxa da plataforma"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /rewards/{id}/platform-fee [put]
+func (h *SettlementHandler) SetRewardFee(c *gin.Context) {
+	rewardID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do prêmio inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	var req models.UpdatePlatformFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := h.settlementService.SetRewardFee(rewardID, middleware.IsAdmin(c), &req); err != nil {
+		c.JSON(platformFeeErrorStatus(err), gin.H{
+			"error":   "Não foi possível definir a taxa",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":              "Taxa da plataforma atualizada",
+		"platform_fee_percent": req.PlatformFeePercent,
+	})
+}
+
+// SetOrganizerFee godoc
+// @Summary Definir taxa da plataforma do organizador
+// @Description Define o percentual da taxa da plataforma dos prêmios de um organizador que não têm taxa própria. Sem platform_fee_percent a taxa específica é removida (apenas administradores)
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do organizador"
+// @Param request body models.UpdatePlatformFeeRequest true "Taxa da plataforma"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/platform-fee [put]
+func (h *SettlementHandler) SetOrganizerFee(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do usuário inválido",
+			"message": "Formato de ID inválido",
+		})
+		return
+	}
+
+	var req models.UpdatePlatformFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := h.settlementService.SetOrganizerFee(userID, middleware.IsAdmin(c), &req); err != nil {
+		c.JSON(platformFeeErrorStatus(err), gin.H{
+			"error":   "Não foi possível definir a taxa",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":              "Taxa da plataforma atualizada",
+		"platform_fee_percent": req.PlatformFeePercent,
+	})
+}
+
+// platformFeeErrorStatus mapeia os erros da definição de taxas para o status HTTP
+func platformFeeErrorStatus(err error) int {
+	switch err.Error() {
+	case "apenas administradores podem realizar esta operação":
+		return http.StatusForbidden
+	case "prêmio não encontrado", "usuário não encontrado":
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/cauamistura/BNUPremios/internal/middleware"
 	"github.com/cauamistura/BNUPremios/internal/models"
 	"github.com/cauamistura/BNUPremios/internal/services"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // UserHandler implementa os handlers HTTP para usuários
@@ -128,6 +131,119 @@ func (h *UserHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// GetPixSettings godoc
+// @Summary Buscar chave PIX
+// @Description Busca a chave PIX em que o usuário recebe os pagamentos dos seus prêmios (apenas o próprio usuário)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.PixSettings
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/pix-key [get]
+func (h *UserHandler) GetPixSettings(c *gin.Context) {
+	userID, ok := h.authorizeSelf(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.userService.GetPixSettings(userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err.Error() {
+		case "usuário não encontrado", "chave PIX não configurada":
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdatePixSettings godoc
+// @Summary Configurar chave PIX
+// @Description Define a chave PIX (CPF, CNPJ, e-mail, telefone +55 ou chave aleatória) e a cidade usadas nos códigos PIX dos prêmios do usuário (apenas o próprio usuário)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Param request body models.UpdatePixSettingsRequest true "Chave PIX"
+// @Success 200 {object} models.PixSettings
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/pix-key [put]
+func (h *UserHandler) UpdatePixSettings(c *gin.Context) {
+	userID, ok := h.authorizeSelf(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePixSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	settings, err := h.userService.UpdatePixSettings(userID, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err.Error() == "usuário não encontrado" {
+			status = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "erro ao") {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// authorizeSelf garante que o usuário autenticado é o usuário da rota
+func (h *UserHandler) authorizeSelf(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return uuid.Nil, false
+	}
+
+	authUserID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Usuário não autenticado",
+		})
+		return uuid.Nil, false
+	}
+
+	if authUserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Você só pode acessar sua própria chave PIX",
+		})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
 // Delete godoc
 // @Summary Deletar usuário
 // @Description Remove um usuário do sistema (requer autenticação)
@@ -201,7 +317,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 // Register godoc
 // @Summary Registrar novo usuário
-// @Description Registra um novo usuário no sistema
+// @Description Registra um novo usuário no sistema. Com referral_code, registra o usuário que o indicou
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -224,8 +340,11 @@ func (h *UserHandler) Register(c *gin.Context) {
 	userResponse, err := h.userService.Register(&registerReq)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "email já está em uso" {
+		switch err.Error() {
+		case "email já está em uso":
 			status = http.StatusConflict
+		case "código de indicação inválido", "não é possível usar o próprio código de indicação":
+			status = http.StatusBadRequest
 		}
 		c.JSON(status, gin.H{
 			"error": err.Error(),
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"reflect"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidations registra no validador do Gin os tipos próprios da API.
+// Campos money.Money são validados pelos centavos, então regras como min=0 valem para valores monetários
+func RegisterValidations() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if amount, ok := field.Interface().(money.Money); ok {
+			return amount.Cents()
+		}
+		return nil
+	}, money.Money{})
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cauamistura/BNUPremios/internal/middleware"
+	"github.com/cauamistura/BNUPremios/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WalletHandler implementa os handlers HTTP das carteiras
+type WalletHandler struct {
+	walletService *services.WalletService
+}
+
+// NewWalletHandler cria uma nova instância do handler de carteiras
+func NewWalletHandler(walletService *services.WalletService) *WalletHandler {
+	return &WalletHandler{walletService: walletService}
+}
+
+// GetWallet godoc
+// @Summary Saldo da carteira
+// @Description Retorna o saldo da carteira do usuário (apenas o próprio usuário ou um administrador)
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} models.Wallet
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/wallet [get]
+func (h *WalletHandler) GetWallet(c *gin.Context) {
+	userID, ok := authorizeWalletAccess(c)
+	if !ok {
+		return
+	}
+
+	wallet, err := h.walletService.GetWallet(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// ListTransactions godoc
+// @Summary Extrato da carteira
+// @Description Lista os lançamentos do razão na carteira do usuário, do mais recente para o mais antigo (apenas o próprio usuário ou um administrador)
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do usuário"
+// @Param page query int false "Página (padrão: 1)"
+// @Param limit query int false "Limite por página (padrão: 20, máximo: 100)"
+// @Success 200 {object} models.LedgerEntryListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /users/{id}/wallet/transactions [get]
+func (h *WalletHandler) ListTransactions(c *gin.Context) {
+	userID, ok := authorizeWalletAccess(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+
+	transactions, err := h.walletService.ListTransactions(userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Erro interno do servidor",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, transactions)
+}
+
+// authorizeWalletAccess garante que a carteira da rota é do usuário autenticado ou que ele é administrador
+func authorizeWalletAccess(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "ID do usuário inválido",
+			"message": "Formato de ID inválido",
+		})
+		return uuid.Nil, false
+	}
+
+	authUserID, err := middleware.GetUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Usuário não autenticado",
+			"message": "Token inválido ou ausente",
+		})
+		return uuid.Nil, false
+	}
+
+	if authUserID != userID && !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Acesso negado",
+			"message": "Você só pode acessar sua própria carteira",
+		})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
@@ -108,3 +108,8 @@ func GetUserFromContext(c *gin.Context) (uuid.UUID, error) {
 
 	return userID, nil
 }
+
+// IsAdmin indica se o usuário autenticado tem o papel de administrador
+func IsAdmin(c *gin.Context) bool {
+	return c.GetString("user_role") == "admin"
+}
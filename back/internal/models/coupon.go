@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+// Tipos de cupom: desconto percentual, desconto de valor fixo ou números bônus
+// a cada min_quantity números comprados ("compre 10, ganhe 2")
+const (
+	CouponPercentage   = "percentage"
+	CouponFixed        = "fixed"
+	CouponBonusNumbers = "bonus_numbers"
+)
+
+// Coupon representa um cupom de desconto de um organizador. Sem RewardID vale para todos os prêmios dele
+type Coupon struct {
+	ID              uuid.UUID    `json:"id"`
+	OwnerID         uuid.UUID    `json:"owner_id"`
+	RewardID        *uuid.UUID   `json:"reward_id,omitempty"`
+	Code            string       `json:"code"`
+	Type            string       `json:"type"`
+	DiscountPercent *float64     `json:"discount_percent,omitempty"`
+	DiscountAmount  *money.Money `json:"discount_amount,omitempty" swaggertype:"number"`
+	BonusNumbers    *int         `json:"bonus_numbers,omitempty"`
+	MinQuantity     *int         `json:"min_quantity,omitempty"`
+	MaxUses         *int         `json:"max_uses,omitempty"`
+	MaxUsesPerUser  *int         `json:"max_uses_per_user,omitempty"`
+	StartsAt        *time.Time   `json:"starts_at,omitempty"`
+	ExpiresAt       *time.Time   `json:"expires_at,omitempty"`
+	Active          bool         `json:"active"`
+	Uses            int          `json:"uses"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// CouponDiscount representa o efeito de um cupom em um pedido
+type CouponDiscount struct {
+	Discount     money.Money
+	BonusNumbers int
+}
+
+// Apply calcula o desconto do cupom em um pedido de quantity números no valor de subtotal.
+// Confere a validade e a quantidade mínima; os limites de uso são conferidos pelo repositório
+func (c *Coupon) Apply(quantity int, subtotal money.Money, now time.Time) (*CouponDiscount, error) {
+	switch {
+	case !c.Active:
+		return nil, &CouponError{Message: "cupom inativo"}
+	case c.StartsAt != nil && now.Before(*c.StartsAt):
+		return nil, &CouponError{Message: "cupom ainda não está válido"}
+	case c.ExpiresAt != nil && !now.Before(*c.ExpiresAt):
+		return nil, &CouponError{Message: "cupom expirado"}
+	case c.MinQuantity != nil && quantity < *c.MinQuantity:
+		return nil, &CouponError{Message: fmt.Sprintf("o pedido deve ter no mínimo %d números para usar o cupom", *c.MinQuantity)}
+	}
+
+	var err error
+	result := &CouponDiscount{Discount: money.New(0, subtotal.Currency())}
+	switch c.Type {
+	case CouponPercentage:
+		if result.Discount, err = subtotal.Percent(*c.DiscountPercent); err != nil {
+			return nil, err
+		}
+	case CouponFixed:
+		result.Discount = *c.DiscountAmount
+		if cmp, err := result.Discount.Cmp(subtotal); err != nil {
+			return nil, err
+		} else if cmp > 0 {
+			result.Discount = subtotal
+		}
+	case CouponBonusNumbers:
+		result.BonusNumbers = quantity / *c.MinQuantity * *c.BonusNumbers
+	}
+
+	return result, nil
+}
+
+// CouponError indica um cupom que não pode ser usado no pedido
+type CouponError struct {
+	Message string
+}
+
+func (e *CouponError) Error() string {
+	return e.Message
+}
+
+// CreateCouponRequest representa a requisição de criação de um cupom.
+// percentage exige discount_percent, fixed exige discount_amount e bonus_numbers exige bonus_numbers e min_quantity
+type CreateCouponRequest struct {
+	Code            string       `json:"code" binding:"required,min=3,max=30,alphanum"`
+	RewardID        *uuid.UUID   `json:"reward_id"`
+	Type            string       `json:"type" binding:"required,oneof=percentage fixed bonus_numbers"`
+	DiscountPercent *float64     `json:"discount_percent" binding:"omitempty,gt=0,max=100"`
+	DiscountAmount  *money.Money `json:"discount_amount" binding:"omitempty,gt=0" swaggertype:"number"`
+	BonusNumbers    *int         `json:"bonus_numbers" binding:"omitempty,min=1"`
+	MinQuantity     *int         `json:"min_quantity" binding:"omitempty,min=1"`
+	MaxUses         *int         `json:"max_uses" binding:"omitempty,min=1"`
+	MaxUsesPerUser  *int         `json:"max_uses_per_user" binding:"omitempty,min=1"`
+	StartsAt        *time.Time   `json:"starts_at"`
+	ExpiresAt       *time.Time   `json:"expires_at"`
+}
+
+// UpdateCouponRequest representa a requisição de atualização de um cupom.
+// O tipo e o valor do desconto não mudam depois de criado
+type UpdateCouponRequest struct {
+	Active         *bool      `json:"active"`
+	MaxUses        *int       `json:"max_uses" binding:"omitempty,min=1"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user" binding:"omitempty,min=1"`
+	StartsAt       *time.Time `json:"starts_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+// CouponRedemption representa o uso de um cupom em um pedido de compra
+type CouponRedemption struct {
+	ID             uuid.UUID   `json:"id"`
+	CouponID       uuid.UUID   `json:"coupon_id"`
+	PurchaseID     uuid.UUID   `json:"purchase_id"`
+	UserID         uuid.UUID   `json:"user_id"`
+	PaymentStatus  string      `json:"payment_status"`
+	DiscountAmount money.Money `json:"discount_amount" swaggertype:"number"`
+	BonusNumbers   int         `json:"bonus_numbers"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// CouponListResponse representa a resposta da listagem de cupons
+type CouponListResponse struct {
+	Coupons []Coupon `json:"coupons"`
+}
+
+// CouponRedemptionListResponse representa a resposta da listagem de resgates de um cupom
+type CouponRedemptionListResponse struct {
+	Redemptions []CouponRedemption `json:"redemptions"`
+}
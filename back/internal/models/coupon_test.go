@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+)
+
+func TestCouponApply(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+	percent := func(v float64) *float64 { return &v }
+	amount := func(cents int64) *money.Money { m := money.FromCents(cents); return &m }
+	count := func(v int) *int { return &v }
+
+	tests := []struct {
+		name      string
+		coupon    Coupon
+		quantity  int
+		subtotal  money.Money
+		want      CouponDiscount
+		wantError string
+	}{
+		{
+			name:     "percentual",
+			coupon:   Coupon{Type: CouponPercentage, DiscountPercent: percent(15), Active: true},
+			quantity: 3,
+			subtotal: money.FromCents(3000),
+			want:     CouponDiscount{Discount: money.FromCents(450)},
+		},
+		{
+			name:     "percentual arredonda o centavo",
+			coupon:   Coupon{Type: CouponPercentage, DiscountPercent: percent(12.5), Active: true},
+			quantity: 1,
+			subtotal: money.FromCents(999),
+			want:     CouponDiscount{Discount: money.FromCents(125)},
+		},
+		{
+			name:     "valor fixo",
+			coupon:   Coupon{Type: CouponFixed, DiscountAmount: amount(500), Active: true},
+			quantity: 2,
+			subtotal: money.FromCents(2000),
+			want:     CouponDiscount{Discount: money.FromCents(500)},
+		},
+		{
+			name:     "valor fixo limitado ao subtotal",
+			coupon:   Coupon{Type: CouponFixed, DiscountAmount: amount(5000), Active: true},
+			quantity: 2,
+			subtotal: money.FromCents(2000),
+			want:     CouponDiscount{Discount: money.FromCents(2000)},
+		},
+		{
+			name:     "números bônus por múltiplo da quantidade mínima",
+			coupon:   Coupon{Type: CouponBonusNumbers, BonusNumbers: count(2), MinQuantity: count(10), Active: true},
+			quantity: 25,
+			subtotal: money.FromCents(25000),
+			want:     CouponDiscount{Discount: money.FromCents(0), BonusNumbers: 4},
+		},
+		{
+			name:     "validade dentro do período",
+			coupon:   Coupon{Type: CouponFixed, DiscountAmount: amount(100), Active: true, StartsAt: &before, ExpiresAt: &after},
+			quantity: 1,
+			subtotal: money.FromCents(1000),
+			want:     CouponDiscount{Discount: money.FromCents(100)},
+		},
+		{
+			name:      "cupom inativo",
+			coupon:    Coupon{Type: CouponFixed, DiscountAmount: amount(100)},
+			quantity:  1,
+			subtotal:  money.FromCents(1000),
+			wantError: "cupom inativo",
+		},
+		{
+			name:      "antes do início",
+			coupon:    Coupon{Type: CouponFixed, DiscountAmount: amount(100), Active: true, StartsAt: &after},
+			quantity:  1,
+			subtotal:  money.FromCents(1000),
+			wantError: "cupom ainda não está válido",
+		},
+		{
+			name:      "expira no instante do pedido",
+			coupon:    Coupon{Type: CouponFixed, DiscountAmount: amount(100), Active: true, ExpiresAt: &now},
+			quantity:  1,
+			subtotal:  money.FromCents(1000),
+			wantError: "cupom expirado",
+		},
+		{
+			name:      "abaixo da quantidade mínima",
+			coupon:    Coupon{Type: CouponBonusNumbers, BonusNumbers: count(1), MinQuantity: count(5), Active: true},
+			quantity:  4,
+			subtotal:  money.FromCents(4000),
+			wantError: "o pedido deve ter no mínimo 5 números para usar o cupom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.coupon.Apply(tt.quantity, tt.subtotal, now)
+			if tt.wantError != "" {
+				var couponErr *CouponError
+				if !errors.As(err, &couponErr) || couponErr.Message != tt.wantError {
+					t.Fatalf("Apply() erro = %v, esperado %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() erro inesperado: %v", err)
+			}
+			if !got.Discount.Equal(tt.want.Discount) || got.BonusNumbers != tt.want.BonusNumbers {
+				t.Errorf("Apply() = {%s, %d}, esperado {%s, %d}",
+					got.Discount, got.BonusNumbers, tt.want.Discount, tt.want.BonusNumbers)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DrawDateChange representa um adiamento da data do sorteio de um prêmio
+type DrawDateChange struct {
+	ID           uuid.UUID  `json:"id"`
+	RewardID     uuid.UUID  `json:"reward_id"`
+	PreviousDate time.Time  `json:"previous_date"`
+	NewDate      time.Time  `json:"new_date"`
+	Reason       string     `json:"reason"`
+	ChangedBy    *uuid.UUID `json:"changed_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// PostponeDrawRequest representa a requisição de adiamento do sorteio
+type PostponeDrawRequest struct {
+	DrawDate time.Time `json:"draw_date" binding:"required"`
+	Reason   string    `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// PostponeDrawResponse representa o prêmio adiado, o adiamento registrado e quantos compradores foram avisados
+type PostponeDrawResponse struct {
+	Reward           RewardResponse `json:"reward"`
+	Change           DrawDateChange `json:"change"`
+	Postponements    int            `json:"postponements"`
+	MaxPostponements int            `json:"max_postponements"`
+	NotifiedBuyers   int            `json:"notified_buyers"`
+}
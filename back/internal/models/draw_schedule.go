@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledDraw representa um prêmio aguardando o sorteio automático
+type ScheduledDraw struct {
+	RewardID   uuid.UUID `json:"reward_id"`
+	RewardName string    `json:"reward_name"`
+	DrawDate   time.Time `json:"draw_date"`
+}
+
+// DrawFailure representa uma falha registrada no sorteio automático de um prêmio
+type DrawFailure struct {
+	RewardID      uuid.UUID `json:"reward_id"`
+	RewardName    string    `json:"reward_name"`
+	DrawDate      time.Time `json:"draw_date"`
+	Attempts      int       `json:"attempts"`
+	LastError     string    `json:"last_error"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	Exhausted     bool      `json:"exhausted"`
+}
+
+// DrawSchedulerStatusResponse representa a resposta do status do agendador de sorteios
+type DrawSchedulerStatusResponse struct {
+	Enabled     bool            `json:"enabled"`
+	Interval    string          `json:"interval"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastRunAt   *time.Time      `json:"last_run_at,omitempty"`
+	Pending     []ScheduledDraw `json:"pending"`
+	Failed      []DrawFailure   `json:"failed"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de aviso enviados aos usuários
+const (
+	NotificationRewardCancelled = "reward_cancelled"
+	NotificationDrawPostponed   = "draw_postponed"
+	NotificationPrizeWon        = "prize_won"
+	NotificationPrizeClaimed    = "prize_claimed"
+	NotificationPrizeShipped    = "prize_shipped"
+	NotificationPrizeReceived   = "prize_received"
+	NotificationPrizeForfeited  = "prize_forfeited"
+)
+
+// Notification representa um aviso a um usuário sobre um prêmio ou pedido
+type Notification struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Type       string     `json:"type"`
+	Title      string     `json:"title"`
+	Message    string     `json:"message"`
+	RewardID   *uuid.UUID `json:"reward_id,omitempty"`
+	PurchaseID *uuid.UUID `json:"purchase_id,omitempty"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NotificationListResponse representa a resposta da listagem de avisos do usuário
+type NotificationListResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+	Pagination    Pagination     `json:"pagination"`
+}
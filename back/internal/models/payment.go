@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+// Situações de uma devolução. Devoluções de PIX direto ficam como manual e são feitas pelo organizador
+const (
+	RefundProcessing = "processing"
+	RefundCompleted  = "completed"
+	RefundFailed     = "failed"
+	RefundManual     = "manual"
+)
+
+// PurchaseRefund representa a devolução do valor pago em um pedido
+type PurchaseRefund struct {
+	ID            uuid.UUID   `json:"id"`
+	PurchaseID    uuid.UUID   `json:"purchaseId"`
+	Amount        money.Money `json:"amount" swaggertype:"number"`
+	Status        string      `json:"status"`
+	Provider      *string     `json:"provider,omitempty"`
+	Reason        *string     `json:"reason,omitempty"`
+	RequestedBy   *uuid.UUID  `json:"requestedBy,omitempty"`
+	FailureReason *string     `json:"failureReason,omitempty"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+}
+
+// CancelPurchaseRequest representa a requisição de cancelamento de um pedido
+type CancelPurchaseRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// CancelPurchaseResponse representa o pedido cancelado e a devolução criada, se houver valor pago
+type CancelPurchaseResponse struct {
+	Purchase *Purchase       `json:"purchase"`
+	Refund   *PurchaseRefund `json:"refund,omitempty"`
+}
+
+// PurchaseRefundListResponse representa as devoluções de um pedido
+type PurchaseRefundListResponse struct {
+	Refunds []PurchaseRefund `json:"refunds"`
+}
+
+// PaymentWebhookRequest representa a notificação enviada pelo provedor de pagamento,
+// no formato da API Pix: uma lista de PIX recebidos com o txid da cobrança
+type PaymentWebhookRequest struct {
+	Pix []PaymentWebhookPix `json:"pix"`
+}
+
+// PaymentWebhookPix representa um PIX recebido na notificação
+type PaymentWebhookPix struct {
+	TxID       string `json:"txid"`
+	EndToEndID string `json:"endToEndId"`
+	Valor      string `json:"valor"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Situações do resgate de um prêmio sorteado
+const (
+	ClaimPending   = "pending"   // aguardando os dados de entrega do ganhador
+	ClaimConfirmed = "confirmed" // dados de entrega confirmados pelo ganhador
+	ClaimShipped   = "shipped"   // enviado ou entregue em mãos pelo organizador
+	ClaimReceived  = "received"  // recebimento confirmado pelo ganhador
+	ClaimForfeited = "forfeited" // prazo de resgate vencido; o prêmio foi sorteado novamente
+)
+
+// Formas de entrega de um prêmio
+const (
+	DeliveryShipping = "shipping"
+	DeliveryHandover = "handover"
+)
+
+// PrizeClaim representa o resgate e a entrega do prêmio de um número vencedor
+type PrizeClaim struct {
+	ID              uuid.UUID  `json:"id"`
+	RewardID        uuid.UUID  `json:"reward_id"`
+	Number          int        `json:"number"`
+	UserID          uuid.UUID  `json:"user_id"`
+	Position        int        `json:"position"`
+	PrizeName       string     `json:"prize_name"`
+	Status          string     `json:"status"`
+	ClaimDeadline   time.Time  `json:"claim_deadline"`
+	RecipientName   string     `json:"recipient_name,omitempty"`
+	RecipientPhone  string     `json:"recipient_phone,omitempty"`
+	DeliveryAddress string     `json:"delivery_address,omitempty"`
+	DeliveryNotes   string     `json:"delivery_notes,omitempty"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`
+	DeliveryMethod  *string    `json:"delivery_method,omitempty"`
+	TrackingCode    string     `json:"tracking_code,omitempty"`
+	ShippingNotes   string     `json:"shipping_notes,omitempty"`
+	ProofImages     []string   `json:"proof_images"`
+	ShippedAt       *time.Time `json:"shipped_at,omitempty"`
+	ReceivedAt      *time.Time `json:"received_at,omitempty"`
+	ForfeitedAt     *time.Time `json:"forfeited_at,omitempty"`
+	ReplacedBy      *uuid.UUID `json:"replaced_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ConfirmClaimRequest representa os dados de entrega informados pelo ganhador
+type ConfirmClaimRequest struct {
+	RecipientName   string `json:"recipient_name" binding:"required,max=255"`
+	RecipientPhone  string `json:"recipient_phone" binding:"required,max=30"`
+	DeliveryAddress string `json:"delivery_address" binding:"required,max=500"`
+	Notes           string `json:"notes" binding:"omitempty,max=500"`
+}
+
+// ShipClaimRequest representa o envio ou a entrega em mãos registrada pelo organizador, com as fotos de comprovação
+type ShipClaimRequest struct {
+	Method       string   `json:"method" binding:"required,oneof=shipping handover"`
+	TrackingCode string   `json:"tracking_code" binding:"omitempty,max=100"`
+	ProofImages  []string `json:"proof_images" binding:"required,min=1,max=10,dive,required,max=500"`
+	Notes        string   `json:"notes" binding:"omitempty,max=500"`
+}
+
+// PrizeClaimListResponse representa a resposta da listagem de resgates
+type PrizeClaimListResponse struct {
+	Claims []PrizeClaim `json:"claims"`
+}
+
+// RedrawClaimResponse representa o resgate perdido por prazo vencido e o resgate do novo ganhador
+type RedrawClaimResponse struct {
+	Forfeited PrizeClaim `json:"forfeited"`
+	Claim     PrizeClaim `json:"claim"`
+}
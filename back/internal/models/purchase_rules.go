@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Regras de compra verificadas em cada pedido
+const (
+	RuleMinPerOrder  = "min_per_order"
+	RuleMaxPerOrder  = "max_per_order"
+	RuleMaxPerUser   = "max_per_user"
+	RuleQuantityStep = "quantity_step"
+)
+
+// PurchaseRules representa as regras de compra de um prêmio. Zero em MaxPerOrder ou MaxPerUser significa sem limite
+type PurchaseRules struct {
+	MinPerOrder  int `json:"min_per_order"`
+	MaxPerOrder  int `json:"max_per_order,omitempty"`
+	MaxPerUser   int `json:"max_per_user,omitempty"`
+	QuantityStep int `json:"quantity_step"`
+}
+
+// PurchaseRuleViolation descreve uma regra de compra não atendida
+type PurchaseRuleViolation struct {
+	Rule    string `json:"rule"`
+	Limit   int    `json:"limit"`
+	Message string `json:"message"`
+}
+
+// PurchaseRulesError agrupa as regras de compra não atendidas por um pedido
+type PurchaseRulesError struct {
+	Violations []PurchaseRuleViolation
+}
+
+func (e *PurchaseRulesError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// MaxPerUserViolation monta a violação do limite de números por usuário
+func MaxPerUserViolation(limit, owned int) PurchaseRuleViolation {
+	return PurchaseRuleViolation{
+		Rule:    RuleMaxPerUser,
+		Limit:   limit,
+		Message: fmt.Sprintf("cada usuário pode ter no máximo %d números neste prêmio (você já possui %d)", limit, owned),
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+// Bônus de indicação: números grátis no prêmio da primeira compra paga do indicado ou crédito na carteira
+const (
+	ReferralBonusNumbers = "bonus_numbers"
+	ReferralWalletCredit = "wallet_credit"
+)
+
+// Situações de uma indicação
+const (
+	ReferralPending  = "pending"
+	ReferralRewarded = "rewarded"
+	ReferralRevoked  = "revoked"
+)
+
+// ReferralTerms representa o bônus dado a quem indica. As condições são registradas em cada indicação
+// no cadastro do indicado; o crédito também é usado quando o prêmio não tem números livres para o bônus
+type ReferralTerms struct {
+	RewardType   string
+	BonusNumbers int
+	CreditAmount money.Money
+}
+
+// Referral representa a indicação de um usuário e o bônus recebido por quem o indicou
+type Referral struct {
+	ID             uuid.UUID   `json:"id"`
+	ReferredID     uuid.UUID   `json:"referred_id"`
+	ReferredName   string      `json:"referred_name"`
+	RewardType     string      `json:"reward_type"`
+	Status         string      `json:"status"`
+	RewardID       *uuid.UUID  `json:"reward_id,omitempty"`
+	AwardedNumbers int         `json:"awarded_numbers"`
+	AwardedAmount  money.Money `json:"awarded_amount" swaggertype:"number"`
+	RewardedAt     *time.Time  `json:"rewarded_at,omitempty"`
+	RevokedAt      *time.Time  `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// ReferralCodeResponse representa o código de indicação de um usuário
+type ReferralCodeResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Code   string    `json:"code"`
+}
+
+// ReferralStatsResponse representa as indicações de um usuário e os bônus recebidos
+type ReferralStatsResponse struct {
+	Code           string      `json:"code"`
+	Total          int         `json:"total"`
+	Pending        int         `json:"pending"`
+	Rewarded       int         `json:"rewarded"`
+	Revoked        int         `json:"revoked"`
+	AwardedNumbers int         `json:"awarded_numbers"`
+	AwardedAmount  money.Money `json:"awarded_amount" swaggertype:"number"`
+	Referrals      []Referral  `json:"referrals"`
+}
@@ -1,91 +1,190 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cauamistura/BNUPremios/internal/money"
 	"github.com/google/uuid"
 )
 
 // Reward representa um prêmio no sistema
 type Reward struct {
-	ID           uuid.UUID  `json:"id" db:"id"`
-	OwnerID      uuid.UUID  `json:"owner_id" db:"owner_id"`
-	Name         string     `json:"name" db:"name" binding:"required"`
-	Description  string     `json:"description" db:"description"`
-	Image        string     `json:"image" db:"image"`
-	DrawDate     time.Time  `json:"draw_date" db:"draw_date"`
-	Completed    bool       `json:"completed" db:"completed"`
-	WinnerNumber *int       `json:"winner_number,omitempty" db:"winner_number"`
-	DrawnAt      *time.Time `json:"drawn_at,omitempty" db:"drawn_at"`
-	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	OwnerID         uuid.UUID  `json:"owner_id" db:"owner_id"`
+	Name            string     `json:"name" db:"name" binding:"required"`
+	Description     string     `json:"description" db:"description"`
+	Image           string     `json:"image" db:"image"`
+	DrawDate        time.Time  `json:"draw_date" db:"draw_date"`
+	Status          string     `json:"status" db:"status"`
+	StatusChangedAt time.Time  `json:"status_changed_at" db:"status_changed_at"`
+	SoldOut         bool       `json:"sold_out" db:"sold_out"`
+	WinnerNumber    *int       `json:"winner_number,omitempty" db:"winner_number"`
+	DrawnAt         *time.Time `json:"drawn_at,omitempty" db:"drawn_at"`
+	DrawSeed        *string    `json:"-" db:"draw_seed"`
+	DrawSeedHash    *string    `json:"draw_seed_hash,omitempty" db:"draw_seed_hash"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // RewardDetails representa os detalhes completos de um prêmio
 type RewardDetails struct {
-	Reward     Reward            `json:"reward"`
-	Images     []string          `json:"images"`
-	Price      float64           `json:"price"`
-	MinQuota   int               `json:"min_quota"`
-	Buyers     []BuyerWithNumber `json:"buyers"`
-	WinnerUser *UserResponse     `json:"winner_user,omitempty"`
+	Reward   Reward      `json:"reward"`
+	Images   []string    `json:"images"`
+	Price    money.Money `json:"price" swaggertype:"number"`
+	MinQuota int         `json:"min_quota"`
+	RewardOptions
+	SoldNumbers  int               `json:"sold_numbers"`
+	SalesCloseAt time.Time         `json:"sales_close_at"`
+	Prizes       []RewardPrize     `json:"prizes"`
+	Packages     []RewardPackage   `json:"packages"`
+	Buyers       []BuyerWithNumber `json:"buyers"`
+	Winners      []RewardWinner    `json:"winners"`
+	TopBuyers    []TopBuyerWinner  `json:"top_buyers"`
+	DateChanges  []DrawDateChange  `json:"draw_date_changes"`
 }
 
-// CreateRewardRequest representa a requisição de criação de prêmio
+// RewardOptions representa as configurações opcionais de um prêmio armazenadas em reward_details
+type RewardOptions struct {
+	TotalNumbers       *int    `json:"total_numbers,omitempty" binding:"omitempty,min=1"`
+	AllocationMode     *string `json:"allocation_mode,omitempty" binding:"omitempty,oneof=sequential random"`
+	MaxPerOrder        *int    `json:"max_per_order,omitempty" binding:"omitempty,min=1"`
+	MaxPerUser         *int    `json:"max_per_user,omitempty" binding:"omitempty,min=1"`
+	QuantityStep       *int    `json:"quantity_step,omitempty" binding:"omitempty,min=1"`
+	TopBuyerPrize      *string `json:"top_buyer_prize,omitempty"`
+	DailyTopBuyerPrize *string `json:"daily_top_buyer_prize,omitempty"`
+	SalesCloseMinutes  *int    `json:"sales_close_minutes,omitempty" binding:"omitempty,min=0"`
+	MinSoldNumbers     *int    `json:"min_sold_numbers,omitempty" binding:"omitempty,min=1"`
+}
+
+// Formas de distribuição dos números comprados por quantidade
+const (
+	AllocationSequential = "sequential"
+	AllocationRandom     = "random"
+)
+
+// TopBuyerWinner representa o maior comprador premiado de um prêmio.
+// Kind é "overall" para o maior comprador geral e "daily" para o maior comprador de um dia de vendas, indicado em
+// ReferenceDate
+type TopBuyerWinner struct {
+	Kind            string       `json:"kind"`
+	PrizeName       string       `json:"prize_name"`
+	User            UserResponse `json:"user"`
+	TotalNumbers    int          `json:"total_numbers"`
+	FirstPurchaseAt time.Time    `json:"first_purchase_at"`
+	ReferenceDate   *time.Time   `json:"reference_date,omitempty"`
+	ResolvedAt      time.Time    `json:"resolved_at"`
+}
+
+// RewardPrize representa uma faixa de premiação de um prêmio (1º lugar, 2º lugar, consolação...)
+type RewardPrize struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	RewardID uuid.UUID `json:"reward_id" db:"reward_id"`
+	Position int       `json:"position" db:"position"`
+	Name     string    `json:"name" db:"name"`
+	Image    string    `json:"image" db:"image"`
+	Quantity int       `json:"quantity" db:"quantity"`
+}
+
+// RewardPrizeRequest representa uma faixa de premiação na criação ou atualização de um prêmio
+type RewardPrizeRequest struct {
+	Position int    `json:"position" binding:"required,min=1"`
+	Name     string `json:"name" binding:"required"`
+	Image    string `json:"image"`
+	Quantity int    `json:"quantity" binding:"omitempty,min=1"`
+}
+
+// RewardWinner representa um número vencedor de uma faixa de premiação
+type RewardWinner struct {
+	PrizeID     *uuid.UUID   `json:"prize_id,omitempty"`
+	Position    int          `json:"position"`
+	PrizeName   string       `json:"prize_name"`
+	DrawOrder   int          `json:"draw_order"`
+	DrawIndex   *int         `json:"draw_index,omitempty"`
+	Number      int          `json:"number"`
+	User        UserResponse `json:"user"`
+	DrawnAt     time.Time    `json:"drawn_at"`
+	ClaimStatus *string      `json:"claim_status,omitempty"`
+}
+
+// CreateRewardRequest representa a requisição de criação de prêmio.
+// O prêmio começa como rascunho, a menos que Status peça sua publicação imediata
 type CreateRewardRequest struct {
-	Name        string    `json:"name" binding:"required"`
-	Description string    `json:"description"`
-	Image       string    `json:"image"`
-	DrawDate    time.Time `json:"draw_date" binding:"required"`
-	Images      []string  `json:"images"`
-	Price       float64   `json:"price"`
-	MinQuota    int       `json:"min_quota"`
+	Name        string               `json:"name" binding:"required"`
+	Status      string               `json:"status" binding:"omitempty,oneof=draft published"`
+	Description string               `json:"description"`
+	Image       string               `json:"image"`
+	DrawDate    time.Time            `json:"draw_date" binding:"required"`
+	Images      []string             `json:"images"`
+	Price       money.Money          `json:"price" binding:"min=0" swaggertype:"number"`
+	MinQuota    int                  `json:"min_quota"`
+	Prizes      []RewardPrizeRequest `json:"prizes"`
+	RewardOptions
 }
 
 // UpdateRewardRequest representa a requisição de atualização de prêmio
 type UpdateRewardRequest struct {
-	Name        *string    `json:"name"`
-	Description *string    `json:"description"`
-	Image       *string    `json:"image"`
-	DrawDate    *time.Time `json:"draw_date"`
-	Completed   *bool      `json:"completed"`
-	Images      []string   `json:"images"`
-	Price       *float64   `json:"price"`
-	MinQuota    *int       `json:"min_quota"`
+	Name        *string              `json:"name"`
+	Description *string              `json:"description"`
+	Image       *string              `json:"image"`
+	DrawDate    *time.Time           `json:"draw_date"`
+	Images      []string             `json:"images"`
+	Price       *money.Money         `json:"price" binding:"omitempty,min=0" swaggertype:"number"`
+	MinQuota    *int                 `json:"min_quota"`
+	Prizes      []RewardPrizeRequest `json:"prizes"`
+	RewardOptions
 }
 
 // RewardResponse representa a resposta de um prêmio
 type RewardResponse struct {
-	ID           uuid.UUID  `json:"id"`
-	OwnerID      uuid.UUID  `json:"owner_id"`
-	Name         string     `json:"name"`
-	Description  string     `json:"description"`
-	Image        string     `json:"image"`
-	DrawDate     time.Time  `json:"draw_date"`
-	Completed    bool       `json:"completed"`
-	WinnerNumber *int       `json:"winner_number,omitempty"`
-	DrawnAt      *time.Time `json:"drawn_at,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID              uuid.UUID  `json:"id"`
+	OwnerID         uuid.UUID  `json:"owner_id"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	Image           string     `json:"image"`
+	DrawDate        time.Time  `json:"draw_date"`
+	Status          string     `json:"status"`
+	StatusChangedAt time.Time  `json:"status_changed_at"`
+	SoldOut         bool       `json:"sold_out"`
+	WinnerNumber    *int       `json:"winner_number,omitempty"`
+	DrawnAt         *time.Time `json:"drawn_at,omitempty"`
+	DrawSeedHash    *string    `json:"draw_seed_hash,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 // RewardDetailsResponse representa a resposta com detalhes completos de um prêmio
 type RewardDetailsResponse struct {
 	RewardResponse
-	Images     []string          `json:"images"`
-	Price      float64           `json:"price"`
-	MinQuota   int               `json:"min_quota"`
-	Buyers     []BuyerWithNumber `json:"buyers"`
-	WinnerUser *UserResponse     `json:"winner_user,omitempty"`
+	Images   []string    `json:"images"`
+	Price    money.Money `json:"price" swaggertype:"number"`
+	MinQuota int         `json:"min_quota"`
+	RewardOptions
+	SoldNumbers  int               `json:"sold_numbers"`
+	SalesCloseAt time.Time         `json:"sales_close_at"`
+	Prizes       []RewardPrize     `json:"prizes"`
+	Packages     []RewardPackage   `json:"packages"`
+	Buyers       []BuyerWithNumber `json:"buyers"`
+	Winners      []RewardWinner    `json:"winners"`
+	TopBuyers    []TopBuyerWinner  `json:"top_buyers"`
+	DateChanges  []DrawDateChange  `json:"draw_date_changes"`
 }
 
 // RewardDetailsWithoutBuyersResponse representa a resposta com detalhes de um prêmio sem compradores
 type RewardDetailsWithoutBuyersResponse struct {
 	RewardResponse
-	Images     []string      `json:"images"`
-	Price      float64       `json:"price"`
-	MinQuota   int           `json:"min_quota"`
-	WinnerUser *UserResponse `json:"winner_user,omitempty"`
+	Images   []string    `json:"images"`
+	Price    money.Money `json:"price" swaggertype:"number"`
+	MinQuota int         `json:"min_quota"`
+	RewardOptions
+	SoldNumbers  int              `json:"sold_numbers"`
+	SalesCloseAt time.Time        `json:"sales_close_at"`
+	Prizes       []RewardPrize    `json:"prizes"`
+	Packages     []RewardPackage  `json:"packages"`
+	Winners      []RewardWinner   `json:"winners"`
+	TopBuyers    []TopBuyerWinner `json:"top_buyers"`
+	DateChanges  []DrawDateChange `json:"draw_date_changes"`
 }
 
 // RewardListResponse representa a resposta da listagem de prêmios
@@ -94,9 +193,43 @@ type RewardListResponse struct {
 	Pagination Pagination       `json:"pagination"`
 }
 
-// BuyNumbersRequest representa a requisição para comprar números
+// BuyNumbersRequest representa a requisição para comprar números.
+// Informe quantity para receber os próximos números livres ou numbers para escolher os números desejados.
+// Com package_id a quantidade é a do pacote e o pedido é cobrado pelo preço do pacote
 type BuyNumbersRequest struct {
-	Quantity int `json:"quantity" binding:"required,min=1"`
+	Quantity      int        `json:"quantity" binding:"omitempty,min=1"`
+	Numbers       []int      `json:"numbers" binding:"omitempty,dive,min=1"`
+	PackageID     *uuid.UUID `json:"package_id"`
+	PaymentMethod string     `json:"payment_method" binding:"omitempty,oneof=pix wallet"`
+	CouponCode    string     `json:"coupon_code" binding:"omitempty,max=30"`
+}
+
+// Formas de pagamento de um pedido de compra
+const (
+	PaymentMethodPix    = "pix"
+	PaymentMethodWallet = "wallet"
+)
+
+// NumberAvailabilityResponse representa os números livres de um intervalo do prêmio
+type NumberAvailabilityResponse struct {
+	TotalNumbers int   `json:"total_numbers"`
+	SoldNumbers  int   `json:"sold_numbers"`
+	From         int   `json:"from"`
+	To           int   `json:"to"`
+	Available    []int `json:"available"`
+}
+
+// UnavailableNumbersError indica que números escolhidos pelo comprador não estão livres
+type UnavailableNumbersError struct {
+	Numbers []int
+}
+
+func (e *UnavailableNumbersError) Error() string {
+	parts := make([]string, len(e.Numbers))
+	for i, number := range e.Numbers {
+		parts[i] = strconv.Itoa(number)
+	}
+	return "números indisponíveis: " + strings.Join(parts, ", ")
 }
 
 // DrawRewardRequest representa a requisição para realizar o sorteio
@@ -106,9 +239,112 @@ type DrawRewardRequest struct {
 
 // DrawRewardResponse representa a resposta do sorteio
 type DrawRewardResponse struct {
-	RewardID     uuid.UUID     `json:"reward_id"`
-	WinnerNumber int           `json:"winner_number"`
-	WinnerUser   *UserResponse `json:"winner_user,omitempty"`
-	DrawnAt      time.Time     `json:"drawn_at"`
-	Message      string        `json:"message"`
+	RewardID     uuid.UUID        `json:"reward_id"`
+	WinnerNumber int              `json:"winner_number"`
+	WinnerUser   *UserResponse    `json:"winner_user,omitempty"`
+	Winners      []RewardWinner   `json:"winners"`
+	TopBuyers    []TopBuyerWinner `json:"top_buyers"`
+	DrawnAt      time.Time        `json:"drawn_at"`
+	Message      string           `json:"message"`
+}
+
+// DrawProofResponse representa a prova pública de um sorteio commit-reveal
+type DrawProofResponse struct {
+	RewardID      uuid.UUID        `json:"reward_id"`
+	Algorithm     string           `json:"algorithm"`
+	SeedHash      *string          `json:"seed_hash,omitempty"`
+	Seed          *string          `json:"seed,omitempty"`
+	NumbersDigest *string          `json:"numbers_digest,omitempty"`
+	Numbers       []int            `json:"numbers"`
+	WinnerIndex   *int             `json:"winner_index,omitempty"`
+	WinnerNumber  *int             `json:"winner_number,omitempty"`
+	Rounds        []DrawProofRound `json:"rounds"`
+	DrawnAt       *time.Time       `json:"drawn_at,omitempty"`
+	Drawn         bool             `json:"drawn"`
+}
+
+// DrawProofRound representa uma rodada do sorteio na prova pública
+type DrawProofRound struct {
+	Round     int    `json:"round"`
+	Position  int    `json:"position"`
+	PrizeName string `json:"prize_name"`
+	Index     *int   `json:"index,omitempty"`
+	Number    int    `json:"number"`
+}
+
+// InstantPrize representa uma cota premiada: um número oculto que dá um prêmio instantâneo a quem comprá-lo
+type InstantPrize struct {
+	ID          uuid.UUID     `json:"id" db:"id"`
+	RewardID    uuid.UUID     `json:"reward_id" db:"reward_id"`
+	Number      int           `json:"number" db:"number"`
+	Name        string        `json:"name" db:"name"`
+	Description string        `json:"description" db:"description"`
+	Value       money.Money   `json:"value" db:"value" swaggertype:"number"`
+	ClaimedBy   *uuid.UUID    `json:"claimed_by,omitempty" db:"claimed_by"`
+	ClaimedAt   *time.Time    `json:"claimed_at,omitempty" db:"claimed_at"`
+	Winner      *UserResponse `json:"winner,omitempty"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+}
+
+// InstantPrizeRequest representa uma cota premiada na requisição do organizador
+type InstantPrizeRequest struct {
+	Number      int         `json:"number" binding:"required,min=1"`
+	Name        string      `json:"name" binding:"required"`
+	Description string      `json:"description"`
+	Value       money.Money `json:"value" binding:"min=0" swaggertype:"number"`
+}
+
+// CreateInstantPrizesRequest representa a requisição para cadastrar cotas premiadas em um prêmio
+type CreateInstantPrizesRequest struct {
+	Prizes []InstantPrizeRequest `json:"prizes" binding:"required,min=1,dive"`
+}
+
+// InstantPrizeResponse representa uma cota premiada na listagem pública.
+// O número só é revelado depois que a cota for comprada
+type InstantPrizeResponse struct {
+	ID          uuid.UUID     `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Value       money.Money   `json:"value" swaggertype:"number"`
+	Claimed     bool          `json:"claimed"`
+	Number      *int          `json:"number,omitempty"`
+	Winner      *UserResponse `json:"winner,omitempty"`
+	ClaimedAt   *time.Time    `json:"claimed_at,omitempty"`
+}
+
+// InstantPrizeListResponse representa a resposta da listagem de cotas premiadas de um prêmio
+type InstantPrizeListResponse struct {
+	Prizes    []InstantPrizeResponse `json:"prizes"`
+	Total     int                    `json:"total"`
+	Claimed   int                    `json:"claimed"`
+	Unclaimed int                    `json:"unclaimed"`
+}
+
+// BuyNumbersResult representa o resultado de uma compra de números
+type BuyNumbersResult struct {
+	PurchaseID    uuid.UUID      `json:"purchase_id"`
+	Numbers       []int          `json:"numbers"`
+	InstantPrizes []InstantPrize `json:"instant_prizes"`
+}
+
+// RewardPackage representa um pacote de números com preço próprio ("50 números por R$ 20,00")
+type RewardPackage struct {
+	ID        uuid.UUID   `json:"id"`
+	RewardID  uuid.UUID   `json:"reward_id"`
+	Name      string      `json:"name"`
+	Quantity  int         `json:"quantity"`
+	Price     money.Money `json:"price" swaggertype:"number"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// CreateRewardPackageRequest representa a requisição de cadastro de um pacote de números
+type CreateRewardPackageRequest struct {
+	Name     string      `json:"name" binding:"required,max=100"`
+	Quantity int         `json:"quantity" binding:"required,min=1"`
+	Price    money.Money `json:"price" binding:"gt=0" swaggertype:"number"`
+}
+
+// RewardPackageListResponse representa a resposta da listagem de pacotes de um prêmio
+type RewardPackageListResponse struct {
+	Packages []RewardPackage `json:"packages"`
 }
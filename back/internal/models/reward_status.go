@@ -0,0 +1,94 @@
+package models
+
+import "fmt"
+
+// Situações do ciclo de vida de um prêmio
+const (
+	RewardDraft       = "draft"
+	RewardPublished   = "published"
+	RewardSalesClosed = "sales_closed"
+	RewardDrawn       = "drawn"
+	RewardCancelled   = "cancelled"
+	RewardDelivered   = "delivered"
+)
+
+// rewardTransitions lista, para cada situação, as situações para as quais o prêmio pode passar
+var rewardTransitions = map[string][]string{
+	RewardDraft:       {RewardPublished, RewardCancelled},
+	RewardPublished:   {RewardSalesClosed, RewardCancelled},
+	RewardSalesClosed: {RewardPublished, RewardDrawn, RewardCancelled},
+	RewardDrawn:       {RewardDelivered},
+}
+
+// CanTransitionReward informa se um prêmio pode passar da situação from para a situação to
+func CanTransitionReward(from, to string) bool {
+	for _, next := range rewardTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// RewardStatusesBefore lista as situações a partir das quais um prêmio pode passar para a situação to
+func RewardStatusesBefore(to string) []string {
+	var statuses []string
+	for _, from := range []string{RewardDraft, RewardPublished, RewardSalesClosed, RewardDrawn, RewardCancelled, RewardDelivered} {
+		if CanTransitionReward(from, to) {
+			statuses = append(statuses, from)
+		}
+	}
+	return statuses
+}
+
+// IsValidRewardStatus informa se status é uma situação de prêmio conhecida
+func IsValidRewardStatus(status string) bool {
+	switch status {
+	case RewardDraft, RewardPublished, RewardSalesClosed, RewardDrawn, RewardCancelled, RewardDelivered:
+		return true
+	}
+	return false
+}
+
+// IsDrawn informa se o prêmio já foi sorteado
+func (r *Reward) IsDrawn() bool {
+	return r.Status == RewardDrawn || r.Status == RewardDelivered
+}
+
+// RewardStatusError é retornado quando uma operação exige uma transição de situação não permitida
+type RewardStatusError struct {
+	From string
+	To   string
+}
+
+func (e *RewardStatusError) Error() string {
+	return fmt.Sprintf("não é possível passar o prêmio de %s para %s", e.From, e.To)
+}
+
+// MinimumSalesError é retornado quando o prêmio não vendeu o mínimo de números para o sorteio.
+// Cancelled indica que o prêmio foi cancelado por ter chegado à data do sorteio abaixo do mínimo
+type MinimumSalesError struct {
+	Sold      int
+	Minimum   int
+	Cancelled bool
+}
+
+func (e *MinimumSalesError) Error() string {
+	if e.Cancelled {
+		return fmt.Sprintf("prêmio cancelado por não atingir o mínimo de %d números vendidos (%d vendidos)", e.Minimum, e.Sold)
+	}
+	return fmt.Sprintf("vendas abaixo do mínimo para o sorteio: %d de %d números vendidos", e.Sold, e.Minimum)
+}
+
+// CancelRewardRequest representa a requisição de cancelamento de um prêmio
+type CancelRewardRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// CancelRewardResponse representa o prêmio cancelado e os pedidos pagos cancelados com suas devoluções.
+// Failures lista os pedidos que não puderam ser cancelados agora; o limpador de reservas refaz as devoluções
+type CancelRewardResponse struct {
+	Reward    RewardResponse           `json:"reward"`
+	Purchases []CancelPurchaseResponse `json:"purchases"`
+	Failures  []string                 `json:"failures,omitempty"`
+}
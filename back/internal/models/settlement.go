@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+// Situações de um repasse ao organizador
+const (
+	SettlementPending = "pending"
+	SettlementPaid    = "paid"
+)
+
+// RewardSettlement representa o repasse ao organizador das vendas de um prêmio sorteado
+type RewardSettlement struct {
+	ID              uuid.UUID   `json:"id"`
+	RewardID        uuid.UUID   `json:"reward_id"`
+	RewardName      string      `json:"reward_name"`
+	OwnerID         uuid.UUID   `json:"owner_id"`
+	PaidPurchases   int         `json:"paid_purchases"`
+	GrossAmount     money.Money `json:"gross_amount" swaggertype:"number"`
+	DirectAmount    money.Money `json:"direct_amount" swaggertype:"number"`
+	FeePercent      float64     `json:"fee_percent"`
+	FeeAmount       money.Money `json:"fee_amount" swaggertype:"number"`
+	NetAmount       money.Money `json:"net_amount" swaggertype:"number"`
+	Status          string      `json:"status"`
+	PayoutReference *string     `json:"payout_reference,omitempty"`
+	PaidAt          *time.Time  `json:"paid_at,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// RewardProceeds representa o valor líquido apurado até o momento de um prêmio ainda não sorteado
+type RewardProceeds struct {
+	RewardID      uuid.UUID   `json:"reward_id"`
+	RewardName    string      `json:"reward_name"`
+	PaidPurchases int         `json:"paid_purchases"`
+	GrossAmount   money.Money `json:"gross_amount" swaggertype:"number"`
+	DirectAmount  money.Money `json:"direct_amount" swaggertype:"number"`
+	FeePercent    float64     `json:"fee_percent"`
+	FeeAmount     money.Money `json:"fee_amount" swaggertype:"number"`
+	NetAmount     money.Money `json:"net_amount" swaggertype:"number"`
+}
+
+// PayoutSummary representa os totais dos repasses de um organizador
+type PayoutSummary struct {
+	PendingAmount  money.Money `json:"pending_amount" swaggertype:"number"`
+	PaidAmount     money.Money `json:"paid_amount" swaggertype:"number"`
+	UpcomingAmount money.Money `json:"upcoming_amount" swaggertype:"number"`
+}
+
+// PayoutReportResponse representa o relatório de repasses de um organizador: repasses apurados
+// (pendentes e pagos) e o valor em andamento dos prêmios ainda não sorteados
+type PayoutReportResponse struct {
+	Summary     PayoutSummary      `json:"summary"`
+	Settlements []RewardSettlement `json:"settlements"`
+	Upcoming    []RewardProceeds   `json:"upcoming"`
+}
+
+// UpdatePlatformFeeRequest representa a requisição de definição da taxa da plataforma.
+// Sem platform_fee_percent a taxa específica é removida e volta a valer a padrão
+type UpdatePlatformFeeRequest struct {
+	PlatformFeePercent *float64 `json:"platform_fee_percent" binding:"omitempty,min=0,max=100"`
+}
+
+// MarkSettlementPaidRequest representa a requisição de registro do pagamento de um repasse
+type MarkSettlementPaidRequest struct {
+	Reference string `json:"reference" binding:"required,max=255"`
+}
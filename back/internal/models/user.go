@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/cauamistura/BNUPremios/internal/money"
 	"github.com/google/uuid"
 )
 
@@ -37,9 +38,10 @@ type LoginRequest struct {
 
 // RegisterRequest representa a requisição de registro
 type RegisterRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Name         string `json:"name" binding:"required"`
+	Email        string `json:"email" binding:"required,email"`
+	Password     string `json:"password" binding:"required,min=6"`
+	ReferralCode string `json:"referral_code" binding:"omitempty,max=12"`
 }
 
 // UpdateUserRequest representa a requisição de atualização de usuário
@@ -50,6 +52,19 @@ type UpdateUserRequest struct {
 	Active *bool  `json:"active"`
 }
 
+// PixSettings representa a chave PIX em que o organizador recebe os pagamentos dos seus prêmios
+type PixSettings struct {
+	PixKey       string `json:"pix_key"`
+	MerchantName string `json:"merchant_name"`
+	MerchantCity string `json:"merchant_city"`
+}
+
+// UpdatePixSettingsRequest representa a requisição de configuração da chave PIX
+type UpdatePixSettingsRequest struct {
+	PixKey       string `json:"pix_key" binding:"required,max=77"`
+	MerchantCity string `json:"merchant_city" binding:"required,max=15"`
+}
+
 // LoginResponse representa a resposta de login
 type LoginResponse struct {
 	Token string       `json:"token"`
@@ -84,16 +99,53 @@ type BuyerListResponse struct {
 	Pagination Pagination        `json:"pagination"`
 }
 
-// Purchase representa uma compra de números
+// Situações de pagamento de uma compra
+const (
+	PaymentPending   = "pending"
+	PaymentPaid      = "paid"
+	PaymentExpired   = "expired"
+	PaymentCancelled = "cancelled"
+	PaymentRefunded  = "refunded"
+)
+
+// Purchase representa um pedido de compra de números. Os números ficam reservados
+// enquanto o pagamento está pendente e passam a vendidos quando o pedido é pago
 type Purchase struct {
-	ID           int       `json:"id"`
-	RewardID     uuid.UUID `json:"rewardId"`
-	RewardName   string    `json:"rewardName"`
-	RewardImage  string    `json:"rewardImage"`
-	Numbers      []int     `json:"numbers"`
-	PurchaseDate time.Time `json:"purchaseDate"`
-	TotalAmount  float64   `json:"totalAmount"`
-	Status       string    `json:"status"`
+	ID              uuid.UUID   `json:"id"`
+	RewardID        uuid.UUID   `json:"rewardId"`
+	RewardName      string      `json:"rewardName"`
+	RewardImage     string      `json:"rewardImage"`
+	UserID          uuid.UUID   `json:"userId"`
+	Numbers         []int       `json:"numbers"`
+	Quantity        int         `json:"quantity"`
+	UnitPrice       money.Money `json:"unitPrice" swaggertype:"number"`
+	TotalAmount     money.Money `json:"totalAmount" swaggertype:"number"`
+	DiscountAmount  money.Money `json:"discountAmount" swaggertype:"number"`
+	BonusNumbers    int         `json:"bonusNumbers"`
+	CouponCode      *string     `json:"couponCode,omitempty"`
+	PackageID       *uuid.UUID  `json:"packageId,omitempty"`
+	PaymentStatus   string      `json:"paymentStatus"`
+	PaymentProvider *string     `json:"paymentProvider,omitempty"`
+	ChargeID        *string     `json:"chargeId,omitempty"`
+	PixCopyPaste    *string     `json:"pixCopyPaste,omitempty"`
+	Status          string      `json:"status"`
+	PurchaseDate    time.Time   `json:"purchaseDate"`
+	ExpiresAt       time.Time   `json:"expiresAt"`
+	PaidAt          *time.Time  `json:"paidAt,omitempty"`
+	CancelledAt     *time.Time  `json:"cancelledAt,omitempty"`
+	CancelledBy     *uuid.UUID  `json:"cancelledBy,omitempty"`
+	CancelReason    *string     `json:"cancelReason,omitempty"`
+	UpdatedAt       time.Time   `json:"updatedAt"`
+}
+
+// PurchasePixResponse representa o código PIX para pagamento de um pedido pendente
+type PurchasePixResponse struct {
+	PurchaseID      uuid.UUID   `json:"purchase_id"`
+	PaymentProvider string      `json:"payment_provider"`
+	TxID            string      `json:"txid"`
+	Amount          money.Money `json:"amount" swaggertype:"number"`
+	PixCopyPaste    string      `json:"pix_copy_paste"`
+	ExpiresAt       time.Time   `json:"expires_at"`
 }
 
 // PurchaseListResponse representa a resposta da listagem de compras
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+// Tipos de transação do razão
+const (
+	LedgerPurchase      = "purchase"
+	LedgerRefund        = "refund"
+	LedgerPrizeCredit   = "prize_credit"
+	LedgerReferralBonus = "referral_bonus"
+)
+
+// Contas do sistema usadas como contrapartida dos lançamentos nas carteiras
+const (
+	LedgerAccountSales     = "sales"
+	LedgerAccountPrizes    = "prizes"
+	LedgerAccountReferrals = "referrals"
+)
+
+// Wallet representa o saldo da carteira de um usuário
+type Wallet struct {
+	UserID  uuid.UUID   `json:"user_id"`
+	Balance money.Money `json:"balance" swaggertype:"number"`
+}
+
+// LedgerEntry representa um lançamento na carteira de um usuário
+type LedgerEntry struct {
+	ID            int64       `json:"id"`
+	TransactionID uuid.UUID   `json:"transaction_id"`
+	Type          string      `json:"type"`
+	ReferenceID   *uuid.UUID  `json:"reference_id,omitempty"`
+	Description   *string     `json:"description,omitempty"`
+	Amount        money.Money `json:"amount" swaggertype:"number"`
+	BalanceAfter  money.Money `json:"balance_after" swaggertype:"number"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// LedgerEntryListResponse representa a resposta da listagem de lançamentos da carteira
+type LedgerEntryListResponse struct {
+	Wallet     Wallet        `json:"wallet"`
+	Entries    []LedgerEntry `json:"entries"`
+	Pagination Pagination    `json:"pagination"`
+}
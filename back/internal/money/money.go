@@ -0,0 +1,271 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency é a moeda dos valores da plataforma
+const DefaultCurrency = "BRL"
+
+var (
+	// ErrCurrencyMismatch indica uma operação entre valores de moedas diferentes
+	ErrCurrencyMismatch = errors.New("operação entre valores de moedas diferentes")
+	// ErrOverflow indica que o resultado de uma operação não cabe em um valor monetário
+	ErrOverflow = errors.New("valor monetário fora do limite")
+	// ErrInvalidAmount indica um valor que não é um número decimal com até duas casas
+	ErrInvalidAmount = errors.New("valor monetário deve ser um número com até 2 casas decimais")
+)
+
+// Money representa um valor monetário em centavos com o código da moeda (ISO 4217).
+// O valor zero é R$ 0,00. Em JSON é codificado como número decimal com duas casas ("12.50")
+// e no banco é gravado em colunas DECIMAL, sem passar por ponto flutuante
+type Money struct {
+	cents    int64
+	currency string
+}
+
+// FromCents cria um valor em reais a partir dos centavos
+func FromCents(cents int64) Money {
+	return Money{cents: cents, currency: DefaultCurrency}
+}
+
+// New cria um valor em centavos na moeda informada
+func New(cents int64, currency string) Money {
+	return Money{cents: cents, currency: strings.ToUpper(currency)}
+}
+
+// Parse lê um valor em reais no formato decimal ("12", "12.5", "-0.35")
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || len(fraction) > 2 || !isDigits(whole) || (fraction != "" && !isDigits(fraction)) {
+		return Money{}, ErrInvalidAmount
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (math.MaxInt64-99)/100 {
+		return Money{}, ErrOverflow
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+	cents += units * 100
+	if negative {
+		cents = -cents
+	}
+
+	return FromCents(cents), nil
+}
+
+// Sum soma valores da mesma moeda. Sem valores o resultado é R$ 0,00
+func Sum(values ...Money) (Money, error) {
+	total := Money{}
+	for _, value := range values {
+		var err error
+		if total, err = total.Add(value); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Cents retorna o valor em centavos
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// Currency retorna o código da moeda
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// IsZero informa se o valor é zero
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+// IsPositive informa se o valor é maior que zero
+func (m Money) IsPositive() bool {
+	return m.cents > 0
+}
+
+// IsNegative informa se o valor é menor que zero
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+// Add soma dois valores da mesma moeda
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency() != other.Currency() {
+		return Money{}, ErrCurrencyMismatch
+	}
+	if (other.cents > 0 && m.cents > math.MaxInt64-other.cents) || (other.cents < 0 && m.cents < math.MinInt64-other.cents) {
+		return Money{}, ErrOverflow
+	}
+	return New(m.cents+other.cents, m.Currency()), nil
+}
+
+// Sub subtrai um valor da mesma moeda
+func (m Money) Sub(other Money) (Money, error) {
+	negated, err := other.Neg()
+	if err != nil {
+		return Money{}, err
+	}
+	return m.Add(negated)
+}
+
+// Neg retorna o valor com o sinal invertido
+func (m Money) Neg() (Money, error) {
+	if m.cents == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return New(-m.cents, m.Currency()), nil
+}
+
+// Mul multiplica o valor por uma quantidade, como o preço unitário pelo número de cotas
+func (m Money) Mul(quantity int64) (Money, error) {
+	if m.cents == 0 || quantity == 0 {
+		return New(0, m.Currency()), nil
+	}
+	result := m.cents * quantity
+	if result/quantity != m.cents || (m.cents == -1 && quantity == math.MinInt64) || (quantity == -1 && m.cents == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+	return New(result, m.Currency()), nil
+}
+
+// Percent calcula o percentual informado do valor (com até duas casas, como em taxas de 2,5%),
+// arredondando meio centavo para longe do zero
+func (m Money) Percent(percent float64) (Money, error) {
+	basisPoints := math.Round(percent * 100)
+	if math.IsNaN(basisPoints) || math.Abs(basisPoints) > 1e6 {
+		return Money{}, ErrOverflow
+	}
+
+	product, err := m.Mul(int64(basisPoints))
+	if err != nil {
+		return Money{}, err
+	}
+
+	cents := product.cents / 10000
+	if remainder := product.cents % 10000; remainder >= 5000 {
+		cents++
+	} else if remainder <= -5000 {
+		cents--
+	}
+	return New(cents, m.Currency()), nil
+}
+
+// Cmp compara dois valores da mesma moeda: -1 se m for menor, 0 se forem iguais e 1 se m for maior
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency() != other.Currency() {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.cents < other.cents:
+		return -1, nil
+	case m.cents > other.cents:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Equal informa se dois valores têm a mesma moeda e o mesmo valor
+func (m Money) Equal(other Money) bool {
+	return m.Currency() == other.Currency() && m.cents == other.cents
+}
+
+// String formata o valor como número decimal com duas casas ("1234.50")
+func (m Money) String() string {
+	cents := m.cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+	}
+	whole := cents / 100
+	fraction := cents % 100
+	if fraction < 0 {
+		whole, fraction = -whole, -fraction
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, whole, fraction)
+}
+
+// MarshalJSON codifica o valor como número decimal com duas casas
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON lê o valor de um número ou de uma string decimal com até duas casas
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	parsed, err := Parse(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan lê o valor de uma coluna DECIMAL do banco
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case []byte:
+		return m.scanText(string(v))
+	case string:
+		return m.scanText(v)
+	case int64:
+		parsed, err := FromCents(v).Mul(100)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+	return fmt.Errorf("tipo %T não pode ser lido como valor monetário", src)
+}
+
+// scanText lê o texto de uma coluna DECIMAL, que pode ter mais casas que os centavos quando vem de um cálculo
+func (m *Money) scanText(text string) error {
+	if whole, fraction, ok := strings.Cut(text, "."); ok && len(fraction) > 2 {
+		if strings.TrimRight(fraction[2:], "0") != "" {
+			return fmt.Errorf("valor %s tem frações de centavo", text)
+		}
+		text = whole + "." + fraction[:2]
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value grava o valor em uma coluna DECIMAL do banco
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,45 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   Money
+		percent float64
+		want    Money
+		wantErr error
+	}{
+		{name: "taxa com casas decimais", value: FromCents(1000), percent: 2.5, want: FromCents(25)},
+		{name: "meio centavo arredonda para cima", value: FromCents(10), percent: 5, want: FromCents(1)},
+		{name: "menos de meio centavo arredonda para baixo", value: FromCents(10), percent: 4.9, want: FromCents(0)},
+		{name: "meio centavo negativo arredonda para longe do zero", value: FromCents(-10), percent: 5, want: FromCents(-1)},
+		{name: "terceira casa decimal", value: FromCents(123456), percent: 10, want: FromCents(12346)},
+		{name: "percentual zero", value: FromCents(123456), percent: 0, want: FromCents(0)},
+		{name: "mantém a moeda", value: New(1000, "USD"), percent: 10, want: New(100, "USD")},
+		{name: "percentual inválido", value: FromCents(1000), percent: math.NaN(), wantErr: ErrOverflow},
+		{name: "resultado fora do limite", value: FromCents(math.MaxInt64 / 100), percent: 50, wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.Percent(tt.percent)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Percent(%v) erro = %v, esperado %v", tt.percent, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Percent(%v) erro inesperado: %v", tt.percent, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("%s.Percent(%v) = %s %s, esperado %s %s", tt.value, tt.percent, got, got.Currency(), tt.want, tt.want.Currency())
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+)
+
+// FakeProvider guarda as cobranças em memória, para desenvolvimento e testes.
+// Com autoApprove as cobranças são consideradas pagas na primeira consulta
+type FakeProvider struct {
+	autoApprove bool
+
+	mu      sync.Mutex
+	charges map[string]*Charge
+	refunds map[string]*Refund
+}
+
+// NewFakeProvider cria uma nova instância do provedor de pagamento falso
+func NewFakeProvider(autoApprove bool) *FakeProvider {
+	return &FakeProvider{
+		autoApprove: autoApprove,
+		charges:     make(map[string]*Charge),
+		refunds:     make(map[string]*Refund),
+	}
+}
+
+// Name identifica o provedor
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// CreateCharge cria uma cobrança pendente
+func (p *FakeProvider) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge := &Charge{
+		ID:        req.TxID,
+		Status:    ChargePending,
+		Amount:    req.Amount,
+		CopyPaste: "FAKE-PIX-" + req.TxID,
+	}
+	p.charges[req.TxID] = charge
+
+	copied := *charge
+	return &copied, nil
+}
+
+// GetCharge consulta uma cobrança
+func (p *FakeProvider) GetCharge(ctx context.Context, chargeID string) (*Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	if p.autoApprove && charge.Status == ChargePending {
+		p.markPaid(charge)
+	}
+
+	copied := *charge
+	return &copied, nil
+}
+
+// Pay marca uma cobrança como paga, simulando o pagamento pelo comprador
+func (p *FakeProvider) Pay(chargeID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return ErrChargeNotFound
+	}
+	if charge.Status != ChargePending {
+		return errors.New("cobrança não está pendente")
+	}
+
+	p.markPaid(charge)
+	return nil
+}
+
+// Refund devolve o valor de uma cobrança paga
+func (p *FakeProvider) Refund(ctx context.Context, chargeID, refundID string, amount money.Money) (*Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if charge.Status != ChargePaid {
+		return nil, errors.New("apenas cobranças pagas podem ser devolvidas")
+	}
+
+	refund := &Refund{ID: refundID, ChargeID: chargeID, Amount: amount, Status: RefundCompleted}
+	p.refunds[refundID] = refund
+
+	copied := *refund
+	return &copied, nil
+}
+
+func (p *FakeProvider) markPaid(charge *Charge) {
+	now := time.Now()
+	charge.Status = ChargePaid
+	charge.PaidAmount = charge.Amount
+	charge.PaidAt = &now
+}
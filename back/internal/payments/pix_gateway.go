@@ -0,0 +1,200 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+)
+
+// PixGatewayProvider integra com gateways que seguem a API Pix do Banco Central
+// (cobranças imediatas em /cob e devoluções em /pix/{e2eid}/devolucao)
+type PixGatewayProvider struct {
+	baseURL string
+	token   string
+	pixKey  string
+	client  *http.Client
+}
+
+// NewPixGatewayProvider cria uma nova instância do provedor PIX
+func NewPixGatewayProvider(baseURL, token, pixKey string) *PixGatewayProvider {
+	return &PixGatewayProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		pixKey:  pixKey,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// pixCob representa uma cobrança imediata da API Pix
+type pixCob struct {
+	TxID       string `json:"txid,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Calendario struct {
+		Expiracao int `json:"expiracao,omitempty"`
+	} `json:"calendario"`
+	Valor struct {
+		Original string `json:"original"`
+	} `json:"valor"`
+	Chave              string `json:"chave,omitempty"`
+	SolicitacaoPagador string `json:"solicitacaoPagador,omitempty"`
+	PixCopiaECola      string `json:"pixCopiaECola,omitempty"`
+	Pix                []struct {
+		EndToEndID string    `json:"endToEndId"`
+		Valor      string    `json:"valor"`
+		Horario    time.Time `json:"horario"`
+	} `json:"pix,omitempty"`
+}
+
+// pixDevolucao representa uma devolução da API Pix
+type pixDevolucao struct {
+	ID     string `json:"id,omitempty"`
+	Valor  string `json:"valor"`
+	Status string `json:"status,omitempty"`
+}
+
+// Name identifica o provedor
+func (p *PixGatewayProvider) Name() string {
+	return "pix_gateway"
+}
+
+// CreateCharge cria uma cobrança imediata com o txid do pedido
+func (p *PixGatewayProvider) CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	var body pixCob
+	body.Calendario.Expiracao = int(req.ExpiresIn.Seconds())
+	body.Valor.Original = req.Amount.String()
+	body.Chave = p.pixKey
+	body.SolicitacaoPagador = req.Description
+
+	var cob pixCob
+	if err := p.do(ctx, http.MethodPut, "/cob/"+url.PathEscape(req.TxID), body, &cob); err != nil {
+		return nil, err
+	}
+
+	return cob.toCharge()
+}
+
+// GetCharge consulta uma cobrança imediata
+func (p *PixGatewayProvider) GetCharge(ctx context.Context, chargeID string) (*Charge, error) {
+	var cob pixCob
+	if err := p.do(ctx, http.MethodGet, "/cob/"+url.PathEscape(chargeID), nil, &cob); err != nil {
+		return nil, err
+	}
+
+	return cob.toCharge()
+}
+
+// Refund solicita a devolução do PIX recebido na cobrança
+func (p *PixGatewayProvider) Refund(ctx context.Context, chargeID, refundID string, amount money.Money) (*Refund, error) {
+	var cob pixCob
+	if err := p.do(ctx, http.MethodGet, "/cob/"+url.PathEscape(chargeID), nil, &cob); err != nil {
+		return nil, err
+	}
+	if len(cob.Pix) == 0 {
+		return nil, fmt.Errorf("cobrança %s não possui pagamento para devolver", chargeID)
+	}
+
+	body := pixDevolucao{Valor: amount.String()}
+	path := "/pix/" + url.PathEscape(cob.Pix[0].EndToEndID) + "/devolucao/" + url.PathEscape(refundID)
+
+	var devolucao pixDevolucao
+	if err := p.do(ctx, http.MethodPut, path, body, &devolucao); err != nil {
+		return nil, err
+	}
+
+	status := RefundProcessing
+	switch devolucao.Status {
+	case "DEVOLVIDO":
+		status = RefundCompleted
+	case "NAO_REALIZADO":
+		status = RefundFailed
+	}
+
+	return &Refund{ID: refundID, ChargeID: chargeID, Amount: amount, Status: status}, nil
+}
+
+// do executa uma requisição autenticada no gateway e decodifica a resposta em out
+func (p *PixGatewayProvider) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var reader io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao chamar gateway PIX: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrChargeNotFound
+	}
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("gateway PIX respondeu %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// toCharge converte a cobrança da API Pix para o formato comum dos provedores
+func (c *pixCob) toCharge() (*Charge, error) {
+	amount, err := money.Parse(c.Valor.Original)
+	if err != nil {
+		return nil, fmt.Errorf("valor inválido na cobrança %s: %w", c.TxID, err)
+	}
+
+	charge := &Charge{
+		ID:        c.TxID,
+		Amount:    amount,
+		CopyPaste: c.PixCopiaECola,
+	}
+
+	switch c.Status {
+	case "CONCLUIDA":
+		charge.Status = ChargePaid
+		// O valor recebido é a soma dos PIX da cobrança, que pode diferir do valor original
+		charge.PaidAmount = money.New(0, amount.Currency())
+		for _, pix := range c.Pix {
+			received, err := money.Parse(pix.Valor)
+			if err != nil {
+				return nil, fmt.Errorf("valor inválido no pagamento %s da cobrança %s: %w", pix.EndToEndID, c.TxID, err)
+			}
+			if charge.PaidAmount, err = charge.PaidAmount.Add(received); err != nil {
+				return nil, err
+			}
+		}
+		if len(c.Pix) > 0 {
+			paidAt := c.Pix[0].Horario
+			charge.PaidAt = &paidAt
+		}
+	case "REMOVIDA_PELO_USUARIO_RECEBEDOR":
+		charge.Status = ChargeCancelled
+	case "REMOVIDA_PELO_PSP":
+		charge.Status = ChargeExpired
+	default:
+		charge.Status = ChargePending
+	}
+
+	return charge, nil
+}
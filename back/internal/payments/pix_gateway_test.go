@@ -0,0 +1,83 @@
+package payments
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+)
+
+func TestPixCobToCharge(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		wantStatus     string
+		wantAmount     money.Money
+		wantPaidAmount money.Money
+		wantErr        bool
+	}{
+		{
+			name:       "pendente",
+			body:       `{"txid":"abc","status":"ATIVA","valor":{"original":"30.00"}}`,
+			wantStatus: ChargePending,
+			wantAmount: money.FromCents(3000),
+		},
+		{
+			name:           "paga com o valor cobrado",
+			body:           `{"txid":"abc","status":"CONCLUIDA","valor":{"original":"30.00"},"pix":[{"endToEndId":"E1","valor":"30.00","horario":"2026-03-10T12:00:00Z"}]}`,
+			wantStatus:     ChargePaid,
+			wantAmount:     money.FromCents(3000),
+			wantPaidAmount: money.FromCents(3000),
+		},
+		{
+			name:           "paga com valor menor",
+			body:           `{"txid":"abc","status":"CONCLUIDA","valor":{"original":"30.00"},"pix":[{"endToEndId":"E1","valor":"10.50","horario":"2026-03-10T12:00:00Z"}]}`,
+			wantStatus:     ChargePaid,
+			wantAmount:     money.FromCents(3000),
+			wantPaidAmount: money.FromCents(1050),
+		},
+		{
+			name:           "paga em mais de um PIX",
+			body:           `{"txid":"abc","status":"CONCLUIDA","valor":{"original":"30.00"},"pix":[{"endToEndId":"E1","valor":"10.00","horario":"2026-03-10T12:00:00Z"},{"endToEndId":"E2","valor":"20.00","horario":"2026-03-10T12:05:00Z"}]}`,
+			wantStatus:     ChargePaid,
+			wantAmount:     money.FromCents(3000),
+			wantPaidAmount: money.FromCents(3000),
+		},
+		{
+			name:           "concluída sem PIX",
+			body:           `{"txid":"abc","status":"CONCLUIDA","valor":{"original":"30.00"}}`,
+			wantStatus:     ChargePaid,
+			wantAmount:     money.FromCents(3000),
+			wantPaidAmount: money.FromCents(0),
+		},
+		{
+			name:    "valor recebido inválido",
+			body:    `{"txid":"abc","status":"CONCLUIDA","valor":{"original":"30.00"},"pix":[{"endToEndId":"E1","valor":"trinta","horario":"2026-03-10T12:00:00Z"}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cob pixCob
+			if err := json.Unmarshal([]byte(tt.body), &cob); err != nil {
+				t.Fatalf("json.Unmarshal() erro inesperado: %v", err)
+			}
+
+			charge, err := cob.toCharge()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("toCharge() = %+v, esperado erro", charge)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toCharge() erro inesperado: %v", err)
+			}
+			if charge.Status != tt.wantStatus || !charge.Amount.Equal(tt.wantAmount) || !charge.PaidAmount.Equal(tt.wantPaidAmount) {
+				t.Errorf("toCharge() = {%s, %s, %s}, esperado {%s, %s, %s}", charge.Status, charge.Amount, charge.PaidAmount,
+					tt.wantStatus, tt.wantAmount, tt.wantPaidAmount)
+			}
+		})
+	}
+}
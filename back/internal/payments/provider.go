@@ -0,0 +1,110 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/config"
+	"github.com/cauamistura/BNUPremios/internal/money"
+)
+
+// Situações de uma cobrança no provedor de pagamento
+const (
+	ChargePending   = "pending"
+	ChargePaid      = "paid"
+	ChargeExpired   = "expired"
+	ChargeCancelled = "cancelled"
+)
+
+// Situações de uma devolução no provedor de pagamento
+const (
+	RefundProcessing = "processing"
+	RefundCompleted  = "completed"
+	RefundFailed     = "failed"
+)
+
+// DirectPixProvider identifica os pedidos pagos por PIX direto na chave do organizador, sem provedor.
+// Esses pagamentos não podem ser consultados e são confirmados pelo próprio organizador
+const DirectPixProvider = "pix_direct"
+
+// WalletProvider identifica os pedidos pagos com o saldo da carteira do comprador
+const WalletProvider = "wallet"
+
+// ErrChargeNotFound indica que o provedor não conhece a cobrança informada
+var ErrChargeNotFound = errors.New("cobrança não encontrada")
+
+// ChargeRequest representa os dados para criar uma cobrança
+type ChargeRequest struct {
+	// TxID identifica a cobrança no provedor e é derivado do pedido de compra
+	TxID        string
+	Amount      money.Money
+	Description string
+	ExpiresIn   time.Duration
+}
+
+// Charge representa uma cobrança criada no provedor de pagamento. Amount é o valor cobrado e PaidAmount o valor
+// efetivamente recebido, preenchido quando a cobrança é paga
+type Charge struct {
+	ID         string
+	Status     string
+	Amount     money.Money
+	PaidAmount money.Money
+	CopyPaste  string
+	PaidAt     *time.Time
+}
+
+// Refund representa uma devolução de uma cobrança paga
+type Refund struct {
+	ID       string
+	ChargeID string
+	Amount   money.Money
+	Status   string
+}
+
+// PaymentProvider é implementado pelos provedores de pagamento aceitos pela API
+type PaymentProvider interface {
+	// Name identifica o provedor nos pedidos de compra
+	Name() string
+	// CreateCharge cria uma cobrança com o TxID informado
+	CreateCharge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	// GetCharge consulta a situação atual de uma cobrança
+	GetCharge(ctx context.Context, chargeID string) (*Charge, error)
+	// Refund devolve parte ou todo o valor de uma cobrança paga
+	Refund(ctx context.Context, chargeID, refundID string, amount money.Money) (*Refund, error)
+}
+
+// NewProvider cria o provedor de pagamento configurado em PAYMENT_PROVIDER
+func NewProvider(cfg config.PaymentConfig) (PaymentProvider, error) {
+	switch cfg.Provider {
+	case "fake":
+		return NewFakeProvider(cfg.FakeAutoApprove), nil
+	case "pix_gateway":
+		if cfg.PixGatewayURL == "" || cfg.PixKey == "" {
+			return nil, errors.New("PIX_GATEWAY_URL e PIX_KEY são obrigatórios para o provedor pix_gateway")
+		}
+		return NewPixGatewayProvider(cfg.PixGatewayURL, cfg.PixGatewayToken, cfg.PixKey), nil
+	default:
+		return nil, fmt.Errorf("provedor de pagamento desconhecido: %s", cfg.Provider)
+	}
+}
+
+// SignWebhook calcula a assinatura (HMAC-SHA256 em hexadecimal) do corpo de um webhook
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook confere a assinatura de um webhook em tempo constante
+func VerifyWebhook(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	expected := SignWebhook(secret, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
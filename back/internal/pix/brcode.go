@@ -0,0 +1,222 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Limites do padrão BR Code definidos no Manual de Padrões para Iniciação do PIX
+const (
+	MaxKeyLength          = 77
+	MaxMerchantNameLength = 25
+	MaxMerchantCityLength = 15
+	MaxStaticTxIDLength   = 25
+	maxFieldLength        = 99
+)
+
+// Identificadores dos campos EMV usados no BR Code
+const (
+	fieldPayloadFormat   = "00"
+	fieldInitiation      = "01"
+	fieldMerchantAccount = "26"
+	fieldCategoryCode    = "52"
+	fieldCurrency        = "53"
+	fieldAmount          = "54"
+	fieldCountryCode     = "58"
+	fieldMerchantName    = "59"
+	fieldMerchantCity    = "60"
+	fieldAdditionalData  = "62"
+	fieldCRC             = "63"
+
+	accountGUI         = "00"
+	accountKey         = "01"
+	accountDescription = "02"
+	accountLocation    = "25"
+	additionalTxID     = "05"
+)
+
+// Payload representa os dados de uma cobrança PIX no padrão BR Code (EMV QR Code).
+// Com Location o código é dinâmico e os dados da cobrança ficam no PSP; sem ele é estático
+// e leva a chave, o valor e o txid no próprio código
+type Payload struct {
+	Key          string
+	MerchantName string
+	MerchantCity string
+	Amount       money.Money
+	TxID         string
+	Description  string
+	Location     string
+}
+
+// Encode gera o código "copia e cola" com o CRC16 no final
+func (p Payload) Encode() (string, error) {
+	if p.Key == "" && p.Location == "" {
+		return "", errors.New("chave PIX ou location são obrigatórios")
+	}
+
+	if p.Amount.Currency() != money.DefaultCurrency {
+		return "", errors.New("o BR Code aceita apenas valores em reais")
+	}
+
+	name := normalizeText(p.MerchantName, MaxMerchantNameLength)
+	city := normalizeText(p.MerchantCity, MaxMerchantCityLength)
+	if name == "" || city == "" {
+		return "", errors.New("nome e cidade do recebedor são obrigatórios")
+	}
+
+	txid := "***"
+	account := field(accountGUI, "br.gov.bcb.pix")
+	if p.Location != "" {
+		account += field(accountLocation, strings.TrimPrefix(p.Location, "https://"))
+	} else {
+		account += field(accountKey, p.Key)
+		if p.TxID != "" {
+			if len(p.TxID) > MaxStaticTxIDLength || !isAlphanumeric(p.TxID) {
+				return "", fmt.Errorf("txid deve ter até %d caracteres alfanuméricos", MaxStaticTxIDLength)
+			}
+			txid = p.TxID
+		}
+	}
+	if description := normalizeText(p.Description, maxFieldLength-len(account)-4); description != "" {
+		account += field(accountDescription, description)
+	}
+	if len(account) > maxFieldLength {
+		return "", errors.New("dados da conta do recebedor excedem o tamanho do BR Code")
+	}
+
+	var b strings.Builder
+	b.WriteString(field(fieldPayloadFormat, "01"))
+	if p.Location != "" {
+		// Códigos dinâmicos são de uso único
+		b.WriteString(field(fieldInitiation, "12"))
+	}
+	b.WriteString(field(fieldMerchantAccount, account))
+	b.WriteString(field(fieldCategoryCode, "0000"))
+	b.WriteString(field(fieldCurrency, "986"))
+	if p.Amount.IsPositive() {
+		b.WriteString(field(fieldAmount, p.Amount.String()))
+	}
+	b.WriteString(field(fieldCountryCode, "BR"))
+	b.WriteString(field(fieldMerchantName, name))
+	b.WriteString(field(fieldMerchantCity, city))
+	b.WriteString(field(fieldAdditionalData, field(additionalTxID, txid)))
+	b.WriteString(fieldCRC + "04")
+
+	code := b.String()
+	return code + CRC16(code), nil
+}
+
+// CRC16 calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) exigido no campo 63 do BR Code
+func CRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+// QRCodePNG gera a imagem PNG do QR Code de um código "copia e cola"
+func QRCodePNG(code string, size int) ([]byte, error) {
+	return qrcode.Encode(code, qrcode.Medium, size)
+}
+
+// NormalizeKey valida uma chave PIX (CPF, CNPJ, e-mail, telefone ou chave aleatória)
+// e a devolve no formato usado no BR Code
+func NormalizeKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	switch {
+	case key == "":
+		return "", errors.New("chave PIX é obrigatória")
+	case len(key) > MaxKeyLength:
+		return "", errors.New("chave PIX muito longa")
+	case strings.Contains(key, "@"):
+		return strings.ToLower(key), nil
+	case strings.HasPrefix(key, "+"):
+		digits := key[1:]
+		if !isDigits(digits) || !strings.HasPrefix(digits, "55") || len(digits) < 12 || len(digits) > 13 {
+			return "", errors.New("telefone da chave PIX deve estar no formato +55DDNNNNNNNNN")
+		}
+		return key, nil
+	}
+
+	if _, err := uuid.Parse(key); err == nil {
+		return strings.ToLower(key), nil
+	}
+
+	// CPF e CNPJ podem ser informados com pontuação
+	digits := strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '/' {
+			return -1
+		}
+		return r
+	}, key)
+	if isDigits(digits) && (len(digits) == 11 || len(digits) == 14) {
+		return digits, nil
+	}
+
+	return "", errors.New("chave PIX inválida")
+}
+
+// field codifica um campo EMV: identificador, tamanho com dois dígitos e valor
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// normalizeText remove acentos e caracteres fora do ASCII e limita o tamanho, como exigem os leitores de BR Code
+func normalizeText(text string, limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	text, _, _ = transform.String(t, text)
+	text = strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, text)
+
+	text = strings.ToUpper(strings.TrimSpace(text))
+	if len(text) > limit {
+		text = strings.TrimSpace(text[:limit])
+	}
+	return text
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
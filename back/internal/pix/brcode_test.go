@@ -0,0 +1,93 @@
+package pix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cauamistura/BNUPremios/internal/money"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "valor de verificação do CRC16-CCITT", data: "123456789", want: "29B1"},
+		{name: "exemplo do manual do BR Code", data: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***6304", want: "1D3D"},
+		{name: "vazio", data: "", want: "FFFF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CRC16(tt.data); got != tt.want {
+				t.Errorf("CRC16(%q) = %s, esperado %s", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadEncode(t *testing.T) {
+	tests := []struct {
+		name       string
+		payload    Payload
+		wantPrefix string
+		wantErr    bool
+	}{
+		{
+			name: "estático sem valor",
+			payload: Payload{
+				Key:          "123e4567-e12b-12d1-a456-426655440000",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "Brasília",
+			},
+			wantPrefix: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913FULANO DE TAL6008BRASILIA62070503***6304",
+		},
+		{
+			name: "estático com valor e txid",
+			payload: Payload{
+				Key:          "fulano@example.com",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "Blumenau",
+				Amount:       money.FromCents(1050),
+				TxID:         "PEDIDO123",
+			},
+			wantPrefix: "00020126400014br.gov.bcb.pix0118fulano@example.com520400005303986540510.505802BR5913FULANO DE TAL6008BLUMENAU62130509PEDIDO1236304",
+		},
+		{
+			name: "dinâmico com location",
+			payload: Payload{
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "Blumenau",
+				Location:     "https://pix.example.com/qr/v2/abc",
+			},
+			wantPrefix: "00020101021226470014br.gov.bcb.pix2525pix.example.com/qr/v2/abc5204000053039865802BR5913FULANO DE TAL6008BLUMENAU62070503***6304",
+		},
+		{name: "sem chave nem location", payload: Payload{MerchantName: "Fulano", MerchantCity: "Blumenau"}, wantErr: true},
+		{name: "sem cidade", payload: Payload{Key: "fulano@example.com", MerchantName: "Fulano"}, wantErr: true},
+		{name: "txid inválido", payload: Payload{Key: "fulano@example.com", MerchantName: "Fulano", MerchantCity: "Blumenau", TxID: "pedido-123"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := tt.payload.Encode()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Encode() = %q, esperado erro", code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode() erro inesperado: %v", err)
+			}
+
+			body, crc := code[:len(code)-4], code[len(code)-4:]
+			if !strings.HasPrefix(code, tt.wantPrefix) || len(code) != len(tt.wantPrefix)+4 {
+				t.Errorf("Encode() = %q, esperado %q seguido do CRC", code, tt.wantPrefix)
+			}
+			if want := CRC16(body); crc != want {
+				t.Errorf("Encode() termina com CRC %s, esperado %s", crc, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// couponUsesQuery conta os resgates do cupom c em pedidos pendentes ou pagos;
+// pedidos expirados ou cancelados devolvem o uso do cupom
+const couponUsesQuery = `
+	SELECT COUNT(*) FROM coupon_redemptions cr
+	INNER JOIN purchases p ON p.id = cr.purchase_id
+	WHERE cr.coupon_id = c.id AND p.payment_status IN ('pending', 'paid')`
+
+// couponColumns lista as colunas lidas por scanCoupon, na mesma ordem
+const couponColumns = `c.id, c.owner_id, c.reward_id, c.code, c.type, c.discount_percent, c.discount_amount, c.bonus_numbers,
+	c.min_quantity, c.max_uses, c.max_uses_per_user, c.starts_at, c.expires_at, c.active, (` + couponUsesQuery + `),
+	c.created_at, c.updated_at`
+
+// CouponRepository implementa as operações de banco de dados dos cupons de desconto
+type CouponRepository struct {
+	db *sql.DB
+}
+
+// NewCouponRepository cria uma nova instância do repositório de cupons
+func NewCouponRepository(db *sql.DB) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+// Create cadastra um cupom. O código é único entre os cupons do organizador
+func (r *CouponRepository) Create(coupon *models.Coupon) (*models.Coupon, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow(`
+		INSERT INTO coupons (owner_id, reward_id, code, type, discount_percent, discount_amount, bonus_numbers,
+			min_quantity, max_uses, max_uses_per_user, starts_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`, coupon.OwnerID, coupon.RewardID, coupon.Code, coupon.Type, coupon.DiscountPercent, coupon.DiscountAmount,
+		coupon.BonusNumbers, coupon.MinQuantity, coupon.MaxUses, coupon.MaxUsesPerUser, coupon.StartsAt, coupon.ExpiresAt).Scan(&id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, errors.New("já existe um cupom com este código")
+		}
+		return nil, fmt.Errorf("erro ao criar cupom: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+// GetByID busca um cupom pelo ID
+func (r *CouponRepository) GetByID(id uuid.UUID) (*models.Coupon, error) {
+	return getCoupon(r.db, id)
+}
+
+// ListByOwner lista os cupons de um organizador, do mais recente para o mais antigo
+func (r *CouponRepository) ListByOwner(ownerID uuid.UUID) ([]models.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupons c WHERE c.owner_id = $1 ORDER BY c.created_at DESC`
+
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar cupons: %w", err)
+	}
+	defer rows.Close()
+
+	coupons := []models.Coupon{}
+	for rows.Next() {
+		coupon, err := scanCoupon(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear cupom: %w", err)
+		}
+		coupons = append(coupons, *coupon)
+	}
+
+	return coupons, rows.Err()
+}
+
+// Update grava a situação, os limites de uso e a validade de um cupom
+func (r *CouponRepository) Update(coupon *models.Coupon) error {
+	_, err := r.db.Exec(`
+		UPDATE coupons
+		SET active = $2, max_uses = $3, max_uses_per_user = $4, starts_at = $5, expires_at = $6, updated_at = NOW()
+		WHERE id = $1
+	`, coupon.ID, coupon.Active, coupon.MaxUses, coupon.MaxUsesPerUser, coupon.StartsAt, coupon.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar cupom: %w", err)
+	}
+	return nil
+}
+
+// ListRedemptions lista os resgates de um cupom, do mais recente para o mais antigo
+func (r *CouponRepository) ListRedemptions(couponID uuid.UUID) ([]models.CouponRedemption, error) {
+	query := `
+		SELECT cr.id, cr.coupon_id, cr.purchase_id, cr.user_id, p.payment_status, cr.discount_amount, cr.bonus_numbers, cr.created_at
+		FROM coupon_redemptions cr
+		INNER JOIN purchases p ON p.id = cr.purchase_id
+		WHERE cr.coupon_id = $1
+		ORDER BY cr.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, couponID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar resgates: %w", err)
+	}
+	defer rows.Close()
+
+	redemptions := []models.CouponRedemption{}
+	for rows.Next() {
+		var redemption models.CouponRedemption
+		err := rows.Scan(&redemption.ID, &redemption.CouponID, &redemption.PurchaseID, &redemption.UserID,
+			&redemption.PaymentStatus, &redemption.DiscountAmount, &redemption.BonusNumbers, &redemption.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear resgate: %w", err)
+		}
+		redemptions = append(redemptions, redemption)
+	}
+
+	return redemptions, rows.Err()
+}
+
+// lockCouponForReward bloqueia o cupom de código informado que vale para o prêmio e confere os limites de uso.
+// O bloqueio serializa os pedidos que usam o mesmo cupom, então os limites não são ultrapassados
+func lockCouponForReward(tx *sql.Tx, ownerID, rewardID, userID uuid.UUID, code string) (*models.Coupon, error) {
+	var id uuid.UUID
+	err := tx.QueryRow(`
+		SELECT id FROM coupons
+		WHERE owner_id = $1 AND UPPER(code) = UPPER($3) AND (reward_id IS NULL OR reward_id = $2)
+		FOR UPDATE
+	`, ownerID, rewardID, code).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, &models.CouponError{Message: "cupom não encontrado"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cupom: %w", err)
+	}
+
+	// Os usos são contados depois do bloqueio, enxergando os resgates dos pedidos concluídos enquanto esperava
+	coupon, err := getCoupon(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if coupon.MaxUses != nil && coupon.Uses >= *coupon.MaxUses {
+		return nil, &models.CouponError{Message: "cupom esgotado"}
+	}
+
+	if coupon.MaxUsesPerUser != nil {
+		var userUses int
+		err := tx.QueryRow(`
+			SELECT COUNT(*) FROM coupon_redemptions cr
+			INNER JOIN purchases p ON p.id = cr.purchase_id
+			WHERE cr.coupon_id = $1 AND cr.user_id = $2 AND p.payment_status IN ('pending', 'paid')
+		`, id, userID).Scan(&userUses)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao contar usos do cupom: %w", err)
+		}
+		if userUses >= *coupon.MaxUsesPerUser {
+			return nil, &models.CouponError{Message: "limite de uso do cupom por usuário atingido"}
+		}
+	}
+
+	return coupon, nil
+}
+
+// insertCouponRedemption registra o resgate de um cupom em um pedido
+func insertCouponRedemption(tx *sql.Tx, couponID, purchaseID, userID uuid.UUID, discount money.Money, bonusNumbers int) error {
+	_, err := tx.Exec(`
+		INSERT INTO coupon_redemptions (coupon_id, purchase_id, user_id, discount_amount, bonus_numbers)
+		VALUES ($1, $2, $3, $4, $5)
+	`, couponID, purchaseID, userID, discount, bonusNumbers)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar resgate do cupom: %w", err)
+	}
+	return nil
+}
+
+// getCoupon busca um cupom pelo ID, dentro ou fora de uma transação
+func getCoupon(q queryer, id uuid.UUID) (*models.Coupon, error) {
+	coupon, err := scanCoupon(q.QueryRow(`SELECT `+couponColumns+` FROM coupons c WHERE c.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("cupom não encontrado")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cupom: %w", err)
+	}
+	return coupon, nil
+}
+
+// scanCoupon lê um cupom a partir das colunas de couponColumns
+func scanCoupon(row rowScanner) (*models.Coupon, error) {
+	var c models.Coupon
+	err := row.Scan(&c.ID, &c.OwnerID, &c.RewardID, &c.Code, &c.Type, &c.DiscountPercent, &c.DiscountAmount, &c.BonusNumbers,
+		&c.MinQuantity, &c.MaxUses, &c.MaxUsesPerUser, &c.StartsAt, &c.ExpiresAt, &c.Active, &c.Uses,
+		&c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
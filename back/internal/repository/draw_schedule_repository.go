@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/google/uuid"
+)
+
+// drawSchedulerLockKey identifica o advisory lock do agendador de sorteios no Postgres
+const drawSchedulerLockKey = "bnupremios:draw_scheduler"
+
+// DrawScheduleRepository implementa as operações de banco de dados do agendador de sorteios
+type DrawScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewDrawScheduleRepository cria uma nova instância do repositório do agendador de sorteios
+func NewDrawScheduleRepository(db *sql.DB) *DrawScheduleRepository {
+	return &DrawScheduleRepository{db: db}
+}
+
+// TryLock tenta obter o advisory lock do agendador. Apenas uma instância da API o mantém por vez;
+// a função retornada libera o lock e devolve a conexão ao pool
+func (r *DrawScheduleRepository) TryLock() (bool, func(), error) {
+	ctx := context.Background()
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, drawSchedulerLockKey).Scan(&acquired)
+	if err != nil || !acquired {
+		conn.Close()
+		return false, nil, err
+	}
+
+	release := func() {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, drawSchedulerLockKey); err != nil {
+			log.Printf("Erro ao liberar o lock do agendador de sorteios: %v", err)
+		}
+		conn.Close()
+	}
+
+	return true, release, nil
+}
+
+// CloseDueSales encerra as vendas dos prêmios publicados cujo horário de encerramento já passou.
+// Retorna quantos prêmios foram encerrados
+func (r *DrawScheduleRepository) CloseDueSales(now time.Time) (int64, error) {
+	query := `
+		UPDATE rewards r
+		SET status = 'sales_closed', status_changed_at = $1, updated_at = $1
+		FROM reward_details rd
+		WHERE rd.reward_id = r.id
+			AND r.status = 'published'
+			AND r.draw_date - make_interval(mins => rd.sales_close_minutes) <= $1
+	`
+
+	result, err := r.db.Exec(query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ResolveDailyTopBuyers apura o maior comprador de cada dia de vendas já encerrado dos prêmios publicados ou com
+// vendas encerradas. Retorna quantos dias foram apurados
+func (r *DrawScheduleRepository) ResolveDailyTopBuyers(now time.Time) (int64, error) {
+	return resolveDailyTopBuyers(r.db, nil, now)
+}
+
+// ListDue busca os prêmios com vendas encerradas cuja data de sorteio já passou, ainda não sorteados e prontos para
+// nova tentativa
+func (r *DrawScheduleRepository) ListDue(now time.Time, maxAttempts, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT r.id
+		FROM rewards r
+		LEFT JOIN reward_draw_failures f ON f.reward_id = r.id
+		WHERE r.status = 'sales_closed'
+			AND r.draw_date <= $1
+			AND (f.reward_id IS NULL OR (f.next_attempt_at <= $1 AND f.attempts < $2))
+		ORDER BY r.draw_date
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(query, now, maxAttempts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// ListPending busca os prêmios vencidos que ainda não foram sorteados e não possuem falhas registradas
+func (r *DrawScheduleRepository) ListPending(now time.Time) ([]models.ScheduledDraw, error) {
+	query := `
+		SELECT r.id, r.name, r.draw_date
+		FROM rewards r
+		LEFT JOIN reward_draw_failures f ON f.reward_id = r.id
+		WHERE r.status = 'sales_closed' AND r.draw_date <= $1 AND f.reward_id IS NULL
+		ORDER BY r.draw_date
+	`
+
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pending := []models.ScheduledDraw{}
+	for rows.Next() {
+		var draw models.ScheduledDraw
+		if err := rows.Scan(&draw.RewardID, &draw.RewardName, &draw.DrawDate); err != nil {
+			return nil, err
+		}
+		pending = append(pending, draw)
+	}
+
+	return pending, nil
+}
+
+// ListFailures busca as falhas registradas de prêmios ainda não sorteados
+func (r *DrawScheduleRepository) ListFailures(maxAttempts int) ([]models.DrawFailure, error) {
+	query := `
+		SELECT r.id, r.name, r.draw_date, f.attempts, COALESCE(f.last_error, ''), f.last_attempt_at, f.next_attempt_at
+		FROM reward_draw_failures f
+		INNER JOIN rewards r ON r.id = f.reward_id
+		WHERE r.status = 'sales_closed'
+		ORDER BY f.next_attempt_at
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	failures := []models.DrawFailure{}
+	for rows.Next() {
+		var failure models.DrawFailure
+		err := rows.Scan(
+			&failure.RewardID, &failure.RewardName, &failure.DrawDate, &failure.Attempts,
+			&failure.LastError, &failure.LastAttemptAt, &failure.NextAttemptAt)
+		if err != nil {
+			return nil, err
+		}
+		failure.Exhausted = failure.Attempts >= maxAttempts
+		failures = append(failures, failure)
+	}
+
+	return failures, nil
+}
+
+// GetAttempts retorna quantas tentativas de sorteio automático já falharam para um prêmio
+func (r *DrawScheduleRepository) GetAttempts(rewardID uuid.UUID) (int, error) {
+	var attempts int
+	err := r.db.QueryRow(`SELECT attempts FROM reward_draw_failures WHERE reward_id = $1`, rewardID).Scan(&attempts)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return attempts, err
+}
+
+// RecordFailure registra uma falha de sorteio e agenda a próxima tentativa
+func (r *DrawScheduleRepository) RecordFailure(rewardID uuid.UUID, drawErr error, now, nextAttemptAt time.Time) error {
+	query := `
+		INSERT INTO reward_draw_failures (reward_id, attempts, last_error, last_attempt_at, next_attempt_at, created_at, updated_at)
+		VALUES ($1, 1, $2, $3, $4, $3, $3)
+		ON CONFLICT (reward_id) DO UPDATE
+		SET attempts = reward_draw_failures.attempts + 1,
+			last_error = EXCLUDED.last_error,
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.Exec(query, rewardID, drawErr.Error(), now, nextAttemptAt)
+	return err
+}
+
+// ClearFailure remove o registro de falhas de um prêmio
+func (r *DrawScheduleRepository) ClearFailure(rewardID uuid.UUID) error {
+	_, err := r.db.Exec(`DELETE FROM reward_draw_failures WHERE reward_id = $1`, rewardID)
+	return err
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+// LedgerRepository implementa as operações do razão de partidas dobradas que sustenta as carteiras
+type LedgerRepository struct {
+	db *sql.DB
+}
+
+// NewLedgerRepository cria uma nova instância do repositório do razão
+func NewLedgerRepository(db *sql.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// ledgerLine é um lançamento a ser registrado: positivo credita e negativo debita a conta
+type ledgerLine struct {
+	accountID uuid.UUID
+	system    bool
+	amount    money.Money
+}
+
+// GetWallet busca o saldo da carteira de um usuário. Usuários sem lançamentos têm saldo zero
+func (r *LedgerRepository) GetWallet(userID uuid.UUID) (*models.Wallet, error) {
+	wallet := &models.Wallet{UserID: userID}
+	err := r.db.QueryRow(`SELECT balance FROM ledger_accounts WHERE user_id = $1`, userID).Scan(&wallet.Balance)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("erro ao buscar carteira: %w", err)
+	}
+	return wallet, nil
+}
+
+// ListEntries lista os lançamentos da carteira de um usuário, do mais recente para o mais antigo
+func (r *LedgerRepository) ListEntries(userID uuid.UUID, page, limit int) ([]models.LedgerEntry, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM ledger_entries e
+		INNER JOIN ledger_accounts a ON a.id = e.account_id
+		WHERE a.user_id = $1
+	`
+	if err := r.db.QueryRow(countQuery, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar lançamentos: %w", err)
+	}
+
+	query := `
+		SELECT e.id, e.transaction_id, t.type, t.reference_id, t.description, e.amount, e.balance_after, e.created_at
+		FROM ledger_entries e
+		INNER JOIN ledger_accounts a ON a.id = e.account_id
+		INNER JOIN ledger_transactions t ON t.id = e.transaction_id
+		WHERE a.user_id = $1
+		ORDER BY e.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar lançamentos: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.LedgerEntry{}
+	for rows.Next() {
+		var entry models.LedgerEntry
+		err := rows.Scan(&entry.ID, &entry.TransactionID, &entry.Type, &entry.ReferenceID, &entry.Description,
+			&entry.Amount, &entry.BalanceAfter, &entry.CreatedAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao escanear lançamento: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, total, rows.Err()
+}
+
+// Credit credita a carteira de um usuário tendo como contrapartida uma conta do sistema.
+// Retorna o ID da transação registrada
+func (r *LedgerRepository) Credit(userID uuid.UUID, systemAccount, kind string, referenceID *uuid.UUID, description string, amount money.Money) (uuid.UUID, error) {
+	if !amount.IsPositive() {
+		return uuid.Nil, errors.New("valor do crédito deve ser maior que zero")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	walletID, _, err := lockWallet(tx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	systemID, err := systemAccountID(tx, systemAccount)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	debit, err := amount.Neg()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	transactionID, err := postLedgerTransaction(tx, kind, referenceID, description, []ledgerLine{
+		{accountID: walletID, amount: amount},
+		{accountID: systemID, system: true, amount: debit},
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, err
+	}
+
+	return transactionID, nil
+}
+
+// lockWallet bloqueia a carteira do usuário, criando-a se ainda não existir, e retorna seu ID e saldo
+func lockWallet(tx *sql.Tx, userID uuid.UUID) (uuid.UUID, money.Money, error) {
+	_, err := tx.Exec(`INSERT INTO ledger_accounts (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`, userID)
+	if err != nil {
+		return uuid.Nil, money.Money{}, fmt.Errorf("erro ao criar carteira: %w", err)
+	}
+
+	var id uuid.UUID
+	var balance money.Money
+	err = tx.QueryRow(`SELECT id, balance FROM ledger_accounts WHERE user_id = $1 FOR UPDATE`, userID).Scan(&id, &balance)
+	if err != nil {
+		return uuid.Nil, money.Money{}, fmt.Errorf("erro ao buscar carteira: %w", err)
+	}
+
+	return id, balance, nil
+}
+
+// systemAccountID busca o ID de uma conta do sistema pelo código
+func systemAccountID(tx *sql.Tx, code string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := tx.QueryRow(`SELECT id FROM ledger_accounts WHERE code = $1`, code).Scan(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("erro ao buscar conta %s do razão: %w", code, err)
+	}
+	return id, nil
+}
+
+// postLedgerTransaction registra uma transação com seus lançamentos, que precisam somar zero.
+// Os lançamentos nas carteiras são feitos antes dos das contas do sistema, sempre na mesma ordem,
+// para que transações simultâneas bloqueiem as contas na mesma sequência
+func postLedgerTransaction(tx *sql.Tx, kind string, referenceID *uuid.UUID, description string, lines []ledgerLine) (uuid.UUID, error) {
+	amounts := make([]money.Money, len(lines))
+	for i, line := range lines {
+		amounts[i] = line.amount
+	}
+	total, err := money.Sum(amounts...)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(lines) < 2 || !total.IsZero() {
+		return uuid.Nil, errors.New("transação do razão não fecha em zero")
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].system != lines[j].system {
+			return !lines[i].system
+		}
+		return lines[i].accountID.String() < lines[j].accountID.String()
+	})
+
+	var transactionID uuid.UUID
+	err = tx.QueryRow(`
+		INSERT INTO ledger_transactions (type, reference_id, description)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, kind, referenceID, nullIfEmpty(description)).Scan(&transactionID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("erro ao registrar transação: %w", err)
+	}
+
+	for _, line := range lines {
+		_, err := tx.Exec(`INSERT INTO ledger_entries (transaction_id, account_id, amount) VALUES ($1, $2, $3)`,
+			transactionID, line.accountID, line.amount)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("erro ao registrar lançamento: %w", err)
+		}
+	}
+
+	return transactionID, nil
+}
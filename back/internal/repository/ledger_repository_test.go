@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+func TestLedgerCreditAndPayFromWallet(t *testing.T) {
+	db := openTestDB(t)
+	ledger := NewLedgerRepository(db)
+	purchases := NewPurchaseRepository(db)
+	rewardID := createTestReward(t, db, createTestUser(t, db), models.RewardOptions{TotalNumbers: intPtr(100)})
+	buyerID := createTestUser(t, db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	if _, err := ledger.Credit(buyerID, models.LedgerAccountPrizes, models.LedgerPrizeCredit, nil, "", money.FromCents(0)); err == nil {
+		t.Fatal("Credit() de valor zero deveria falhar")
+	}
+
+	creditID, err := ledger.Credit(buyerID, models.LedgerAccountPrizes, models.LedgerPrizeCredit, nil, "Crédito de teste", money.FromCents(5000))
+	if err != nil {
+		t.Fatalf("Credit() erro inesperado: %v", err)
+	}
+	assertWalletBalance(t, ledger, buyerID, money.FromCents(5000))
+	assertTransactionBalanced(t, db, creditID)
+
+	t.Run("saldo insuficiente mantém o pedido pendente", func(t *testing.T) {
+		purchase, err := purchases.Create(rewardID, buyerID, 6, nil, nil, 0, "", expiresAt)
+		if err != nil {
+			t.Fatalf("Create() erro inesperado: %v", err)
+		}
+		if _, err := purchases.PayFromWallet(purchase.ID); err == nil || err.Error() != "saldo insuficiente na carteira" {
+			t.Fatalf("PayFromWallet() erro = %v, esperado saldo insuficiente na carteira", err)
+		}
+		assertPurchaseStatus(t, purchases, purchase.ID, models.PaymentPending)
+		assertWalletBalance(t, ledger, buyerID, money.FromCents(5000))
+	})
+
+	t.Run("pagamento debita a carteira", func(t *testing.T) {
+		purchase, err := purchases.Create(rewardID, buyerID, 3, nil, nil, 0, "", expiresAt)
+		if err != nil {
+			t.Fatalf("Create() erro inesperado: %v", err)
+		}
+		result, err := purchases.PayFromWallet(purchase.ID)
+		if err != nil {
+			t.Fatalf("PayFromWallet() erro inesperado: %v", err)
+		}
+		if len(result.Numbers) != 3 {
+			t.Errorf("PayFromWallet() números = %v, esperado 3", result.Numbers)
+		}
+		assertPurchaseStatus(t, purchases, purchase.ID, models.PaymentPaid)
+		assertWalletBalance(t, ledger, buyerID, money.FromCents(2000))
+
+		paid, err := purchases.GetByID(purchase.ID)
+		if err != nil || paid.ChargeID == nil {
+			t.Fatalf("GetByID() = %+v, %v; esperado a transação do razão como cobrança", paid, err)
+		}
+		transactionID, err := uuid.Parse(*paid.ChargeID)
+		if err != nil {
+			t.Fatalf("cobrança %q não é uma transação do razão: %v", *paid.ChargeID, err)
+		}
+		assertTransactionBalanced(t, db, transactionID)
+	})
+
+	entries, total, err := ledger.ListEntries(buyerID, 1, 10)
+	if err != nil {
+		t.Fatalf("ListEntries() erro inesperado: %v", err)
+	}
+	if total != 2 || len(entries) != 2 {
+		t.Fatalf("ListEntries() = %d lançamentos, esperado 2", total)
+	}
+	// Do mais recente para o mais antigo, cada lançamento guarda o saldo resultante
+	if !entries[0].Amount.Equal(money.FromCents(-3000)) || !entries[0].BalanceAfter.Equal(money.FromCents(2000)) {
+		t.Errorf("lançamento da compra = %s (saldo %s), esperado -30.00 (saldo 20.00)", entries[0].Amount, entries[0].BalanceAfter)
+	}
+	if !entries[1].Amount.Equal(money.FromCents(5000)) || !entries[1].BalanceAfter.Equal(money.FromCents(5000)) {
+		t.Errorf("lançamento do crédito = %s (saldo %s), esperado 50.00 (saldo 50.00)", entries[1].Amount, entries[1].BalanceAfter)
+	}
+}
+
+func assertWalletBalance(t *testing.T, ledger *LedgerRepository, userID uuid.UUID, want money.Money) {
+	t.Helper()
+
+	wallet, err := ledger.GetWallet(userID)
+	if err != nil {
+		t.Fatalf("GetWallet() erro inesperado: %v", err)
+	}
+	if !wallet.Balance.Equal(want) {
+		t.Errorf("saldo da carteira = %s, esperado %s", wallet.Balance, want)
+	}
+}
+
+// assertTransactionBalanced confere que os lançamentos de uma transação do razão somam zero
+func assertTransactionBalanced(t *testing.T, db queryer, transactionID uuid.UUID) {
+	t.Helper()
+
+	var entries int
+	var total money.Money
+	err := db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM ledger_entries WHERE transaction_id = $1`, transactionID).
+		Scan(&entries, &total)
+	if err != nil {
+		t.Fatalf("erro ao somar lançamentos: %v", err)
+	}
+	if entries < 2 || !total.IsZero() {
+		t.Errorf("transação %s com %d lançamentos somando %s, esperado ao menos 2 somando zero", transactionID, entries, total)
+	}
+}
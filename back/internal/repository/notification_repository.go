@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/google/uuid"
+)
+
+// notificationColumns lista as colunas lidas por scanNotification, na mesma ordem
+const notificationColumns = "id, user_id, type, title, message, reward_id, purchase_id, read_at, created_at"
+
+// NotificationRepository implementa as operações de banco de dados dos avisos aos usuários
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository cria uma nova instância do repositório de avisos
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create registra um aviso, preenchendo o ID e a data de criação
+func (r *NotificationRepository) Create(notification *models.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, type, title, message, reward_id, purchase_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(query, notification.UserID, notification.Type, notification.Title, notification.Message,
+		notification.RewardID, notification.PurchaseID).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar aviso: %w", err)
+	}
+	return nil
+}
+
+// ListByUser lista os avisos de um usuário, do mais recente para o mais antigo, com o total e quantos não foram lidos
+func (r *NotificationRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Notification, int, int, error) {
+	offset := (page - 1) * limit
+
+	var total, unread int
+	countQuery := `SELECT COUNT(*), COUNT(*) FILTER (WHERE read_at IS NULL) FROM notifications WHERE user_id = $1`
+	if err := r.db.QueryRow(countQuery, userID).Scan(&total, &unread); err != nil {
+		return nil, 0, 0, fmt.Errorf("erro ao contar avisos: %w", err)
+	}
+
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("erro ao listar avisos: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("erro ao escanear aviso: %w", err)
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	return notifications, total, unread, rows.Err()
+}
+
+// MarkRead marca um aviso do usuário como lido e o retorna. Avisos já lidos mantêm a data da primeira leitura
+func (r *NotificationRepository) MarkRead(id, userID uuid.UUID) (*models.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + notificationColumns
+
+	notification, err := scanNotification(r.db.QueryRow(query, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("aviso não encontrado")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao marcar aviso como lido: %w", err)
+	}
+	return notification, nil
+}
+
+// scanNotification lê um aviso a partir das colunas de notificationColumns
+func scanNotification(row rowScanner) (*models.Notification, error) {
+	var notification models.Notification
+	err := row.Scan(&notification.ID, &notification.UserID, &notification.Type, &notification.Title, &notification.Message,
+		&notification.RewardID, &notification.PurchaseID, &notification.ReadAt, &notification.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/draw"
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// prizeClaimColumns lista as colunas lidas por scanPrizeClaim, na mesma ordem
+const prizeClaimColumns = `id, reward_id, number, user_id, position, prize_name, status, claim_deadline,
+	COALESCE(recipient_name, ''), COALESCE(recipient_phone, ''), COALESCE(delivery_address, ''), COALESCE(delivery_notes, ''),
+	confirmed_at, delivery_method, COALESCE(tracking_code, ''), COALESCE(shipping_notes, ''), proof_images,
+	shipped_at, received_at, forfeited_at, replaced_by, created_at, updated_at`
+
+// PrizeClaimRepository implementa as operações de banco de dados dos resgates dos prêmios sorteados
+type PrizeClaimRepository struct {
+	db *sql.DB
+}
+
+// NewPrizeClaimRepository cria uma nova instância do repositório de resgates
+func NewPrizeClaimRepository(db *sql.DB) *PrizeClaimRepository {
+	return &PrizeClaimRepository{db: db}
+}
+
+// GetByID busca um resgate pelo ID
+func (r *PrizeClaimRepository) GetByID(id uuid.UUID) (*models.PrizeClaim, error) {
+	query := `SELECT ` + prizeClaimColumns + ` FROM prize_claims WHERE id = $1`
+
+	claim, err := scanPrizeClaim(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("resgate não encontrado")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar resgate: %w", err)
+	}
+	return claim, nil
+}
+
+// ListByReward lista os resgates de um prêmio, incluindo os perdidos por prazo vencido, na ordem do sorteio
+func (r *PrizeClaimRepository) ListByReward(rewardID uuid.UUID) ([]models.PrizeClaim, error) {
+	query := `
+		SELECT ` + prizeClaimColumns + `
+		FROM prize_claims
+		WHERE reward_id = $1
+		ORDER BY position, created_at
+	`
+	return r.list(query, rewardID)
+}
+
+// ListByUser lista os resgates de um ganhador, do mais recente para o mais antigo
+func (r *PrizeClaimRepository) ListByUser(userID uuid.UUID) ([]models.PrizeClaim, error) {
+	query := `
+		SELECT ` + prizeClaimColumns + `
+		FROM prize_claims
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	return r.list(query, userID)
+}
+
+func (r *PrizeClaimRepository) list(query string, args ...interface{}) ([]models.PrizeClaim, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar resgates: %w", err)
+	}
+	defer rows.Close()
+
+	claims := []models.PrizeClaim{}
+	for rows.Next() {
+		claim, err := scanPrizeClaim(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear resgate: %w", err)
+		}
+		claims = append(claims, *claim)
+	}
+
+	return claims, rows.Err()
+}
+
+// Confirm registra os dados de entrega de um resgate pendente dentro do prazo
+func (r *PrizeClaimRepository) Confirm(id uuid.UUID, req *models.ConfirmClaimRequest) (*models.PrizeClaim, error) {
+	query := `
+		UPDATE prize_claims
+		SET status = 'confirmed', recipient_name = $2, recipient_phone = $3, delivery_address = $4, delivery_notes = $5,
+			confirmed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND claim_deadline > NOW()
+		RETURNING ` + prizeClaimColumns
+
+	claim, err := scanPrizeClaim(r.db.QueryRow(query, id, req.RecipientName, req.RecipientPhone, req.DeliveryAddress, nullIfEmpty(req.Notes)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("situação do resgate não permite esta operação")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao confirmar resgate: %w", err)
+	}
+	return claim, nil
+}
+
+// Ship registra o envio ou a entrega em mãos de um resgate confirmado, com as fotos de comprovação
+func (r *PrizeClaimRepository) Ship(id uuid.UUID, req *models.ShipClaimRequest) (*models.PrizeClaim, error) {
+	query := `
+		UPDATE prize_claims
+		SET status = 'shipped', delivery_method = $2, tracking_code = $3, proof_images = $4, shipping_notes = $5,
+			shipped_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'confirmed'
+		RETURNING ` + prizeClaimColumns
+
+	claim, err := scanPrizeClaim(r.db.QueryRow(query, id, req.Method, nullIfEmpty(req.TrackingCode), pq.Array(req.ProofImages), nullIfEmpty(req.Notes)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("situação do resgate não permite esta operação")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar envio do prêmio: %w", err)
+	}
+	return claim, nil
+}
+
+// Receive registra o recebimento de um resgate enviado. Quando todos os resgates ativos do prêmio foram recebidos,
+// o prêmio passa para delivered na mesma transação; o retorno informa se isso aconteceu
+func (r *PrizeClaimRepository) Receive(id uuid.UUID) (*models.PrizeClaim, bool, error) {
+	var rewardID uuid.UUID
+	if err := r.db.QueryRow(`SELECT reward_id FROM prize_claims WHERE id = $1`, id).Scan(&rewardID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, errors.New("resgate não encontrado")
+		}
+		return nil, false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// Bloquear o prêmio antes do resgate, na mesma ordem das demais operações
+	var status string
+	if err := tx.QueryRow(`SELECT status FROM rewards WHERE id = $1 FOR UPDATE`, rewardID).Scan(&status); err != nil {
+		return nil, false, err
+	}
+
+	query := `
+		UPDATE prize_claims
+		SET status = 'received', received_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'shipped'
+		RETURNING ` + prizeClaimColumns
+	claim, err := scanPrizeClaim(tx.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, false, errors.New("situação do resgate não permite esta operação")
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var open int
+	openQuery := `SELECT COUNT(*) FROM prize_claims WHERE reward_id = $1 AND status NOT IN ('received', 'forfeited')`
+	if err := tx.QueryRow(openQuery, rewardID).Scan(&open); err != nil {
+		return nil, false, err
+	}
+
+	delivered := open == 0 && models.CanTransitionReward(status, models.RewardDelivered)
+	if delivered {
+		_, err = tx.Exec(`UPDATE rewards SET status = 'delivered', status_changed_at = NOW(), updated_at = NOW() WHERE id = $1`, rewardID)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return claim, delivered, nil
+}
+
+// Redraw sorteia um novo ganhador para um resgate pendente com prazo vencido. O novo número sai da próxima rodada
+// do mesmo sorteio commit-reveal, entre os números vendidos que ainda não venceram, e pode ser conferido na prova
+// pública. O resgate vencido passa para forfeited e aponta para o resgate do novo ganhador, aberto até claimDeadline
+func (r *PrizeClaimRepository) Redraw(id uuid.UUID, claimDeadline time.Time) (*models.PrizeClaim, *models.PrizeClaim, error) {
+	var rewardID uuid.UUID
+	if err := r.db.QueryRow(`SELECT reward_id FROM prize_claims WHERE id = $1`, id).Scan(&rewardID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, errors.New("resgate não encontrado")
+		}
+		return nil, nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var drawSeed, digest *string
+	var winnerNumber *int
+	rewardQuery := `SELECT status, draw_seed, draw_numbers_digest, winner_number FROM rewards WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(rewardQuery, rewardID).Scan(&status, &drawSeed, &digest, &winnerNumber); err != nil {
+		return nil, nil, err
+	}
+	if status != models.RewardDrawn {
+		return nil, nil, errors.New("apenas prêmios sorteados e ainda não entregues podem ser sorteados novamente")
+	}
+	if drawSeed == nil {
+		return nil, nil, errors.New("sorteio sem semente registrada")
+	}
+
+	claim, err := scanPrizeClaim(tx.QueryRow(`SELECT `+prizeClaimColumns+` FROM prize_claims WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		return nil, nil, err
+	}
+	if claim.Status != models.ClaimPending {
+		return nil, nil, errors.New("apenas resgates não confirmados pelo ganhador podem ser sorteados novamente")
+	}
+	now := time.Now()
+	if now.Before(claim.ClaimDeadline) {
+		return nil, nil, errors.New("prazo de resgate do prêmio ainda não venceu")
+	}
+
+	// Os números que já venceram em rodadas anteriores, inclusive os perdidos, ficam fora da nova rodada
+	winners := make(map[int]bool)
+	rows, err := tx.Query(`SELECT number FROM reward_winners WHERE reward_id = $1`, rewardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		winners[number] = true
+	}
+	rows.Close()
+	round := len(winners)
+
+	numbersQuery := `SELECT number, user_id FROM reward_buyers WHERE reward_id = $1 AND status = 'sold' ORDER BY number`
+	rows, err = tx.Query(numbersQuery, rewardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var numbers, remaining []int
+	numberToUser := make(map[int]uuid.UUID)
+	for rows.Next() {
+		var number int
+		var userID uuid.UUID
+		if err := rows.Scan(&number, &userID); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		numbers = append(numbers, number)
+		numberToUser[number] = userID
+		if !winners[number] {
+			remaining = append(remaining, number)
+		}
+	}
+	rows.Close()
+
+	if len(remaining) == 0 {
+		return nil, nil, errors.New("nenhum número disponível para um novo sorteio")
+	}
+	if digest == nil {
+		numbersDigest := draw.NumbersDigest(numbers)
+		digest = &numbersDigest
+	}
+
+	index, err := draw.WinnerIndex(*drawSeed, *digest, round, len(remaining))
+	if err != nil {
+		return nil, nil, err
+	}
+	number := remaining[index]
+
+	// Registrar o novo vencedor na mesma faixa do resgate perdido
+	winnerQuery := `
+		INSERT INTO reward_winners (reward_id, prize_id, position, prize_name, draw_order, draw_index, number, user_id, drawn_at)
+		SELECT reward_id, prize_id, position, prize_name, $3, $4, $5, $6, $7
+		FROM reward_winners
+		WHERE reward_id = $1 AND number = $2
+	`
+	_, err = tx.Exec(winnerQuery, rewardID, claim.Number, round+1, index, number, numberToUser[number], now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO prize_claims (reward_id, number, user_id, position, prize_name, claim_deadline)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + prizeClaimColumns
+	replacement, err := scanPrizeClaim(tx.QueryRow(insertQuery, rewardID, number, numberToUser[number], claim.Position, claim.PrizeName, claimDeadline))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	forfeitQuery := `
+		UPDATE prize_claims
+		SET status = 'forfeited', forfeited_at = $2, replaced_by = $3, updated_at = $2
+		WHERE id = $1
+		RETURNING ` + prizeClaimColumns
+	forfeited, err := scanPrizeClaim(tx.QueryRow(forfeitQuery, id, now, replacement.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// O número vencedor principal do prêmio acompanha o novo ganhador
+	if winnerNumber != nil && *winnerNumber == claim.Number {
+		_, err = tx.Exec(`UPDATE rewards SET winner_number = $2, updated_at = $3 WHERE id = $1`, rewardID, number, now)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return forfeited, replacement, nil
+}
+
+// createPrizeClaims abre um resgate pendente para cada número vencedor do prêmio que ainda não tem resgate
+func createPrizeClaims(tx *sql.Tx, rewardID uuid.UUID, claimDeadline time.Time) error {
+	query := `
+		INSERT INTO prize_claims (reward_id, number, user_id, position, prize_name, claim_deadline)
+		SELECT reward_id, number, user_id, position, prize_name, $2
+		FROM reward_winners
+		WHERE reward_id = $1
+		ON CONFLICT (reward_id, number) DO NOTHING
+	`
+	_, err := tx.Exec(query, rewardID, claimDeadline)
+	return err
+}
+
+// scanPrizeClaim lê um resgate a partir das colunas de prizeClaimColumns
+func scanPrizeClaim(row rowScanner) (*models.PrizeClaim, error) {
+	var claim models.PrizeClaim
+	err := row.Scan(&claim.ID, &claim.RewardID, &claim.Number, &claim.UserID, &claim.Position, &claim.PrizeName,
+		&claim.Status, &claim.ClaimDeadline, &claim.RecipientName, &claim.RecipientPhone, &claim.DeliveryAddress,
+		&claim.DeliveryNotes, &claim.ConfirmedAt, &claim.DeliveryMethod, &claim.TrackingCode, &claim.ShippingNotes,
+		pq.Array(&claim.ProofImages), &claim.ShippedAt, &claim.ReceivedAt, &claim.ForfeitedAt, &claim.ReplacedBy,
+		&claim.CreatedAt, &claim.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if claim.ProofImages == nil {
+		claim.ProofImages = []string{}
+	}
+	return &claim, nil
+}
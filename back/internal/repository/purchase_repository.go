@@ -0,0 +1,883 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/cauamistura/BNUPremios/internal/payments"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// purchaseColumns lista as colunas lidas por scanPurchase, na mesma ordem
+const purchaseColumns = `p.id, p.reward_id, r.name, r.image, p.user_id, p.numbers, p.quantity, p.unit_price, p.total_amount,
+	p.payment_status, p.payment_provider, p.charge_id, p.pix_copy_paste, r.status, p.created_at, p.expires_at, p.paid_at,
+	p.cancelled_at, p.cancelled_by, p.cancel_reason, p.discount_amount, p.bonus_numbers,
+	(SELECT code FROM coupons WHERE id = p.coupon_id), p.package_id, p.updated_at`
+
+// PurchaseRepository implementa as operações de banco de dados dos pedidos de compra
+type PurchaseRepository struct {
+	db *sql.DB
+}
+
+// NewPurchaseRepository cria uma nova instância do repositório de pedidos de compra
+func NewPurchaseRepository(db *sql.DB) *PurchaseRepository {
+	return &PurchaseRepository{db: db}
+}
+
+// Create cria um pedido pendente para um usuário, reservando até expiresAt os números escolhidos em numbers
+// ou, se vazio, quantity números livres. Todos os números são reservados ou nenhum.
+// A linha de reward_details fica bloqueada durante a alocação, então pedidos simultâneos
+// do mesmo prêmio são serializados, nunca recebem o mesmo número e não ultrapassam maxPerUser (zero para sem limite).
+// Com packageID o pedido é cobrado pelo preço do pacote; com couponCode o cupom do organizador é aplicado
+// e seu resgate registrado no pedido
+func (r *PurchaseRepository) Create(rewardID, userID uuid.UUID, quantity int, numbers []int, packageID *uuid.UUID, maxPerUser int, couponCode string, expiresAt time.Time) (*models.Purchase, error) {
+	// Iniciar transação
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Verificar se o prêmio está à venda (o bloqueio compartilhado impede um sorteio ou mudança de situação simultâneos)
+	var ownerID uuid.UUID
+	var rewardName, rewardImage, rewardStatus string
+	var drawDate time.Time
+	checkQuery := `SELECT owner_id, name, image, status, draw_date FROM rewards WHERE id = $1 FOR SHARE`
+	err = tx.QueryRow(checkQuery, rewardID).Scan(&ownerID, &rewardName, &rewardImage, &rewardStatus, &drawDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if rewardStatus != models.RewardPublished {
+		return nil, rewardSaleError(rewardStatus)
+	}
+
+	// Bloquear o conjunto de números do prêmio
+	var totalNumbers int
+	var allocationMode string
+	var unitPrice money.Money
+	var salesCloseMinutes int
+	poolQuery := `
+		SELECT total_numbers, allocation_mode, COALESCE(price, 0), sales_close_minutes
+		FROM reward_details WHERE reward_id = $1 FOR UPDATE
+	`
+	err = tx.QueryRow(poolQuery, rewardID).Scan(&totalNumbers, &allocationMode, &unitPrice, &salesCloseMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	// As vendas terminam no horário de encerramento mesmo que o agendador ainda não tenha encerrado o prêmio
+	if !time.Now().Before(salesCloseAt(drawDate, &salesCloseMinutes)) {
+		return nil, errors.New("prazo de vendas do prêmio encerrado")
+	}
+
+	// Números vendidos e reservados ocupam o conjunto
+	var takenNumbers int
+	takenQuery := `SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1`
+	err = tx.QueryRow(takenQuery, rewardID).Scan(&takenNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(numbers) > 0 {
+		quantity = len(numbers)
+	}
+
+	// Conferir o limite por usuário com o conjunto bloqueado, evitando que pedidos simultâneos o ultrapassem
+	if maxPerUser > 0 {
+		var owned int
+		ownedQuery := `SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1 AND user_id = $2`
+		if err := tx.QueryRow(ownedQuery, rewardID, userID).Scan(&owned); err != nil {
+			return nil, err
+		}
+		if owned+quantity > maxPerUser {
+			return nil, &models.PurchaseRulesError{Violations: []models.PurchaseRuleViolation{
+				models.MaxPerUserViolation(maxPerUser, owned),
+			}}
+		}
+	}
+
+	remaining := totalNumbers - takenNumbers
+	if remaining <= 0 {
+		return nil, errors.New("prêmio esgotado")
+	}
+	if quantity > remaining {
+		return nil, errors.New("quantidade solicitada excede os números disponíveis")
+	}
+
+	// Pedidos de pacote são cobrados pelo preço do pacote, lido com o conjunto bloqueado
+	subtotal, err := unitPrice.Mul(int64(quantity))
+	if err != nil {
+		return nil, err
+	}
+	if packageID != nil {
+		var packageQuantity int
+		packageQuery := `SELECT quantity, price FROM reward_packages WHERE id = $1 AND reward_id = $2 AND active`
+		err := tx.QueryRow(packageQuery, *packageID, rewardID).Scan(&packageQuantity, &subtotal)
+		if err == sql.ErrNoRows {
+			return nil, errors.New("pacote não encontrado")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if packageQuantity != quantity {
+			return nil, errors.New("quantidade não corresponde ao pacote")
+		}
+	}
+
+	// Aplicar o cupom; números bônus só são dados enquanto houver números livres no conjunto
+	var coupon *models.Coupon
+	var couponID *uuid.UUID
+	var couponCodeApplied *string
+	applied := &models.CouponDiscount{Discount: money.New(0, subtotal.Currency())}
+	if couponCode != "" {
+		coupon, err = lockCouponForReward(tx, ownerID, rewardID, userID, couponCode)
+		if err != nil {
+			return nil, err
+		}
+		applied, err = coupon.Apply(quantity, subtotal, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if applied.BonusNumbers > remaining-quantity {
+			applied.BonusNumbers = remaining - quantity
+		}
+		couponID, couponCodeApplied = &coupon.ID, &coupon.Code
+	}
+
+	var numbersToReserve []int
+	if len(numbers) > 0 {
+		// Conferir os números escolhidos contra o conjunto e os números já ocupados
+		if err := checkNumbersAvailable(tx, rewardID, totalNumbers, numbers); err != nil {
+			return nil, err
+		}
+		numbersToReserve = append([]int(nil), numbers...)
+		if applied.BonusNumbers > 0 {
+			bonus, err := allocateBonusNumbers(tx, rewardID, totalNumbers, takenNumbers, allocationMode, numbersToReserve, applied.BonusNumbers)
+			if err != nil {
+				return nil, err
+			}
+			numbersToReserve = append(numbersToReserve, bonus...)
+		}
+		sort.Ints(numbersToReserve)
+	} else if allocationMode == models.AllocationRandom {
+		// Sortear números livres do conjunto
+		numbersToReserve, err = allocateRandomNumbers(tx, rewardID, totalNumbers, takenNumbers, quantity+applied.BonusNumbers)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Alocar os menores números livres do conjunto
+		numbersToReserve, err = allocateSequentialNumbers(tx, rewardID, totalNumbers, quantity+applied.BonusNumbers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Números bônus não são cobrados
+	totalAmount, err := subtotal.Sub(applied.Discount)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	purchase := &models.Purchase{
+		ID:             uuid.New(),
+		RewardID:       rewardID,
+		RewardName:     rewardName,
+		RewardImage:    rewardImage,
+		UserID:         userID,
+		Numbers:        numbersToReserve,
+		Quantity:       len(numbersToReserve),
+		UnitPrice:      unitPrice,
+		TotalAmount:    totalAmount,
+		DiscountAmount: applied.Discount,
+		BonusNumbers:   applied.BonusNumbers,
+		CouponCode:     couponCodeApplied,
+		PackageID:      packageID,
+		PaymentStatus:  models.PaymentPending,
+		Status:         "active",
+		PurchaseDate:   now,
+		ExpiresAt:      expiresAt,
+		UpdatedAt:      now,
+	}
+
+	purchaseQuery := `
+		INSERT INTO purchases (id, reward_id, user_id, numbers, quantity, unit_price, total_amount, payment_status, expires_at,
+			coupon_id, discount_amount, bonus_numbers, package_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $14)
+	`
+	_, err = tx.Exec(purchaseQuery, purchase.ID, rewardID, userID, pq.Array(purchase.Numbers), purchase.Quantity,
+		purchase.UnitPrice, purchase.TotalAmount, purchase.PaymentStatus, expiresAt,
+		couponID, purchase.DiscountAmount, purchase.BonusNumbers, packageID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inserir cada número reservado
+	insertQuery := `
+		INSERT INTO reward_buyers (reward_id, user_id, number, status, purchase_id, created_at)
+		VALUES ($1, $2, $3, 'reserved', $4, $5)
+	`
+	for _, number := range numbersToReserve {
+		_, err = tx.Exec(insertQuery, rewardID, userID, number, purchase.ID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if coupon != nil {
+		err = insertCouponRedemption(tx, coupon.ID, purchase.ID, userID, applied.Discount, applied.BonusNumbers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit da transação
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return purchase, nil
+}
+
+// MarkPaid marca um pedido pendente como pago, transformando seus números em vendidos.
+// Um pedido já expirado pelo limpador de reservas não pode mais ser pago
+func (r *PurchaseRepository) MarkPaid(purchaseID uuid.UUID) (*models.BuyNumbersResult, error) {
+	// Iniciar transação
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := markPaid(tx, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit da transação
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PayFromWallet paga um pedido pendente com o saldo da carteira do comprador, debitando a carteira
+// e marcando o pedido como pago na mesma transação
+func (r *PurchaseRepository) PayFromWallet(purchaseID uuid.UUID) (*models.BuyNumbersResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := markPaid(tx, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var userID uuid.UUID
+	var totalAmount money.Money
+	err = tx.QueryRow(`SELECT user_id, total_amount FROM purchases WHERE id = $1`, purchaseID).Scan(&userID, &totalAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	walletID, balance, err := lockWallet(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cmp, err := balance.Cmp(totalAmount); err != nil {
+		return nil, err
+	} else if cmp < 0 {
+		return nil, errors.New("saldo insuficiente na carteira")
+	}
+	debit, err := totalAmount.Neg()
+	if err != nil {
+		return nil, err
+	}
+
+	salesID, err := systemAccountID(tx, models.LedgerAccountSales)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionID, err := postLedgerTransaction(tx, models.LedgerPurchase, &purchaseID, "Pagamento de compra", []ledgerLine{
+		{accountID: walletID, amount: debit},
+		{accountID: salesID, system: true, amount: totalAmount},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE purchases SET payment_provider = $2, charge_id = $3, updated_at = NOW()
+		WHERE id = $1
+	`, purchaseID, payments.WalletProvider, transactionID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// markPaid marca um pedido pendente como pago dentro da transação informada
+func markPaid(tx *sql.Tx, purchaseID uuid.UUID) (*models.BuyNumbersResult, error) {
+	var rewardID uuid.UUID
+	err := tx.QueryRow(`SELECT reward_id FROM purchases WHERE id = $1`, purchaseID).Scan(&rewardID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("compra não encontrada")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar a situação do prêmio. O prêmio é bloqueado antes do pedido, na mesma ordem do sorteio,
+	// e o bloqueio compartilhado impede um sorteio ou mudança de situação simultâneos
+	var rewardStatus string
+	checkQuery := `SELECT status FROM rewards WHERE id = $1 FOR SHARE`
+	if err := tx.QueryRow(checkQuery, rewardID).Scan(&rewardStatus); err != nil {
+		return nil, err
+	}
+
+	// Bloquear o pedido para que o limpador de reservas não o expire durante a confirmação
+	var userID uuid.UUID
+	var status string
+	var totalAmount money.Money
+	purchaseQuery := `SELECT user_id, payment_status, total_amount FROM purchases WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(purchaseQuery, purchaseID).Scan(&userID, &status, &totalAmount); err != nil {
+		return nil, err
+	}
+
+	if status == models.PaymentExpired {
+		return nil, errors.New("reserva expirada")
+	}
+	if status == models.PaymentCancelled || status == models.PaymentRefunded {
+		return nil, errors.New("compra cancelada")
+	}
+	if status != models.PaymentPending {
+		return nil, errors.New("compra não está pendente")
+	}
+
+	// Reservas feitas antes do encerramento das vendas ainda podem ser pagas
+	if rewardStatus != models.RewardPublished && rewardStatus != models.RewardSalesClosed {
+		return nil, rewardSaleError(rewardStatus)
+	}
+
+	// Marcar os números como vendidos
+	rows, err := tx.Query(`
+		UPDATE reward_buyers SET status = 'sold'
+		WHERE purchase_id = $1 AND status = 'reserved'
+		RETURNING number
+	`, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := []int{}
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Ints(numbers)
+
+	_, err = tx.Exec(`
+		UPDATE purchases SET payment_status = $2, paid_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, purchaseID, models.PaymentPaid)
+	if err != nil {
+		return nil, err
+	}
+
+	// A primeira compra paga de um usuário indicado premia quem o indicou
+	if err := grantReferralReward(tx, rewardID, purchaseID, userID, totalAmount); err != nil {
+		return nil, err
+	}
+
+	// Marcar o prêmio como esgotado quando o último número for vendido
+	soldOutQuery := `
+		UPDATE rewards r
+		SET sold_out = true, updated_at = NOW()
+		FROM reward_details rd
+		WHERE r.id = $1 AND rd.reward_id = r.id
+			AND (SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1 AND status = 'sold') >= rd.total_numbers
+	`
+	_, err = tx.Exec(soldOutQuery, rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar se algum dos números comprados é uma cota premiada ainda não resgatada
+	instantPrizes, err := claimInstantPrizes(tx, rewardID, userID, numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BuyNumbersResult{
+		PurchaseID:    purchaseID,
+		Numbers:       numbers,
+		InstantPrizes: instantPrizes,
+	}, nil
+}
+
+// SetCharge registra no pedido a cobrança criada no provedor de pagamento
+func (r *PurchaseRepository) SetCharge(purchaseID uuid.UUID, provider, chargeID, copyPaste string) error {
+	query := `
+		UPDATE purchases
+		SET payment_provider = $2, charge_id = $3, pix_copy_paste = $4, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(query, purchaseID, provider, chargeID, nullIfEmpty(copyPaste))
+	return err
+}
+
+// ExtendReservation prorroga até expiresAt a reserva de um pedido ainda pendente
+func (r *PurchaseRepository) ExtendReservation(purchaseID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		UPDATE purchases
+		SET expires_at = $2, updated_at = NOW()
+		WHERE id = $1 AND payment_status = 'pending'
+	`
+	_, err := r.db.Exec(query, purchaseID, expiresAt)
+	return err
+}
+
+// GetByChargeID busca o pedido de uma cobrança do provedor de pagamento
+func (r *PurchaseRepository) GetByChargeID(provider, chargeID string) (*models.Purchase, error) {
+	query := `
+		SELECT ` + purchaseColumns + `
+		FROM purchases p
+		INNER JOIN rewards r ON r.id = p.reward_id
+		WHERE p.payment_provider = $1 AND p.charge_id = $2
+	`
+
+	return scanPurchase(r.db.QueryRow(query, provider, chargeID))
+}
+
+// Cancel cancela um pedido e devolve seus números ao conjunto, mantendo o pedido registrado com quem cancelou e o motivo.
+// Pedidos pendentes são apenas cancelados; pedidos pagos de prêmios ainda não sorteados passam a devolvidos e,
+// se tiveram valor, ganham uma devolução em processamento, que é retornada, e desfazem o bônus de indicação que
+// liberaram. Pedidos de prêmios cancelados são devolvidos mesmo com cota premiada resgatada
+func (r *PurchaseRepository) Cancel(purchaseID uuid.UUID, cancelledBy *uuid.UUID, reason string) (*models.PurchaseRefund, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rewardID uuid.UUID
+	err = tx.QueryRow(`SELECT reward_id FROM purchases WHERE id = $1`, purchaseID).Scan(&rewardID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("compra não encontrada")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Mesma ordem de bloqueio da confirmação do pagamento e do sorteio
+	var rewardStatus string
+	if err := tx.QueryRow(`SELECT status FROM rewards WHERE id = $1 FOR SHARE`, rewardID).Scan(&rewardStatus); err != nil {
+		return nil, err
+	}
+
+	var userID uuid.UUID
+	var status string
+	var numbers pq.Int64Array
+	var totalAmount money.Money
+	var provider *string
+	purchaseQuery := `SELECT user_id, payment_status, numbers, total_amount, payment_provider FROM purchases WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(purchaseQuery, purchaseID).Scan(&userID, &status, &numbers, &totalAmount, &provider); err != nil {
+		return nil, err
+	}
+
+	newStatus := models.PaymentCancelled
+	switch status {
+	case models.PaymentPending:
+	case models.PaymentPaid:
+		if rewardStatus == models.RewardDrawn || rewardStatus == models.RewardDelivered {
+			return nil, errors.New("não é possível cancelar compras de um prêmio já sorteado")
+		}
+
+		newStatus = models.PaymentRefunded
+		if rewardStatus == models.RewardCancelled {
+			break
+		}
+
+		// Quem já resgatou uma cota premiada com os números do pedido não pode devolvê-los
+		var claimed bool
+		claimedQuery := `
+			SELECT EXISTS (
+				SELECT 1 FROM reward_instant_prizes
+				WHERE reward_id = $1 AND claimed_by = $2 AND number = ANY($3)
+			)
+		`
+		if err := tx.QueryRow(claimedQuery, rewardID, userID, numbers).Scan(&claimed); err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, errors.New("compras com cota premiada resgatada não podem ser canceladas")
+		}
+	default:
+		return nil, errors.New("compra não pode ser cancelada")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE purchases
+		SET payment_status = $2, cancelled_at = NOW(), cancelled_by = $3, cancel_reason = $4, updated_at = NOW()
+		WHERE id = $1
+	`, purchaseID, newStatus, cancelledBy, nullIfEmpty(reason))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM reward_buyers WHERE purchase_id = $1`, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var refund *models.PurchaseRefund
+	if newStatus == models.PaymentRefunded {
+		_, err = tx.Exec(`UPDATE rewards SET sold_out = false, updated_at = NOW() WHERE id = $1`, rewardID)
+		if err != nil {
+			return nil, err
+		}
+
+		// O bônus de indicação liberado por este pedido é desfeito junto com ele
+		if err := revokeReferralReward(tx, purchaseID, rewardStatus); err != nil {
+			return nil, err
+		}
+
+		if totalAmount.IsPositive() {
+			refund, err = insertRefund(tx, purchaseID, totalAmount, provider, reason, cancelledBy)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// ListActiveByRewardAndUser lista os pedidos pendentes ou pagos de um usuário em um prêmio
+func (r *PurchaseRepository) ListActiveByRewardAndUser(rewardID, userID uuid.UUID) ([]models.Purchase, error) {
+	query := `
+		SELECT ` + purchaseColumns + `
+		FROM purchases p
+		INNER JOIN rewards r ON r.id = p.reward_id
+		WHERE p.reward_id = $1 AND p.user_id = $2 AND p.payment_status IN ('pending', 'paid')
+		ORDER BY p.created_at
+	`
+
+	rows, err := r.db.Query(query, rewardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purchases := []models.Purchase{}
+	for rows.Next() {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
+			return nil, err
+		}
+		purchases = append(purchases, *purchase)
+	}
+
+	return purchases, rows.Err()
+}
+
+// ListPaidByReward lista os pedidos pagos de um prêmio, do mais antigo para o mais recente
+func (r *PurchaseRepository) ListPaidByReward(rewardID uuid.UUID) ([]models.Purchase, error) {
+	query := `
+		SELECT ` + purchaseColumns + `
+		FROM purchases p
+		INNER JOIN rewards r ON r.id = p.reward_id
+		WHERE p.reward_id = $1 AND p.payment_status = 'paid'
+		ORDER BY p.created_at
+	`
+
+	rows, err := r.db.Query(query, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purchases := []models.Purchase{}
+	for rows.Next() {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
+			return nil, err
+		}
+		purchases = append(purchases, *purchase)
+	}
+
+	return purchases, rows.Err()
+}
+
+// ListPaidOnCancelledRewards lista os pedidos ainda pagos de prêmios cancelados, do mais antigo para o mais recente.
+// São as devoluções que falharam no cancelamento do prêmio e ainda precisam ser feitas
+func (r *PurchaseRepository) ListPaidOnCancelledRewards(limit int) ([]models.Purchase, error) {
+	query := `
+		SELECT ` + purchaseColumns + `
+		FROM purchases p
+		INNER JOIN rewards r ON r.id = p.reward_id
+		WHERE r.status = 'cancelled' AND p.payment_status = 'paid'
+		ORDER BY p.created_at
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	purchases := []models.Purchase{}
+	for rows.Next() {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
+			return nil, err
+		}
+		purchases = append(purchases, *purchase)
+	}
+
+	return purchases, rows.Err()
+}
+
+// ListBuyerIDs lista os usuários com pedidos pendentes ou pagos em um prêmio
+func (r *PurchaseRepository) ListBuyerIDs(rewardID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT user_id
+		FROM purchases
+		WHERE reward_id = $1 AND payment_status IN ('pending', 'paid')
+	`
+
+	rows, err := r.db.Query(query, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := []uuid.UUID{}
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// ExpirePending expira os pedidos não pagos vencidos até now e devolve seus números ao conjunto.
+// Retorna quantos números foram liberados
+func (r *PurchaseRepository) ExpirePending(now time.Time) (int64, error) {
+	query := `
+		WITH expired AS (
+			UPDATE purchases
+			SET payment_status = 'expired', updated_at = $1
+			WHERE payment_status = 'pending' AND expires_at <= $1
+			RETURNING id
+		)
+		DELETE FROM reward_buyers
+		WHERE status = 'reserved' AND purchase_id IN (SELECT id FROM expired)
+	`
+
+	result, err := r.db.Exec(query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetByID busca um pedido de compra por ID
+func (r *PurchaseRepository) GetByID(id uuid.UUID) (*models.Purchase, error) {
+	query := `
+		SELECT ` + purchaseColumns + `
+		FROM purchases p
+		INNER JOIN rewards r ON r.id = p.reward_id
+		WHERE p.id = $1
+	`
+
+	return scanPurchase(r.db.QueryRow(query, id))
+}
+
+// ListByUser lista os pedidos de compra de um usuário, do mais recente para o mais antigo
+func (r *PurchaseRepository) ListByUser(userID uuid.UUID, page, limit int) ([]models.Purchase, int, error) {
+	offset := (page - 1) * limit
+
+	// Query para contar total
+	var total int
+	countQuery := `SELECT COUNT(*) FROM purchases WHERE user_id = $1`
+	if err := r.db.QueryRow(countQuery, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + purchaseColumns + `
+		FROM purchases p
+		INNER JOIN rewards r ON r.id = p.reward_id
+		WHERE p.user_id = $1
+		ORDER BY p.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	purchases := []models.Purchase{}
+	for rows.Next() {
+		purchase, err := scanPurchase(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		purchases = append(purchases, *purchase)
+	}
+
+	return purchases, total, rows.Err()
+}
+
+// scanPurchase lê um pedido a partir das colunas de purchaseColumns
+func scanPurchase(row rowScanner) (*models.Purchase, error) {
+	var purchase models.Purchase
+	var numbers pq.Int64Array
+	var rewardStatus string
+
+	err := row.Scan(
+		&purchase.ID, &purchase.RewardID, &purchase.RewardName, &purchase.RewardImage, &purchase.UserID,
+		&numbers, &purchase.Quantity, &purchase.UnitPrice, &purchase.TotalAmount,
+		&purchase.PaymentStatus, &purchase.PaymentProvider, &purchase.ChargeID, &purchase.PixCopyPaste, &rewardStatus, &purchase.PurchaseDate, &purchase.ExpiresAt, &purchase.PaidAt,
+		&purchase.CancelledAt, &purchase.CancelledBy, &purchase.CancelReason, &purchase.DiscountAmount, &purchase.BonusNumbers,
+		&purchase.CouponCode, &purchase.PackageID, &purchase.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	purchase.Numbers = make([]int, len(numbers))
+	for i, number := range numbers {
+		purchase.Numbers[i] = int(number)
+	}
+
+	// Situação exibida ao comprador
+	switch {
+	case purchase.PaymentStatus == models.PaymentExpired || purchase.PaymentStatus == models.PaymentCancelled ||
+		purchase.PaymentStatus == models.PaymentRefunded:
+		purchase.Status = "cancelled"
+	case rewardStatus == models.RewardDrawn || rewardStatus == models.RewardDelivered:
+		purchase.Status = "completed"
+	default:
+		purchase.Status = "active"
+	}
+
+	return &purchase, nil
+}
+
+// allocateBonusNumbers aloca os números bônus de um cupom quando o comprador escolheu os números do pedido.
+// Os escolhidos ainda não foram gravados, então são descartados dos números livres alocados
+func allocateBonusNumbers(tx *sql.Tx, rewardID uuid.UUID, totalNumbers, takenNumbers int, allocationMode string, chosen []int, bonus int) ([]int, error) {
+	var candidates []int
+	var err error
+	if allocationMode == models.AllocationRandom {
+		candidates, err = allocateRandomNumbers(tx, rewardID, totalNumbers, takenNumbers, bonus+len(chosen))
+	} else {
+		candidates, err = allocateSequentialNumbers(tx, rewardID, totalNumbers, bonus+len(chosen))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[int]bool, len(chosen))
+	for _, number := range chosen {
+		skip[number] = true
+	}
+
+	numbers := make([]int, 0, bonus)
+	for _, number := range candidates {
+		if !skip[number] && len(numbers) < bonus {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers, nil
+}
+
+// rewardSaleError explica por que um prêmio na situação informada não aceita compras
+func rewardSaleError(status string) error {
+	switch status {
+	case models.RewardDraft:
+		return errors.New("prêmio ainda não está à venda")
+	case models.RewardSalesClosed:
+		return errors.New("vendas do prêmio encerradas")
+	case models.RewardCancelled:
+		return errors.New("prêmio cancelado")
+	default:
+		return errors.New("não é possível comprar números de um prêmio já completado")
+	}
+}
+
+// cancelPendingPurchases cancela os pedidos não pagos de um prêmio e devolve seus números ao conjunto.
+// Retorna os pedidos cancelados com comprador, números e valor, para que os compradores sejam avisados
+func cancelPendingPurchases(tx *sql.Tx, rewardID uuid.UUID) ([]models.Purchase, error) {
+	rows, err := tx.Query(`
+		UPDATE purchases SET payment_status = 'cancelled', updated_at = NOW()
+		WHERE reward_id = $1 AND payment_status = 'pending'
+		RETURNING id, user_id, numbers, total_amount
+	`, rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled := []models.Purchase{}
+	for rows.Next() {
+		var numbers pq.Int64Array
+		purchase := models.Purchase{RewardID: rewardID, PaymentStatus: models.PaymentCancelled}
+		if err := rows.Scan(&purchase.ID, &purchase.UserID, &numbers, &purchase.TotalAmount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		purchase.Numbers = make([]int, len(numbers))
+		for i, number := range numbers {
+			purchase.Numbers[i] = int(number)
+		}
+		purchase.Quantity = len(purchase.Numbers)
+		cancelled = append(cancelled, purchase)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM reward_buyers WHERE reward_id = $1 AND status = 'reserved'`, rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return cancelled, nil
+}
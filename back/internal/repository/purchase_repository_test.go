@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+func TestPurchaseCreateSequentialAllocation(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewPurchaseRepository(db)
+	rewardID := createTestReward(t, db, createTestUser(t, db), models.RewardOptions{TotalNumbers: intPtr(10)})
+	buyerID := createTestUser(t, db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name            string
+		quantity        int
+		numbers         []int
+		wantNumbers     []int
+		wantUnavailable []int
+		wantError       string
+	}{
+		{name: "menores números livres", quantity: 3, wantNumbers: []int{1, 2, 3}},
+		{name: "números escolhidos", numbers: []int{7, 5}, wantNumbers: []int{5, 7}},
+		{name: "continua pelos livres", quantity: 2, wantNumbers: []int{4, 6}},
+		{name: "número já reservado", numbers: []int{5, 8}, wantUnavailable: []int{5}},
+		{name: "número fora do conjunto", numbers: []int{11}, wantUnavailable: []int{11}},
+		{name: "mais que o disponível", quantity: 4, wantError: "quantidade solicitada excede os números disponíveis"},
+		{name: "completa o conjunto", quantity: 3, wantNumbers: []int{8, 9, 10}},
+		{name: "esgotado", quantity: 1, wantError: "prêmio esgotado"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purchase, err := repo.Create(rewardID, buyerID, tt.quantity, tt.numbers, nil, 0, "", expiresAt)
+			if tt.wantUnavailable != nil {
+				var unavailable *models.UnavailableNumbersError
+				if !errors.As(err, &unavailable) || !reflect.DeepEqual(unavailable.Numbers, tt.wantUnavailable) {
+					t.Fatalf("Create() erro = %v, esperado números indisponíveis %v", err, tt.wantUnavailable)
+				}
+				return
+			}
+			if tt.wantError != "" {
+				if err == nil || err.Error() != tt.wantError {
+					t.Fatalf("Create() erro = %v, esperado %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() erro inesperado: %v", err)
+			}
+			if !reflect.DeepEqual(purchase.Numbers, tt.wantNumbers) {
+				t.Errorf("Create() números = %v, esperado %v", purchase.Numbers, tt.wantNumbers)
+			}
+			wantTotal := money.FromCents(int64(1000 * len(tt.wantNumbers)))
+			if !purchase.TotalAmount.Equal(wantTotal) || purchase.PaymentStatus != models.PaymentPending {
+				t.Errorf("Create() = %s %s, esperado %s pending", purchase.TotalAmount, purchase.PaymentStatus, wantTotal)
+			}
+		})
+	}
+}
+
+func TestPurchaseCreateMaxPerUser(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewPurchaseRepository(db)
+	rewardID := createTestReward(t, db, createTestUser(t, db), models.RewardOptions{TotalNumbers: intPtr(100)})
+	buyerID := createTestUser(t, db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	if _, err := repo.Create(rewardID, buyerID, 3, nil, nil, 4, "", expiresAt); err != nil {
+		t.Fatalf("Create() erro inesperado: %v", err)
+	}
+
+	// Números reservados contam para o limite
+	_, err := repo.Create(rewardID, buyerID, 2, nil, nil, 4, "", expiresAt)
+	var rulesErr *models.PurchaseRulesError
+	if !errors.As(err, &rulesErr) || len(rulesErr.Violations) != 1 || rulesErr.Violations[0].Rule != models.RuleMaxPerUser {
+		t.Fatalf("Create() erro = %v, esperado violação de %s", err, models.RuleMaxPerUser)
+	}
+
+	if _, err := repo.Create(rewardID, buyerID, 1, nil, nil, 4, "", expiresAt); err != nil {
+		t.Fatalf("Create() até o limite erro inesperado: %v", err)
+	}
+}
+
+func TestPurchaseCreateConcurrent(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewPurchaseRepository(db)
+	const totalNumbers = 10
+	rewardID := createTestReward(t, db, createTestUser(t, db), models.RewardOptions{
+		TotalNumbers:   intPtr(totalNumbers),
+		AllocationMode: stringPtr(models.AllocationRandom),
+	})
+
+	buyers := make([]uuid.UUID, 3*totalNumbers)
+	for i := range buyers {
+		buyers[i] = createTestUser(t, db)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	allocated := []int{}
+	for _, buyerID := range buyers {
+		wg.Add(1)
+		go func(buyerID uuid.UUID) {
+			defer wg.Done()
+			purchase, err := repo.Create(rewardID, buyerID, 1, nil, nil, 0, "", time.Now().Add(time.Hour))
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			allocated = append(allocated, purchase.Numbers...)
+			mu.Unlock()
+		}(buyerID)
+	}
+	wg.Wait()
+
+	// Pedidos simultâneos ocupam o conjunto inteiro, sem repetir números
+	sort.Ints(allocated)
+	want := make([]int, totalNumbers)
+	for i := range want {
+		want[i] = i + 1
+	}
+	if !reflect.DeepEqual(allocated, want) {
+		t.Errorf("números alocados = %v, esperado %v", allocated, want)
+	}
+}
+
+func TestPurchaseCancel(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewPurchaseRepository(db)
+	rewardRepo := NewRewardRepository(db)
+	rewardID := createTestReward(t, db, createTestUser(t, db), models.RewardOptions{TotalNumbers: intPtr(5)})
+	buyerID := createTestUser(t, db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	t.Run("pedido pendente é cancelado sem devolução", func(t *testing.T) {
+		purchase, err := repo.Create(rewardID, buyerID, 2, nil, nil, 0, "", expiresAt)
+		if err != nil {
+			t.Fatalf("Create() erro inesperado: %v", err)
+		}
+
+		refund, err := repo.Cancel(purchase.ID, &buyerID, "desisti")
+		if err != nil || refund != nil {
+			t.Fatalf("Cancel() = %v, %v, esperado sem devolução", refund, err)
+		}
+		assertPurchaseStatus(t, repo, purchase.ID, models.PaymentCancelled)
+	})
+
+	t.Run("pedido pago é devolvido e libera os números", func(t *testing.T) {
+		purchase, err := repo.Create(rewardID, buyerID, 5, nil, nil, 0, "", expiresAt)
+		if err != nil {
+			t.Fatalf("Create() erro inesperado: %v", err)
+		}
+		if _, err := repo.MarkPaid(purchase.ID); err != nil {
+			t.Fatalf("MarkPaid() erro inesperado: %v", err)
+		}
+		if reward, _ := rewardRepo.GetByID(rewardID); reward == nil || !reward.SoldOut {
+			t.Fatalf("prêmio deveria estar esgotado após vender todos os números")
+		}
+
+		refund, err := repo.Cancel(purchase.ID, &buyerID, "")
+		if err != nil {
+			t.Fatalf("Cancel() erro inesperado: %v", err)
+		}
+		if refund == nil || !refund.Amount.Equal(purchase.TotalAmount) || refund.Status != models.RefundProcessing {
+			t.Fatalf("Cancel() devolução = %+v, esperado %s em processamento", refund, purchase.TotalAmount)
+		}
+		assertPurchaseStatus(t, repo, purchase.ID, models.PaymentRefunded)
+
+		_, sold, highest, err := rewardRepo.GetNumberStats(rewardID)
+		if err != nil || sold != 0 || highest != 0 {
+			t.Errorf("GetNumberStats() = %d vendidos, maior %d, %v; esperado conjunto livre", sold, highest, err)
+		}
+		if reward, _ := rewardRepo.GetByID(rewardID); reward == nil || reward.SoldOut {
+			t.Errorf("prêmio não deveria continuar esgotado após a devolução")
+		}
+
+		if _, err := repo.Cancel(purchase.ID, &buyerID, ""); err == nil || err.Error() != "compra não pode ser cancelada" {
+			t.Errorf("Cancel() repetido erro = %v, esperado compra não pode ser cancelada", err)
+		}
+	})
+}
+
+func assertPurchaseStatus(t *testing.T, repo *PurchaseRepository, purchaseID uuid.UUID, want string) {
+	t.Helper()
+
+	purchase, err := repo.GetByID(purchaseID)
+	if err != nil {
+		t.Fatalf("GetByID() erro inesperado: %v", err)
+	}
+	if purchase.PaymentStatus != want {
+		t.Errorf("situação do pedido = %s, esperado %s", purchase.PaymentStatus, want)
+	}
+}
@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ReferralRepository implementa as operações de banco de dados das indicações
+type ReferralRepository struct {
+	db *sql.DB
+}
+
+// NewReferralRepository cria uma nova instância do repositório de indicações
+func NewReferralRepository(db *sql.DB) *ReferralRepository {
+	return &ReferralRepository{db: db}
+}
+
+// GetCode busca o código de indicação de um usuário; vazio se ainda não foi gerado
+func (r *ReferralRepository) GetCode(userID uuid.UUID) (string, error) {
+	var code sql.NullString
+	err := r.db.QueryRow(`SELECT referral_code FROM users WHERE id = $1`, userID).Scan(&code)
+	if err == sql.ErrNoRows {
+		return "", errors.New("usuário não encontrado")
+	}
+	if err != nil {
+		return "", fmt.Errorf("erro ao buscar código de indicação: %w", err)
+	}
+	return code.String, nil
+}
+
+// SetCode grava o código de indicação de um usuário que ainda não tem um e retorna o código do usuário
+func (r *ReferralRepository) SetCode(userID uuid.UUID, code string) (string, error) {
+	var current string
+	err := r.db.QueryRow(`
+		UPDATE users SET referral_code = $2
+		WHERE id = $1 AND referral_code IS NULL
+		RETURNING referral_code
+	`, userID, code).Scan(&current)
+	if err == sql.ErrNoRows {
+		// Outra requisição gerou o código antes
+		return r.GetCode(userID)
+	}
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return "", errors.New("código de indicação já está em uso")
+		}
+		return "", fmt.Errorf("erro ao gravar código de indicação: %w", err)
+	}
+	return current, nil
+}
+
+// GetReferrerByCode busca o usuário dono de um código de indicação ativo
+func (r *ReferralRepository) GetReferrerByCode(code string) (*models.User, error) {
+	var user models.User
+	err := r.db.QueryRow(`
+		SELECT id, name, email, active
+		FROM users
+		WHERE referral_code = $1
+	`, strings.ToUpper(code)).Scan(&user.ID, &user.Name, &user.Email, &user.Active)
+	if err == sql.ErrNoRows || (err == nil && !user.Active) {
+		return nil, errors.New("código de indicação inválido")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar código de indicação: %w", err)
+	}
+	return &user, nil
+}
+
+// Create registra a indicação de um usuário com as condições de bônus vigentes
+func (r *ReferralRepository) Create(referrerID, referredID uuid.UUID, terms models.ReferralTerms) error {
+	_, err := r.db.Exec(`
+		INSERT INTO referrals (referrer_id, referred_id, reward_type, bonus_numbers, credit_amount)
+		VALUES ($1, $2, $3, $4, $5)
+	`, referrerID, referredID, terms.RewardType, terms.BonusNumbers, terms.CreditAmount)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return errors.New("usuário já foi indicado")
+		}
+		return fmt.Errorf("erro ao registrar indicação: %w", err)
+	}
+	return nil
+}
+
+// ListByReferrer lista as indicações feitas por um usuário, da mais recente para a mais antiga
+func (r *ReferralRepository) ListByReferrer(referrerID uuid.UUID) ([]models.Referral, error) {
+	rows, err := r.db.Query(`
+		SELECT rf.id, rf.referred_id, u.name, rf.reward_type, rf.status, rf.reward_id, rf.awarded_numbers,
+			rf.awarded_amount, rf.rewarded_at, rf.revoked_at, rf.created_at
+		FROM referrals rf
+		INNER JOIN users u ON u.id = rf.referred_id
+		WHERE rf.referrer_id = $1
+		ORDER BY rf.created_at DESC
+	`, referrerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar indicações: %w", err)
+	}
+	defer rows.Close()
+
+	referrals := []models.Referral{}
+	for rows.Next() {
+		var referral models.Referral
+		err := rows.Scan(&referral.ID, &referral.ReferredID, &referral.ReferredName, &referral.RewardType, &referral.Status,
+			&referral.RewardID, &referral.AwardedNumbers, &referral.AwardedAmount, &referral.RewardedAt, &referral.RevokedAt, &referral.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear indicação: %w", err)
+		}
+		referrals = append(referrals, referral)
+	}
+
+	return referrals, rows.Err()
+}
+
+// grantReferralReward premia quem indicou o comprador de um pedido que acabou de ser pago, se for a primeira
+// compra paga do indicado. Pedidos sem valor não contam. Roda na transação que marca o pedido como pago e
+// bloqueia a indicação, então compras simultâneas do indicado não premiam duas vezes
+func grantReferralReward(tx *sql.Tx, rewardID, purchaseID, referredID uuid.UUID, totalAmount money.Money) error {
+	if !totalAmount.IsPositive() {
+		return nil
+	}
+
+	var referralID, referrerID uuid.UUID
+	var terms models.ReferralTerms
+	err := tx.QueryRow(`
+		SELECT id, referrer_id, reward_type, bonus_numbers, credit_amount
+		FROM referrals
+		WHERE referred_id = $1 AND status = 'pending'
+		FOR UPDATE
+	`, referredID).Scan(&referralID, &referrerID, &terms.RewardType, &terms.BonusNumbers, &terms.CreditAmount)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var bonusPurchaseID, transactionID *uuid.UUID
+	var numbers []int
+	if terms.RewardType == models.ReferralBonusNumbers && terms.BonusNumbers > 0 {
+		bonusPurchaseID, numbers, err = grantReferralNumbers(tx, rewardID, referrerID, terms.BonusNumbers)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Sem números livres no prêmio, o bônus é pago em crédito na carteira
+	awardedAmount := money.New(0, terms.CreditAmount.Currency())
+	if len(numbers) == 0 && terms.CreditAmount.IsPositive() {
+		walletID, _, err := lockWallet(tx, referrerID)
+		if err != nil {
+			return err
+		}
+		systemID, err := systemAccountID(tx, models.LedgerAccountReferrals)
+		if err != nil {
+			return err
+		}
+		debit, err := terms.CreditAmount.Neg()
+		if err != nil {
+			return err
+		}
+		id, err := postLedgerTransaction(tx, models.LedgerReferralBonus, &referralID, "Bônus de indicação", []ledgerLine{
+			{accountID: walletID, amount: terms.CreditAmount},
+			{accountID: systemID, system: true, amount: debit},
+		})
+		if err != nil {
+			return err
+		}
+		transactionID = &id
+		awardedAmount = terms.CreditAmount
+	}
+
+	_, err = tx.Exec(`
+		UPDATE referrals
+		SET status = 'rewarded', reward_id = $2, purchase_id = $3, bonus_purchase_id = $4, awarded_numbers = $5,
+			awarded_amount = $6, ledger_transaction_id = $7, rewarded_at = NOW()
+		WHERE id = $1
+	`, referralID, rewardID, purchaseID, bonusPurchaseID, len(numbers), awardedAmount, transactionID)
+	return err
+}
+
+// grantReferralNumbers dá a quem indicou números grátis no prêmio, limitados aos números livres e ao máximo por
+// usuário, em um pedido já pago e sem valor. Segue as mesmas regras de venda da reserva: retorna nil quando o prêmio
+// não está à venda, o prazo de vendas terminou ou não há números que quem indicou ainda possa receber
+func grantReferralNumbers(tx *sql.Tx, rewardID, referrerID uuid.UUID, bonus int) (*uuid.UUID, []int, error) {
+	// O prêmio já está bloqueado para leitura pela confirmação do pagamento
+	var rewardStatus string
+	var drawDate time.Time
+	err := tx.QueryRow(`SELECT status, draw_date FROM rewards WHERE id = $1`, rewardID).Scan(&rewardStatus, &drawDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rewardStatus != models.RewardPublished {
+		return nil, nil, nil
+	}
+
+	var totalNumbers, salesCloseMinutes, maxPerUser int
+	var allocationMode string
+	var unitPrice money.Money
+	poolQuery := `
+		SELECT total_numbers, allocation_mode, COALESCE(price, 0), sales_close_minutes, COALESCE(max_per_user, 0)
+		FROM reward_details WHERE reward_id = $1 FOR UPDATE
+	`
+	err = tx.QueryRow(poolQuery, rewardID).Scan(&totalNumbers, &allocationMode, &unitPrice, &salesCloseMinutes, &maxPerUser)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !time.Now().Before(salesCloseAt(drawDate, &salesCloseMinutes)) {
+		return nil, nil, nil
+	}
+
+	var takenNumbers int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1`, rewardID).Scan(&takenNumbers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remaining := totalNumbers - takenNumbers
+	if remaining <= 0 {
+		return nil, nil, nil
+	}
+	if bonus > remaining {
+		bonus = remaining
+	}
+
+	if maxPerUser > 0 {
+		var owned int
+		ownedQuery := `SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1 AND user_id = $2`
+		if err := tx.QueryRow(ownedQuery, rewardID, referrerID).Scan(&owned); err != nil {
+			return nil, nil, err
+		}
+		if owned >= maxPerUser {
+			return nil, nil, nil
+		}
+		if bonus > maxPerUser-owned {
+			bonus = maxPerUser - owned
+		}
+	}
+
+	var numbers []int
+	if allocationMode == models.AllocationRandom {
+		numbers, err = allocateRandomNumbers(tx, rewardID, totalNumbers, takenNumbers, bonus)
+	} else {
+		numbers, err = allocateSequentialNumbers(tx, rewardID, totalNumbers, bonus)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Ints(numbers)
+
+	now := time.Now()
+	purchaseID := uuid.New()
+	_, err = tx.Exec(`
+		INSERT INTO purchases (id, reward_id, user_id, numbers, quantity, unit_price, total_amount, payment_status, expires_at,
+			bonus_numbers, paid_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 0, $7, $8, $5, $8, $8, $8)
+	`, purchaseID, rewardID, referrerID, pq.Array(numbers), len(numbers), unitPrice, models.PaymentPaid, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO reward_buyers (reward_id, user_id, number, status, purchase_id, created_at)
+		VALUES ($1, $2, $3, 'sold', $4, $5)
+	`
+	for _, number := range numbers {
+		if _, err := tx.Exec(insertQuery, rewardID, referrerID, number, purchaseID, now); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Os números bônus também concorrem às cotas premiadas
+	if _, err := claimInstantPrizes(tx, rewardID, referrerID, numbers); err != nil {
+		return nil, nil, err
+	}
+
+	return &purchaseID, numbers, nil
+}
+
+// revokeReferralReward desfaz o bônus de indicação liberado pelo pedido informado, que acabou de ser cancelado ou
+// devolvido na mesma transação. O pedido de números bônus é cancelado e os números voltam ao conjunto; em prêmios
+// cancelados ele fica para o cancelamento do prêmio, que cancela todos os pedidos pagos. O crédito é estornado da
+// carteira de quem indicou até o saldo disponível. A indicação passa para revoked e não rende outro bônus
+func revokeReferralReward(tx *sql.Tx, purchaseID uuid.UUID, rewardStatus string) error {
+	var referralID, referrerID uuid.UUID
+	var bonusPurchaseID, transactionID *uuid.UUID
+	var awardedAmount money.Money
+	err := tx.QueryRow(`
+		SELECT id, referrer_id, bonus_purchase_id, ledger_transaction_id, awarded_amount
+		FROM referrals
+		WHERE purchase_id = $1 AND status = 'rewarded'
+		FOR UPDATE
+	`, purchaseID).Scan(&referralID, &referrerID, &bonusPurchaseID, &transactionID, &awardedAmount)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if bonusPurchaseID != nil && rewardStatus != models.RewardCancelled {
+		var rewardID uuid.UUID
+		err := tx.QueryRow(`
+			UPDATE purchases
+			SET payment_status = $2, cancelled_at = NOW(), cancel_reason = $3, updated_at = NOW()
+			WHERE id = $1 AND payment_status = 'paid'
+			RETURNING reward_id
+		`, *bonusPurchaseID, models.PaymentCancelled, "bônus de indicação desfeito").Scan(&rewardID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err == nil {
+			if _, err := tx.Exec(`DELETE FROM reward_buyers WHERE purchase_id = $1`, *bonusPurchaseID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`UPDATE rewards SET sold_out = false, updated_at = NOW() WHERE id = $1`, rewardID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if transactionID != nil && awardedAmount.IsPositive() {
+		walletID, balance, err := lockWallet(tx, referrerID)
+		if err != nil {
+			return err
+		}
+		reversal := awardedAmount
+		if cmp, err := balance.Cmp(awardedAmount); err != nil {
+			return err
+		} else if cmp < 0 {
+			reversal = balance
+		}
+		if reversal.IsPositive() {
+			systemID, err := systemAccountID(tx, models.LedgerAccountReferrals)
+			if err != nil {
+				return err
+			}
+			debit, err := reversal.Neg()
+			if err != nil {
+				return err
+			}
+			_, err = postLedgerTransaction(tx, models.LedgerReferralBonus, &referralID, "Estorno do bônus de indicação", []ledgerLine{
+				{accountID: walletID, amount: debit},
+				{accountID: systemID, system: true, amount: reversal},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE referrals SET status = 'revoked', revoked_at = NOW() WHERE id = $1`, referralID)
+	return err
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/google/uuid"
+)
+
+// refundColumns lista as colunas lidas por scanRefund
+const refundColumns = `id, purchase_id, amount, status, provider, reason, requested_by, failure_reason, created_at, updated_at`
+
+// RefundRepository implementa as operações de banco de dados das devoluções de pedidos
+type RefundRepository struct {
+	db *sql.DB
+}
+
+// NewRefundRepository cria uma nova instância do repositório de devoluções
+func NewRefundRepository(db *sql.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+// Create registra uma devolução em processamento para um pedido
+func (r *RefundRepository) Create(purchaseID uuid.UUID, amount money.Money, provider *string, reason string, requestedBy *uuid.UUID) (*models.PurchaseRefund, error) {
+	return insertRefund(r.db, purchaseID, amount, provider, reason, requestedBy)
+}
+
+// UpdateStatus atualiza a situação de uma devolução com o retorno do provedor
+func (r *RefundRepository) UpdateStatus(id uuid.UUID, status string, failureReason string) error {
+	query := `
+		UPDATE purchase_refunds
+		SET status = $2, failure_reason = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(query, id, status, nullIfEmpty(failureReason))
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar devolução: %w", err)
+	}
+	return nil
+}
+
+// ListByPurchase lista as devoluções de um pedido, da mais antiga para a mais recente
+func (r *RefundRepository) ListByPurchase(purchaseID uuid.UUID) ([]models.PurchaseRefund, error) {
+	query := `SELECT ` + refundColumns + ` FROM purchase_refunds WHERE purchase_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.Query(query, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := []models.PurchaseRefund{}
+	for rows.Next() {
+		refund, err := scanRefund(rows)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, *refund)
+	}
+
+	return refunds, rows.Err()
+}
+
+// insertRefund registra uma devolução em processamento, dentro ou fora de uma transação
+func insertRefund(q queryer, purchaseID uuid.UUID, amount money.Money, provider *string, reason string, requestedBy *uuid.UUID) (*models.PurchaseRefund, error) {
+	query := `
+		INSERT INTO purchase_refunds (purchase_id, amount, status, provider, reason, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + refundColumns
+
+	refund, err := scanRefund(q.QueryRow(query, purchaseID, amount, models.RefundProcessing, provider, nullIfEmpty(reason), requestedBy))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar devolução: %w", err)
+	}
+	return refund, nil
+}
+
+// scanRefund lê uma devolução a partir das colunas de refundColumns
+func scanRefund(row rowScanner) (*models.PurchaseRefund, error) {
+	var refund models.PurchaseRefund
+	err := row.Scan(
+		&refund.ID, &refund.PurchaseID, &refund.Amount, &refund.Status, &refund.Provider,
+		&refund.Reason, &refund.RequestedBy, &refund.FailureReason, &refund.CreatedAt, &refund.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
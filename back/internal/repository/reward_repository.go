@@ -4,14 +4,36 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math/rand/v2"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/cauamistura/BNUPremios/internal/draw"
 	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// rewardColumns lista as colunas de rewards lidas por scanReward, na mesma ordem
+const rewardColumns = "id, owner_id, name, description, image, draw_date, status, status_changed_at, sold_out, winner_number, drawn_at, draw_seed, draw_seed_hash, created_at, updated_at"
+
+// rowScanner abstrai *sql.Row e *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanReward lê um prêmio selecionado com rewardColumns
+func scanReward(row rowScanner, reward *models.Reward) error {
+	return row.Scan(
+		&reward.ID, &reward.OwnerID, &reward.Name, &reward.Description,
+		&reward.Image, &reward.DrawDate, &reward.Status, &reward.StatusChangedAt, &reward.SoldOut, &reward.WinnerNumber, &reward.DrawnAt,
+		&reward.DrawSeed, &reward.DrawSeedHash,
+		&reward.CreatedAt, &reward.UpdatedAt,
+	)
+}
+
 type RewardRepository struct {
 	db *sql.DB
 }
@@ -21,7 +43,7 @@ func NewRewardRepository(db *sql.DB) *RewardRepository {
 }
 
 // Create cria um novo prêmio
-func (r *RewardRepository) Create(reward *models.Reward, price float64, minQuota int, images []string) error {
+func (r *RewardRepository) Create(reward *models.Reward, price money.Money, minQuota int, images []string, prizes []models.RewardPrizeRequest, options models.RewardOptions) error {
 	// Iniciar transação
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -31,8 +53,8 @@ func (r *RewardRepository) Create(reward *models.Reward, price float64, minQuota
 
 	// Inserir prêmio básico
 	rewardQuery := `
-		INSERT INTO rewards (id, owner_id, name, description, image, draw_date, completed, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO rewards (id, owner_id, name, description, image, draw_date, status, draw_seed, draw_seed_hash, created_at, updated_at, status_changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $10)
 	`
 
 	_, err = tx.Exec(rewardQuery,
@@ -42,7 +64,9 @@ func (r *RewardRepository) Create(reward *models.Reward, price float64, minQuota
 		reward.Description,
 		reward.Image,
 		reward.DrawDate,
-		reward.Completed,
+		reward.Status,
+		reward.DrawSeed,
+		reward.DrawSeedHash,
 		reward.CreatedAt,
 		reward.UpdatedAt,
 	)
@@ -67,6 +91,11 @@ func (r *RewardRepository) Create(reward *models.Reward, price float64, minQuota
 		return err
 	}
 
+	// Gravar configurações opcionais
+	if err := updateOptions(tx, reward.ID, optionColumns(options)); err != nil {
+		return err
+	}
+
 	// Inserir imagens adicionais
 	if len(images) > 0 {
 		imagesQuery := `
@@ -82,6 +111,11 @@ func (r *RewardRepository) Create(reward *models.Reward, price float64, minQuota
 		}
 	}
 
+	// Inserir faixas de premiação
+	if err := insertPrizes(tx, reward.ID, prizes, reward.CreatedAt); err != nil {
+		return err
+	}
+
 	// Commit da transação
 	return tx.Commit()
 }
@@ -89,17 +123,13 @@ func (r *RewardRepository) Create(reward *models.Reward, price float64, minQuota
 // GetByID busca um prêmio por ID
 func (r *RewardRepository) GetByID(id uuid.UUID) (*models.Reward, error) {
 	query := `
-		SELECT id, owner_id, name, description, image, draw_date, completed, winner_number, drawn_at, created_at, updated_at
+		SELECT ` + rewardColumns + `
 		FROM rewards
 		WHERE id = $1
 	`
 
 	var reward models.Reward
-	err := r.db.QueryRow(query, id).Scan(
-		&reward.ID, &reward.OwnerID, &reward.Name, &reward.Description,
-		&reward.Image, &reward.DrawDate, &reward.Completed, &reward.WinnerNumber, &reward.DrawnAt,
-		&reward.CreatedAt, &reward.UpdatedAt,
-	)
+	err := scanReward(r.db.QueryRow(query, id), &reward)
 	if err != nil {
 		return nil, err
 	}
@@ -115,11 +145,20 @@ func (r *RewardRepository) GetDetailsByID(id uuid.UUID) (*models.RewardDetails,
 		return nil, err
 	}
 
-	// Buscar detalhes (price e min_quota)
-	var price float64
+	// Buscar detalhes (price, min_quota e configurações opcionais)
+	var price money.Money
 	var minQuota int
-	detailsQuery := `SELECT price, min_quota FROM reward_details WHERE reward_id = $1`
-	err = r.db.QueryRow(detailsQuery, id).Scan(&price, &minQuota)
+	var options models.RewardOptions
+	detailsQuery := `
+		SELECT price, min_quota, total_numbers, allocation_mode, max_per_order, max_per_user, quantity_step,
+			top_buyer_prize, daily_top_buyer_prize, sales_close_minutes, min_sold_numbers,
+			(SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1 AND status = 'sold')
+		FROM reward_details WHERE reward_id = $1
+	`
+	var soldNumbers int
+	err = r.db.QueryRow(detailsQuery, id).Scan(
+		&price, &minQuota, &options.TotalNumbers, &options.AllocationMode,
+		&options.MaxPerOrder, &options.MaxPerUser, &options.QuantityStep, &options.TopBuyerPrize, &options.DailyTopBuyerPrize, &options.SalesCloseMinutes, &options.MinSoldNumbers, &soldNumbers)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -147,54 +186,75 @@ func (r *RewardRepository) GetDetailsByID(id uuid.UUID) (*models.RewardDetails,
 		return nil, err
 	}
 
-	// Buscar ganhador se o prêmio foi sorteado
-	var winnerUser *models.UserResponse
-	if reward.WinnerNumber != nil {
-		winner, err := r.GetWinnerByNumber(id, *reward.WinnerNumber)
-		if err == nil {
-			winnerUser = &models.UserResponse{
-				ID:        winner.ID,
-				Name:      winner.Name,
-				Email:     winner.Email,
-				Role:      winner.Role,
-				Active:    winner.Active,
-				CreatedAt: winner.CreatedAt,
-				UpdatedAt: winner.UpdatedAt,
-			}
-		}
+	// Buscar faixas de premiação
+	prizes, err := r.GetPrizes(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscar pacotes de números
+	packages, err := r.GetPackages(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscar ganhadores se o prêmio foi sorteado
+	winners, err := r.GetWinners(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscar maiores compradores premiados
+	topBuyers, err := r.GetTopBuyerWinners(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscar histórico de adiamentos do sorteio
+	dateChanges, err := r.GetDrawDateChanges(id)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.RewardDetails{
-		Reward:     *reward,
-		Images:     images,
-		Price:      price,
-		MinQuota:   minQuota,
-		Buyers:     buyers,
-		WinnerUser: winnerUser,
+		Reward:        *reward,
+		Images:        images,
+		Price:         price,
+		MinQuota:      minQuota,
+		RewardOptions: options,
+		SoldNumbers:   soldNumbers,
+		SalesCloseAt:  salesCloseAt(reward.DrawDate, options.SalesCloseMinutes),
+		Prizes:        prizes,
+		Packages:      packages,
+		Buyers:        buyers,
+		Winners:       winners,
+		TopBuyers:     topBuyers,
+		DateChanges:   dateChanges,
 	}, nil
 }
 
-// List busca todos os prêmios com paginação
-func (r *RewardRepository) List(page, limit int, search string) ([]models.Reward, int, error) {
+// List busca os prêmios nas situações informadas com paginação
+func (r *RewardRepository) List(page, limit int, search string, statuses []string) ([]models.Reward, int, error) {
 	offset := (page - 1) * limit
 
 	// Query para contar total
-	countQuery := `SELECT COUNT(*) FROM rewards`
+	countQuery := `SELECT COUNT(*) FROM rewards WHERE status = ANY($1)`
 	var total int
-	err := r.db.QueryRow(countQuery).Scan(&total)
+	err := r.db.QueryRow(countQuery, pq.Array(statuses)).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Query para buscar prêmios
 	query := `
-		SELECT id, owner_id, name, description, image, draw_date, completed, winner_number, drawn_at, created_at, updated_at
+		SELECT ` + rewardColumns + `
 		FROM rewards
+		WHERE status = ANY($3)
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.Query(query, limit, offset)
+	rows, err := r.db.Query(query, limit, offset, pq.Array(statuses))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -203,11 +263,7 @@ func (r *RewardRepository) List(page, limit int, search string) ([]models.Reward
 	var rewards []models.Reward
 	for rows.Next() {
 		var reward models.Reward
-		err := rows.Scan(
-			&reward.ID, &reward.OwnerID, &reward.Name, &reward.Description,
-			&reward.Image, &reward.DrawDate, &reward.Completed, &reward.WinnerNumber, &reward.DrawnAt,
-			&reward.CreatedAt, &reward.UpdatedAt,
-		)
+		err := scanReward(rows, &reward)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -231,7 +287,7 @@ func (r *RewardRepository) ListByOwner(ownerID uuid.UUID, page, limit int) ([]mo
 
 	// Query para buscar prêmios
 	query := `
-		SELECT id, owner_id, name, description, image, draw_date, completed, winner_number, drawn_at, created_at, updated_at
+		SELECT ` + rewardColumns + `
 		FROM rewards
 		WHERE owner_id = $1
 		ORDER BY created_at DESC
@@ -247,11 +303,7 @@ func (r *RewardRepository) ListByOwner(ownerID uuid.UUID, page, limit int) ([]mo
 	var rewards []models.Reward
 	for rows.Next() {
 		var reward models.Reward
-		err := rows.Scan(
-			&reward.ID, &reward.OwnerID, &reward.Name, &reward.Description,
-			&reward.Image, &reward.DrawDate, &reward.Completed, &reward.WinnerNumber, &reward.DrawnAt,
-			&reward.CreatedAt, &reward.UpdatedAt,
-		)
+		err := scanReward(rows, &reward)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -294,45 +346,158 @@ func (r *RewardRepository) Update(id uuid.UUID, updates map[string]interface{})
 	return err
 }
 
-// UpdateDetails atualiza os detalhes de um prêmio (price, min_quota, images)
-func (r *RewardRepository) UpdateDetails(rewardID uuid.UUID, price *float64, minQuota *int, images []string) error {
-	// Iniciar transação
+// UpdateStatus muda a situação de um prêmio para to, se a transição for permitida a partir da situação atual.
+// A conferência é feita no próprio UPDATE, então mudanças simultâneas não pulam etapas
+func (r *RewardRepository) UpdateStatus(id uuid.UUID, to string) (*models.Reward, error) {
+	query := `
+		UPDATE rewards
+		SET status = $2, status_changed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = ANY($3)
+		RETURNING ` + rewardColumns
+
+	var reward models.Reward
+	err := scanReward(r.db.QueryRow(query, id, to, pq.Array(models.RewardStatusesBefore(to))), &reward)
+	if err == nil {
+		return &reward, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	var current string
+	err = r.db.QueryRow(`SELECT status FROM rewards WHERE id = $1`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, &models.RewardStatusError{From: current, To: to}
+}
+
+// MarkDelivered registra a entrega de um prêmio sorteado. Só é permitido depois que todos os resgates foram
+// recebidos pelos ganhadores ou perdidos por prazo vencido
+func (r *RewardRepository) MarkDelivered(id uuid.UUID) (*models.Reward, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 
-	// Atualizar price e min_quota se fornecidos
-	if price != nil || minQuota != nil {
-		updateFields := []string{}
-		args := []interface{}{}
-		argCount := 1
+	// Bloquear o prêmio na mesma ordem do recebimento dos resgates
+	var current string
+	err = tx.QueryRow(`SELECT status FROM rewards WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionReward(current, models.RewardDelivered) {
+		return nil, &models.RewardStatusError{From: current, To: models.RewardDelivered}
+	}
 
-		if price != nil {
-			updateFields = append(updateFields, fmt.Sprintf("price = $%d", argCount))
-			args = append(args, *price)
-			argCount++
-		}
-		if minQuota != nil {
-			updateFields = append(updateFields, fmt.Sprintf("min_quota = $%d", argCount))
-			args = append(args, *minQuota)
-			argCount++
-		}
+	var open int
+	openQuery := `SELECT COUNT(*) FROM prize_claims WHERE reward_id = $1 AND status NOT IN ('received', 'forfeited')`
+	if err := tx.QueryRow(openQuery, id).Scan(&open); err != nil {
+		return nil, err
+	}
+	if open > 0 {
+		return nil, errors.New("há resgates do prêmio ainda não recebidos pelos ganhadores")
+	}
+
+	query := `
+		UPDATE rewards
+		SET status = $2, status_changed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + rewardColumns
+
+	var reward models.Reward
+	if err := scanReward(tx.QueryRow(query, id, models.RewardDelivered), &reward); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &reward, nil
+}
+
+// CancelReward cancela um prêmio ainda não sorteado e cancela seus pedidos não pagos, que são retornados.
+// Os pedidos pagos continuam pagos até serem devolvidos um a um
+func (r *RewardRepository) CancelReward(id uuid.UUID) (*models.Reward, []models.Purchase, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// O bloqueio exclusivo espera as compras e confirmações em andamento, que bloqueiam o prêmio para leitura
+	var current string
+	err = tx.QueryRow(`SELECT status FROM rewards WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, nil, errors.New("prêmio não encontrado")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !models.CanTransitionReward(current, models.RewardCancelled) {
+		return nil, nil, &models.RewardStatusError{From: current, To: models.RewardCancelled}
+	}
+
+	query := `
+		UPDATE rewards
+		SET status = $2, status_changed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + rewardColumns
 
-		updateFields = append(updateFields, fmt.Sprintf("updated_at = $%d", argCount))
-		args = append(args, time.Now())
-		argCount++
+	var reward models.Reward
+	if err := scanReward(tx.QueryRow(query, id, models.RewardCancelled), &reward); err != nil {
+		return nil, nil, err
+	}
+
+	pending, err := cancelPendingPurchases(tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	return &reward, pending, nil
+}
 
-		args = append(args, rewardID)
+// UpdateDetails atualiza os detalhes de um prêmio (price, min_quota, images, prizes e configurações opcionais)
+func (r *RewardRepository) UpdateDetails(rewardID uuid.UUID, price *money.Money, minQuota *int, images []string, prizes []models.RewardPrizeRequest, options models.RewardOptions) error {
+	// Iniciar transação
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-		detailsQuery := fmt.Sprintf(`
-			UPDATE reward_details 
-			SET %s 
-			WHERE reward_id = $%d
-		`, strings.Join(updateFields, ", "), argCount)
+	// Atualizar price, min_quota e configurações opcionais se fornecidos
+	columns := optionColumns(options)
+	if price != nil {
+		columns["price"] = *price
+	}
+	if minQuota != nil {
+		columns["min_quota"] = *minQuota
+	}
+	if err := updateOptions(tx, rewardID, columns); err != nil {
+		return err
+	}
 
-		_, err = tx.Exec(detailsQuery, args...)
+	// Recalcular se o prêmio está esgotado quando o tamanho do conjunto mudar
+	if options.TotalNumbers != nil {
+		soldOutQuery := `
+			UPDATE rewards
+			SET sold_out = (SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1 AND status = 'sold') >= $2, updated_at = NOW()
+			WHERE id = $1
+		`
+		_, err = tx.Exec(soldOutQuery, rewardID, *options.TotalNumbers)
 		if err != nil {
 			return err
 		}
@@ -363,6 +528,18 @@ func (r *RewardRepository) UpdateDetails(rewardID uuid.UUID, price *float64, min
 		}
 	}
 
+	// Substituir faixas de premiação se fornecidas
+	if len(prizes) > 0 {
+		_, err = tx.Exec(`DELETE FROM reward_prizes WHERE reward_id = $1`, rewardID)
+		if err != nil {
+			return err
+		}
+
+		if err := insertPrizes(tx, rewardID, prizes, time.Now()); err != nil {
+			return err
+		}
+	}
+
 	// Commit da transação
 	return tx.Commit()
 }
@@ -376,6 +553,23 @@ func (r *RewardRepository) Delete(id uuid.UUID) error {
 	}
 	defer tx.Rollback()
 
+	// Prêmios com pedidos não são apagados: os pedidos e as devoluções seriam removidos em cascata sem devolver
+	// os compradores. O prêmio fica bloqueado para que nenhum pedido seja criado durante a exclusão
+	var status string
+	if err := tx.QueryRow(`SELECT status FROM rewards WHERE id = $1 FOR UPDATE`, id).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("prêmio não encontrado")
+		}
+		return err
+	}
+	var hasPurchases bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM purchases WHERE reward_id = $1)`, id).Scan(&hasPurchases); err != nil {
+		return err
+	}
+	if hasPurchases {
+		return errors.New("não é possível deletar um prêmio com pedidos; cancele o prêmio para devolver os compradores")
+	}
+
 	// Deletar registros filhos na ordem correta (evitar problemas de chave estrangeira)
 
 	// 1. Deletar compradores (reward_buyers)
@@ -385,21 +579,31 @@ func (r *RewardRepository) Delete(id uuid.UUID) error {
 		return err
 	}
 
-	// 2. Deletar imagens (reward_images)
+	// 2. Deletar vencedores e faixas de premiação (reward_winners, reward_prizes)
+	_, err = tx.Exec(`DELETE FROM reward_winners WHERE reward_id = $1`, id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM reward_prizes WHERE reward_id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	// 3. Deletar imagens (reward_images)
 	deleteImagesQuery := `DELETE FROM reward_images WHERE reward_id = $1`
 	_, err = tx.Exec(deleteImagesQuery, id)
 	if err != nil {
 		return err
 	}
 
-	// 3. Deletar detalhes (reward_details)
+	// 4. Deletar detalhes (reward_details)
 	deleteDetailsQuery := `DELETE FROM reward_details WHERE reward_id = $1`
 	_, err = tx.Exec(deleteDetailsQuery, id)
 	if err != nil {
 		return err
 	}
 
-	// 4. Deletar o prêmio principal (rewards)
+	// 5. Deletar o prêmio principal (rewards)
 	deleteRewardQuery := `DELETE FROM rewards WHERE id = $1`
 	_, err = tx.Exec(deleteRewardQuery, id)
 	if err != nil {
@@ -410,27 +614,13 @@ func (r *RewardRepository) Delete(id uuid.UUID) error {
 	return tx.Commit()
 }
 
-// AddBuyer adiciona um comprador a um prêmio
-func (r *RewardRepository) AddBuyer(rewardID, userID uuid.UUID, number int) error {
-	query := `INSERT INTO reward_buyers (reward_id, user_id, number, created_at) VALUES ($1, $2, $3, NOW())`
-	_, err := r.db.Exec(query, rewardID, userID, number)
-	return err
-}
-
-// RemoveBuyer remove um comprador de um prêmio
-func (r *RewardRepository) RemoveBuyer(rewardID, userID uuid.UUID) error {
-	query := `DELETE FROM reward_buyers WHERE reward_id = $1 AND user_id = $2`
-	_, err := r.db.Exec(query, rewardID, userID)
-	return err
-}
-
 // GetBuyers busca todos os compradores de um prêmio com quantidade de números
 func (r *RewardRepository) GetBuyers(rewardID uuid.UUID) ([]models.BuyerWithNumber, error) {
 	query := `
 		SELECT u.id, u.name, u.email, u.role, u.active, u.created_at, u.updated_at, count(rb.number) as total_numbers
 		FROM users u
 		INNER JOIN reward_buyers rb ON u.id = rb.user_id
-		WHERE rb.reward_id = $1
+		WHERE rb.reward_id = $1 AND rb.status = 'sold'
 		GROUP BY u.id, u.name, u.email, u.role, u.active, u.created_at, u.updated_at
 		ORDER BY total_numbers DESC
 	`
@@ -471,7 +661,7 @@ func (r *RewardRepository) GetUserNumbers(rewardID, userID uuid.UUID) ([]int, er
 	query := `
 		SELECT number
 		FROM reward_buyers
-		WHERE reward_id = $1 AND user_id = $2
+		WHERE reward_id = $1 AND user_id = $2 AND status = 'sold'
 		ORDER BY number
 	`
 
@@ -493,286 +683,674 @@ func (r *RewardRepository) GetUserNumbers(rewardID, userID uuid.UUID) ([]int, er
 	return numbers, nil
 }
 
-// GetAvailableNumbers retorna apenas o próximo número disponível para compra
-func (r *RewardRepository) GetMinNumber(rewardID uuid.UUID) (int, error) {
-	// Buscar o maior número já comprado
-	query := `SELECT COALESCE(MAX(number), 0) FROM reward_buyers WHERE reward_id = $1`
-	var maxNumber int
-	err := r.db.QueryRow(query, rewardID).Scan(&maxNumber)
+// GetNumberStats retorna o tamanho do conjunto de números de um prêmio, quantos já foram vendidos
+// e o maior número ocupado (vendido ou reservado)
+func (r *RewardRepository) GetNumberStats(rewardID uuid.UUID) (total, sold, highest int, err error) {
+	query := `
+		SELECT rd.total_numbers, COUNT(rb.number) FILTER (WHERE rb.status = 'sold'), COALESCE(MAX(rb.number), 0)
+		FROM reward_details rd
+		LEFT JOIN reward_buyers rb ON rb.reward_id = rd.reward_id
+		WHERE rd.reward_id = $1
+		GROUP BY rd.total_numbers
+	`
+	err = r.db.QueryRow(query, rewardID).Scan(&total, &sold, &highest)
+	return total, sold, highest, err
+}
+
+// GetPurchaseRules busca as regras de compra de um prêmio
+func (r *RewardRepository) GetPurchaseRules(rewardID uuid.UUID) (*models.PurchaseRules, error) {
+	query := `
+		SELECT COALESCE(min_quota, 1), COALESCE(max_per_order, 0), COALESCE(max_per_user, 0), quantity_step
+		FROM reward_details
+		WHERE reward_id = $1
+	`
+
+	var rules models.PurchaseRules
+	err := r.db.QueryRow(query, rewardID).Scan(&rules.MinPerOrder, &rules.MaxPerOrder, &rules.MaxPerUser, &rules.QuantityStep)
 	if err != nil {
-		return 1, err
+		return nil, err
 	}
 
-	// O próximo número disponível é o maior + 1
-	nextNumber := maxNumber + 1
-	return nextNumber, nil
+	return &rules, nil
 }
 
-// BuyNumbers compra uma quantidade específica de números para um usuário
-func (r *RewardRepository) BuyNumbers(rewardID, userID uuid.UUID, quantity int) ([]int, error) {
-	// Iniciar transação
+// PostponeDraw adia o sorteio de um prêmio à venda ou com vendas encerradas para newDate e registra o adiamento
+// no histórico. O prêmio fica bloqueado durante a operação, então adiamentos simultâneos não ultrapassam
+// maxPostponements. Retorna o adiamento e quantos adiamentos o prêmio já teve, incluindo este
+func (r *RewardRepository) PostponeDraw(rewardID uuid.UUID, newDate time.Time, reason string, changedBy *uuid.UUID, maxPostponements int) (*models.DrawDateChange, int, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer tx.Rollback()
 
-	// Verificar se o prêmio está completado
-	var completed bool
-	checkQuery := `SELECT completed FROM rewards WHERE id = $1`
-	err = tx.QueryRow(checkQuery, rewardID).Scan(&completed)
+	var status string
+	var drawDate time.Time
+	err = tx.QueryRow(`SELECT status, draw_date FROM rewards WHERE id = $1 FOR UPDATE`, rewardID).Scan(&status, &drawDate)
+	if err == sql.ErrNoRows {
+		return nil, 0, errors.New("prêmio não encontrado")
+	}
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	if completed {
-		return nil, errors.New("não é possível comprar números de um prêmio já completado")
+	switch status {
+	case models.RewardPublished, models.RewardSalesClosed:
+	case models.RewardDraft:
+		return nil, 0, errors.New("a data do sorteio de um rascunho é alterada pela edição do prêmio")
+	case models.RewardCancelled:
+		return nil, 0, errors.New("não é possível adiar o sorteio de um prêmio cancelado")
+	default:
+		return nil, 0, errors.New("não é possível adiar o sorteio de um prêmio já sorteado")
 	}
 
-	// Buscar números disponíveis
-	minNumber, err := r.GetMinNumber(rewardID)
-	if err != nil {
-		return nil, err
+	if !newDate.After(drawDate) {
+		return nil, 0, errors.New("a nova data do sorteio deve ser posterior à data atual")
 	}
 
-	// quero os numeros de minNumber até minNumber + quantity
-	numbersToBuy := make([]int, quantity)
-	for i := 0; i < quantity; i++ {
-		numbersToBuy[i] = minNumber + i
+	var postponements int
+	countQuery := `SELECT COUNT(*) FROM reward_draw_date_changes WHERE reward_id = $1`
+	if err := tx.QueryRow(countQuery, rewardID).Scan(&postponements); err != nil {
+		return nil, 0, err
 	}
-
-	// Inserir cada número comprado
-	insertQuery := `INSERT INTO reward_buyers (reward_id, user_id, number, created_at) VALUES ($1, $2, $3, NOW())`
-	for _, number := range numbersToBuy {
-		_, err = tx.Exec(insertQuery, rewardID, userID, number)
-		if err != nil {
-			return nil, err
-		}
+	if postponements >= maxPostponements {
+		return nil, 0, errors.New("limite de adiamentos do sorteio atingido")
 	}
 
-	// Commit da transação
-	if err = tx.Commit(); err != nil {
-		return nil, err
+	insertQuery := `
+		INSERT INTO reward_draw_date_changes (reward_id, previous_date, new_date, reason, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + drawDateChangeColumns
+	change, err := scanDrawDateChange(tx.QueryRow(insertQuery, rewardID, drawDate, newDate, reason, changedBy))
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return numbersToBuy, nil
-}
-
-// GetUserPurchases busca todas as compras de um usuário
-func (r *RewardRepository) GetUserPurchases(userID uuid.UUID, page, limit int) ([]models.Purchase, int, error) {
-	offset := (page - 1) * limit
-
-	// Query para contar total
-	countQuery := `
-		SELECT COUNT(DISTINCT rb.reward_id)
-		FROM reward_buyers rb
-		WHERE rb.user_id = $1
-	`
-	var total int
-	err := r.db.QueryRow(countQuery, userID).Scan(&total)
+	_, err = tx.Exec(`UPDATE rewards SET draw_date = $2, updated_at = NOW() WHERE id = $1`, rewardID, newDate)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Query para buscar compras com paginação
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	return change, postponements + 1, nil
+}
+
+// drawDateChangeColumns lista as colunas lidas por scanDrawDateChange, na mesma ordem
+const drawDateChangeColumns = "id, reward_id, previous_date, new_date, reason, changed_by, created_at"
+
+// GetDrawDateChanges busca o histórico de adiamentos do sorteio de um prêmio, do mais antigo para o mais recente
+func (r *RewardRepository) GetDrawDateChanges(rewardID uuid.UUID) ([]models.DrawDateChange, error) {
 	query := `
-		SELECT 
-			r.id as reward_id,
-			r.name as reward_name,
-			r.image as reward_image,
-			MIN(rb.created_at) as purchase_date,
-			COUNT(rb.number) as total_numbers,
-			rd.price as price_per_number,
-			r.completed as reward_completed
-		FROM reward_buyers rb
-		INNER JOIN rewards r ON rb.reward_id = r.id
-		LEFT JOIN reward_details rd ON r.id = rd.reward_id
-		WHERE rb.user_id = $1
-		GROUP BY r.id, r.name, r.image, rd.price, r.completed
-		ORDER BY purchase_date DESC
-		LIMIT $2 OFFSET $3
+		SELECT ` + drawDateChangeColumns + `
+		FROM reward_draw_date_changes
+		WHERE reward_id = $1
+		ORDER BY created_at
 	`
 
-	rows, err := r.db.Query(query, userID, limit, offset)
+	rows, err := r.db.Query(query, rewardID)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var purchases []models.Purchase
-	purchaseID := 1
-
+	changes := []models.DrawDateChange{}
 	for rows.Next() {
-		var purchase models.Purchase
-		var rewardID uuid.UUID
-		var rewardName, rewardImage string
-		var purchaseDate time.Time
-		var totalNumbers int
-		var pricePerNumber sql.NullFloat64
-		var rewardCompleted bool
-
-		err := rows.Scan(
-			&rewardID, &rewardName, &rewardImage, &purchaseDate,
-			&totalNumbers, &pricePerNumber, &rewardCompleted)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		// Buscar os números específicos desta compra
-		numbers, err := r.GetUserNumbers(rewardID, userID)
+		change, err := scanDrawDateChange(rows)
 		if err != nil {
-			return nil, 0, err
-		}
-
-		// Calcular valor total
-		totalAmount := 0.0
-		if pricePerNumber.Valid {
-			totalAmount = float64(totalNumbers) * pricePerNumber.Float64
-		}
-
-		// Determinar status
-		status := "active"
-		if rewardCompleted {
-			status = "completed"
-		}
-
-		purchase = models.Purchase{
-			ID:           purchaseID,
-			RewardID:     rewardID,
-			RewardName:   rewardName,
-			RewardImage:  rewardImage,
-			Numbers:      numbers,
-			PurchaseDate: purchaseDate,
-			TotalAmount:  totalAmount,
-			Status:       status,
+			return nil, err
 		}
-
-		purchases = append(purchases, purchase)
-		purchaseID++
+		changes = append(changes, *change)
 	}
 
-	return purchases, total, nil
+	return changes, rows.Err()
 }
 
-// DrawReward realiza o sorteio de um prêmio
-func (r *RewardRepository) DrawReward(rewardID uuid.UUID) (*models.DrawRewardResponse, error) {
-	// Iniciar transação
-	tx, err := r.db.Begin()
+// scanDrawDateChange lê um adiamento a partir das colunas de drawDateChangeColumns
+func scanDrawDateChange(row rowScanner) (*models.DrawDateChange, error) {
+	var change models.DrawDateChange
+	err := row.Scan(&change.ID, &change.RewardID, &change.PreviousDate, &change.NewDate, &change.Reason, &change.ChangedBy, &change.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
+	return &change, nil
+}
 
-	// Verificar se o prêmio já foi sorteado
-	var winnerNumber *int
-	var drawnAt *time.Time
-	checkQuery := `SELECT winner_number, drawn_at FROM rewards WHERE id = $1`
-	err = tx.QueryRow(checkQuery, rewardID).Scan(&winnerNumber, &drawnAt)
-	if err != nil {
-		return nil, err
+// GetSalesCloseAt busca o momento em que as vendas de um prêmio são encerradas
+func (r *RewardRepository) GetSalesCloseAt(rewardID uuid.UUID) (time.Time, error) {
+	var drawDate time.Time
+	var minutes int
+	query := `
+		SELECT r.draw_date, COALESCE(rd.sales_close_minutes, 0)
+		FROM rewards r
+		LEFT JOIN reward_details rd ON rd.reward_id = r.id
+		WHERE r.id = $1
+	`
+	if err := r.db.QueryRow(query, rewardID).Scan(&drawDate, &minutes); err != nil {
+		return time.Time{}, err
 	}
 
-	if winnerNumber != nil {
-		return nil, errors.New("prêmio já foi sorteado")
+	return salesCloseAt(drawDate, &minutes), nil
+}
+
+// salesCloseAt calcula o encerramento das vendas a partir da data do sorteio e dos minutos de antecedência
+func salesCloseAt(drawDate time.Time, minutes *int) time.Time {
+	if minutes == nil {
+		return drawDate
 	}
+	return drawDate.Add(-time.Duration(*minutes) * time.Minute)
+}
 
-	// Buscar todos os números comprados para este prêmio
-	numbersQuery := `
-		SELECT number, user_id 
-		FROM reward_buyers 
-		WHERE reward_id = $1 
-		ORDER BY number
+// CountUserNumbers conta quantos números um usuário já possui em um prêmio
+func (r *RewardRepository) CountUserNumbers(rewardID, userID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1 AND user_id = $2`
+	err := r.db.QueryRow(query, rewardID, userID).Scan(&count)
+	return count, err
+}
+
+// allocateRandomNumbers sorteia números livres do conjunto de um prêmio.
+// Enquanto a maior parte do conjunto está livre, sorteia candidatos e descarta os já vendidos;
+// com o conjunto majoritariamente vendido, sorteia direto entre os números livres no banco
+func allocateRandomNumbers(tx *sql.Tx, rewardID uuid.UUID, totalNumbers, soldNumbers, quantity int) ([]int, error) {
+	if soldNumbers*2 < totalNumbers {
+		numbers, err := sampleFreeNumbers(tx, rewardID, totalNumbers, quantity)
+		if err != nil {
+			return nil, err
+		}
+		if len(numbers) == quantity {
+			return numbers, nil
+		}
+	}
+
+	query := `
+		SELECT n
+		FROM generate_series(1, $2::integer) AS n
+		WHERE NOT EXISTS (SELECT 1 FROM reward_buyers rb WHERE rb.reward_id = $1 AND rb.number = n)
+		ORDER BY random()
+		LIMIT $3
 	`
-	rows, err := tx.Query(numbersQuery, rewardID)
+
+	rows, err := tx.Query(query, rewardID, totalNumbers, quantity)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var numbers []int
-	var userIDs []uuid.UUID
-	numberToUser := make(map[int]uuid.UUID)
-
+	numbers := make([]int, 0, quantity)
 	for rows.Next() {
 		var number int
-		var userID uuid.UUID
-		if err := rows.Scan(&number, &userID); err != nil {
+		if err := rows.Scan(&number); err != nil {
 			return nil, err
 		}
 		numbers = append(numbers, number)
-		userIDs = append(userIDs, userID)
-		numberToUser[number] = userID
-	}
-
-	if len(numbers) == 0 {
-		return nil, errors.New("nenhum número foi comprado para este prêmio")
 	}
-
-	// Realizar sorteio aleatório
-	rand.Seed(time.Now().UnixNano())
-	winnerIndex := rand.Intn(len(numbers))
-	winnerNumber = &numbers[winnerIndex]
-	winnerUserID := numberToUser[*winnerNumber]
-
-	// Atualizar o prêmio com o número vencedor e marcar como completado
-	now := time.Now()
-	updateQuery := `
-		UPDATE rewards 
-		SET winner_number = $1, drawn_at = $2, completed = true, updated_at = $3 
-		WHERE id = $4
-	`
-	_, err = tx.Exec(updateQuery, *winnerNumber, now, now, rewardID)
-	if err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	// Buscar informações do usuário vencedor
-	var winnerUser models.User
-	userQuery := `
-		SELECT id, name, email, role, active, created_at, updated_at 
-		FROM users 
-		WHERE id = $1
-	`
-	err = tx.QueryRow(userQuery, winnerUserID).Scan(
-		&winnerUser.ID, &winnerUser.Name, &winnerUser.Email,
-		&winnerUser.Role, &winnerUser.Active, &winnerUser.CreatedAt, &winnerUser.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
+	if len(numbers) < quantity {
+		return nil, errors.New("quantidade solicitada excede os números disponíveis")
 	}
 
-	// Commit da transação
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// sampleFreeNumbers sorteia candidatos e mantém os que ainda não foram vendidos.
+// Desiste após algumas rodadas, devolvendo o que conseguiu, para o chamador recorrer ao banco
+func sampleFreeNumbers(tx *sql.Tx, rewardID uuid.UUID, totalNumbers, quantity int) ([]int, error) {
+	const maxRounds = 5
+
+	chosen := make(map[int]bool, quantity)
+	numbers := make([]int, 0, quantity)
+
+	for round := 0; round < maxRounds && len(numbers) < quantity; round++ {
+		// Sortear o dobro do que falta, sem repetir candidatos já escolhidos
+		missing := quantity - len(numbers)
+		candidates := make([]int, 0, missing*2)
+		seen := make(map[int]bool, missing*2)
+		for attempts := 0; len(candidates) < missing*2 && attempts < missing*8; attempts++ {
+			candidate := rand.IntN(totalNumbers) + 1
+			if chosen[candidate] || seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+
+		taken := make(map[int]bool)
+		rows, err := tx.Query(`SELECT number FROM reward_buyers WHERE reward_id = $1 AND number = ANY($2)`, rewardID, pq.Array(candidates))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var number int
+			if err := rows.Scan(&number); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			taken[number] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, candidate := range candidates {
+			if len(numbers) == quantity {
+				break
+			}
+			if !taken[candidate] {
+				chosen[candidate] = true
+				numbers = append(numbers, candidate)
+			}
+		}
+	}
+
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+// checkNumbersAvailable garante que todos os números escolhidos estão dentro do conjunto e livres
+func checkNumbersAvailable(tx *sql.Tx, rewardID uuid.UUID, totalNumbers int, numbers []int) error {
+	unavailable := []int{}
+	for _, number := range numbers {
+		if number < 1 || number > totalNumbers {
+			unavailable = append(unavailable, number)
+		}
+	}
+
+	query := `SELECT number FROM reward_buyers WHERE reward_id = $1 AND number = ANY($2) ORDER BY number`
+	rows, err := tx.Query(query, rewardID, pq.Array(numbers))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			return err
+		}
+		unavailable = append(unavailable, number)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(unavailable) > 0 {
+		sort.Ints(unavailable)
+		return &models.UnavailableNumbersError{Numbers: unavailable}
+	}
+
+	return nil
+}
+
+// GetAvailableNumbers retorna os números livres de um prêmio dentro do intervalo informado
+func (r *RewardRepository) GetAvailableNumbers(rewardID uuid.UUID, from, to int) ([]int, error) {
+	query := `
+		SELECT n
+		FROM generate_series($2::integer, $3::integer) AS n
+		WHERE NOT EXISTS (SELECT 1 FROM reward_buyers rb WHERE rb.reward_id = $1 AND rb.number = n)
+		ORDER BY n
+	`
+
+	rows, err := r.db.Query(query, rewardID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	numbers := []int{}
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	return numbers, rows.Err()
+}
+
+// allocateSequentialNumbers retorna os menores números livres do conjunto de um prêmio
+func allocateSequentialNumbers(tx *sql.Tx, rewardID uuid.UUID, totalNumbers, quantity int) ([]int, error) {
+	query := `
+		SELECT n
+		FROM generate_series(1, $2::integer) AS n
+		WHERE NOT EXISTS (SELECT 1 FROM reward_buyers rb WHERE rb.reward_id = $1 AND rb.number = n)
+		ORDER BY n
+		LIMIT $3
+	`
+
+	rows, err := tx.Query(query, rewardID, totalNumbers, quantity)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	numbers := make([]int, 0, quantity)
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(numbers) < quantity {
+		return nil, errors.New("quantidade solicitada excede os números disponíveis")
+	}
+
+	return numbers, nil
+}
+
+// DrawReward realiza o sorteio de um prêmio e abre o resgate de cada número vencedor até claimDeadline
+func (r *RewardRepository) DrawReward(rewardID uuid.UUID, claimDeadline time.Time) (*models.DrawRewardResponse, error) {
+	// Iniciar transação
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Verificar se o prêmio pode ser sorteado (bloqueando a linha para evitar sorteios simultâneos)
+	var rewardName, status string
+	var drawSeed, drawSeedHash *string
+	var drawDate time.Time
+	var salesCloseMinutes int
+	checkQuery := `
+		SELECT r.name, r.status, r.draw_seed, r.draw_seed_hash, r.draw_date,
+			COALESCE((SELECT rd.sales_close_minutes FROM reward_details rd WHERE rd.reward_id = r.id), 0)
+		FROM rewards r
+		WHERE r.id = $1
+		FOR UPDATE
+	`
+	err = tx.QueryRow(checkQuery, rewardID).Scan(&rewardName, &status, &drawSeed, &drawSeedHash, &drawDate, &salesCloseMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == models.RewardDrawn || status == models.RewardDelivered {
+		return nil, errors.New("prêmio já foi sorteado")
+	}
+	if !models.CanTransitionReward(status, models.RewardDrawn) {
+		return nil, &models.RewardStatusError{From: status, To: models.RewardDrawn}
+	}
+	// A lista de compradores só fica congelada depois do horário de encerramento das vendas
+	if time.Now().Before(salesCloseAt(drawDate, &salesCloseMinutes)) {
+		return nil, errors.New("prazo de vendas do prêmio ainda não encerrado")
+	}
+
+	// Prêmios com mínimo de vendas só são sorteados se o mínimo foi atingido
+	var minSold *int
+	var sold int
+	minQuery := `
+		SELECT min_sold_numbers, (SELECT COUNT(*) FROM reward_buyers WHERE reward_id = $1 AND status = 'sold')
+		FROM reward_details WHERE reward_id = $1
+	`
+	err = tx.QueryRow(minQuery, rewardID).Scan(&minSold, &sold)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if minSold != nil && sold < *minSold {
+		return nil, &models.MinimumSalesError{Sold: sold, Minimum: *minSold}
+	}
+
+	// Pedidos ainda não pagos não participam do sorteio
+	if _, err := cancelPendingPurchases(tx, rewardID); err != nil {
+		return nil, err
+	}
+
+	// Buscar todos os números comprados para este prêmio
+	numbersQuery := `
+		SELECT number, user_id 
+		FROM reward_buyers 
+		WHERE reward_id = $1 AND status = 'sold'
+		ORDER BY number
+	`
+	rows, err := tx.Query(numbersQuery, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var numbers []int
+	numberToUser := make(map[int]uuid.UUID)
+
+	for rows.Next() {
+		var number int
+		var userID uuid.UUID
+		if err := rows.Scan(&number, &userID); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+		numberToUser[number] = userID
+	}
+	rows.Close()
+
+	if len(numbers) == 0 {
+		return nil, errors.New("nenhum número foi comprado para este prêmio")
+	}
+
+	// Prêmios antigos não possuem compromisso prévio: a semente é gerada no momento do sorteio
+	if drawSeed == nil {
+		seed, err := draw.GenerateSeed()
+		if err != nil {
+			return nil, err
+		}
+		seedHash := draw.HashSeed(seed)
+		drawSeed = &seed
+		drawSeedHash = &seedHash
+	}
+
+	// Derivar o vencedor a partir da semente revelada e do resumo dos números vendidos
+	// Expandir as faixas de premiação: cada unidade de cada faixa corresponde a uma rodada do sorteio
+	prizes, err := r.getPrizes(tx, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(prizes) == 0 {
+		prizes = []models.RewardPrize{{RewardID: rewardID, Position: 1, Name: rewardName, Quantity: 1}}
+	}
+
+	var slots []models.RewardPrize
+	for _, prize := range prizes {
+		for i := 0; i < prize.Quantity; i++ {
+			slots = append(slots, prize)
+		}
+	}
+
+	// Sortear números distintos para cada faixa, em ordem de posição
+	picks, digest, err := draw.PickWinners(*drawSeed, numbers, len(slots))
+	if err != nil {
+		return nil, err
+	}
+
+	// Atualizar o prêmio com o número vencedor principal, revelar a semente e marcar como sorteado
+	now := time.Now()
+	mainNumber := picks[0].Number
+	updateQuery := `
+		UPDATE rewards 
+		SET winner_number = $1, drawn_at = $2, status = 'drawn', status_changed_at = $2, draw_seed = $3, draw_seed_hash = $4,
+			draw_numbers_digest = $5, updated_at = $6 
+		WHERE id = $7
+	`
+	_, err = tx.Exec(updateQuery, mainNumber, now, *drawSeed, *drawSeedHash, digest, now, rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Registrar os vencedores de cada faixa
+	winnerQuery := `
+		INSERT INTO reward_winners (reward_id, prize_id, position, prize_name, draw_order, draw_index, number, user_id, drawn_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	for i, pick := range picks {
+		prize := slots[i]
+		var prizeID *uuid.UUID
+		if prize.ID != uuid.Nil {
+			prizeID = &prize.ID
+		}
+
+		_, err = tx.Exec(winnerQuery,
+			rewardID, prizeID, prize.Position, prize.Name, i+1, pick.Index, pick.Number, numberToUser[pick.Number], now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Abrir o resgate de cada número vencedor
+	if err := createPrizeClaims(tx, rewardID, claimDeadline); err != nil {
+		return nil, err
+	}
+
+	// Apurar os maiores compradores premiados, se configurados
+	if err := resolveTopBuyers(tx, rewardID, now); err != nil {
+		return nil, err
+	}
+
+	winners, err := r.getWinners(tx, rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	topBuyers, err := r.getTopBuyerWinners(tx, rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit da transação
 	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	// Converter para response
-	winnerUserResponse := models.UserResponse{
-		ID:        winnerUser.ID,
-		Name:      winnerUser.Name,
-		Email:     winnerUser.Email,
-		Role:      winnerUser.Role,
-		Active:    winnerUser.Active,
-		CreatedAt: winnerUser.CreatedAt,
-		UpdatedAt: winnerUser.UpdatedAt,
+	message := fmt.Sprintf("Sorteio realizado! Número vencedor: %d. Prêmio marcado como completado.", mainNumber)
+	if len(picks) < len(slots) {
+		message = fmt.Sprintf("Sorteio realizado! Número vencedor: %d. Apenas %d de %d prêmios foram sorteados por falta de números vendidos.",
+			mainNumber, len(picks), len(slots))
 	}
 
 	return &models.DrawRewardResponse{
 		RewardID:     rewardID,
-		WinnerNumber: *winnerNumber,
-		WinnerUser:   &winnerUserResponse,
+		WinnerNumber: mainNumber,
+		WinnerUser:   &winners[0].User,
+		Winners:      winners,
+		TopBuyers:    topBuyers,
 		DrawnAt:      now,
-		Message:      fmt.Sprintf("Sorteio realizado! Número vencedor: %d. Prêmio marcado como completado.", *winnerNumber),
+		Message:      message,
 	}, nil
 }
 
+// queryer abstrai *sql.DB e *sql.Tx para consultas compartilhadas dentro e fora de transações
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// GetPrizes busca as faixas de premiação de um prêmio ordenadas por posição
+func (r *RewardRepository) GetPrizes(rewardID uuid.UUID) ([]models.RewardPrize, error) {
+	return r.getPrizes(r.db, rewardID)
+}
+
+func (r *RewardRepository) getPrizes(q queryer, rewardID uuid.UUID) ([]models.RewardPrize, error) {
+	query := `
+		SELECT id, reward_id, position, name, COALESCE(image, ''), quantity
+		FROM reward_prizes
+		WHERE reward_id = $1
+		ORDER BY position
+	`
+
+	rows, err := q.Query(query, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prizes := []models.RewardPrize{}
+	for rows.Next() {
+		var prize models.RewardPrize
+		if err := rows.Scan(&prize.ID, &prize.RewardID, &prize.Position, &prize.Name, &prize.Image, &prize.Quantity); err != nil {
+			return nil, err
+		}
+		prizes = append(prizes, prize)
+	}
+
+	return prizes, nil
+}
+
+// GetWinners busca os vencedores de um prêmio na ordem em que foram sorteados
+func (r *RewardRepository) GetWinners(rewardID uuid.UUID) ([]models.RewardWinner, error) {
+	return r.getWinners(r.db, rewardID)
+}
+
+func (r *RewardRepository) getWinners(q queryer, rewardID uuid.UUID) ([]models.RewardWinner, error) {
+	query := `
+		SELECT rw.prize_id, rw.position, rw.prize_name, rw.draw_order, rw.draw_index, rw.number, rw.drawn_at,
+			u.id, u.name, u.email, u.role, u.active, u.created_at, u.updated_at, pc.status
+		FROM reward_winners rw
+		INNER JOIN users u ON u.id = rw.user_id
+		LEFT JOIN prize_claims pc ON pc.reward_id = rw.reward_id AND pc.number = rw.number
+		WHERE rw.reward_id = $1
+		ORDER BY rw.draw_order
+	`
+
+	rows, err := q.Query(query, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	winners := []models.RewardWinner{}
+	for rows.Next() {
+		var winner models.RewardWinner
+		err := rows.Scan(
+			&winner.PrizeID, &winner.Position, &winner.PrizeName, &winner.DrawOrder, &winner.DrawIndex,
+			&winner.Number, &winner.DrawnAt,
+			&winner.User.ID, &winner.User.Name, &winner.User.Email, &winner.User.Role,
+			&winner.User.Active, &winner.User.CreatedAt, &winner.User.UpdatedAt, &winner.ClaimStatus)
+		if err != nil {
+			return nil, err
+		}
+		winners = append(winners, winner)
+	}
+
+	return winners, nil
+}
+
+// insertPrizes insere as faixas de premiação de um prêmio
+func insertPrizes(tx *sql.Tx, rewardID uuid.UUID, prizes []models.RewardPrizeRequest, now time.Time) error {
+	query := `
+		INSERT INTO reward_prizes (reward_id, position, name, image, quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`
+
+	for _, prize := range prizes {
+		quantity := prize.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+		if _, err := tx.Exec(query, rewardID, prize.Position, prize.Name, prize.Image, quantity, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetWinnerByNumber busca o usuário que comprou um número específico
 func (r *RewardRepository) GetWinnerByNumber(rewardID uuid.UUID, number int) (*models.User, error) {
 	query := `
 		SELECT u.id, u.name, u.email, u.role, u.active, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN reward_buyers rb ON u.id = rb.user_id
-		WHERE rb.reward_id = $1 AND rb.number = $2
+		WHERE rb.reward_id = $1 AND rb.number = $2 AND rb.status = 'sold'
 	`
 
 	var user models.User
@@ -789,11 +1367,506 @@ func (r *RewardRepository) GetWinnerByNumber(rewardID uuid.UUID, number int) (*m
 
 // IsRewardDrawn verifica se um prêmio já foi sorteado
 func (r *RewardRepository) IsRewardDrawn(rewardID uuid.UUID) (bool, error) {
-	var winnerNumber *int
-	query := `SELECT winner_number FROM rewards WHERE id = $1`
-	err := r.db.QueryRow(query, rewardID).Scan(&winnerNumber)
+	var status string
+	query := `SELECT status FROM rewards WHERE id = $1`
+	err := r.db.QueryRow(query, rewardID).Scan(&status)
 	if err != nil {
 		return false, err
 	}
-	return winnerNumber != nil, nil
+	return status == models.RewardDrawn || status == models.RewardDelivered, nil
+}
+
+// GetDrawProof busca os dados necessários para verificar publicamente o sorteio de um prêmio
+func (r *RewardRepository) GetDrawProof(rewardID uuid.UUID) (*models.DrawProofResponse, error) {
+	reward, err := r.GetByID(rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var digest *string
+	digestQuery := `SELECT draw_numbers_digest FROM rewards WHERE id = $1`
+	if err := r.db.QueryRow(digestQuery, rewardID).Scan(&digest); err != nil {
+		return nil, err
+	}
+
+	numbersQuery := `SELECT number FROM reward_buyers WHERE reward_id = $1 AND status = 'sold' ORDER BY number`
+	rows, err := r.db.Query(numbersQuery, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	numbers := []int{}
+	for rows.Next() {
+		var number int
+		if err := rows.Scan(&number); err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+
+	proof := &models.DrawProofResponse{
+		RewardID:     rewardID,
+		Algorithm:    draw.Algorithm,
+		SeedHash:     reward.DrawSeedHash,
+		Numbers:      numbers,
+		WinnerNumber: reward.WinnerNumber,
+		DrawnAt:      reward.DrawnAt,
+		Rounds:       []models.DrawProofRound{},
+		Drawn:        reward.WinnerNumber != nil,
+	}
+
+	// A semente só é revelada depois do sorteio
+	if proof.Drawn {
+		proof.Seed = reward.DrawSeed
+		proof.NumbersDigest = digest
+
+		winners, err := r.GetWinners(rewardID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, winner := range winners {
+			proof.Rounds = append(proof.Rounds, models.DrawProofRound{
+				Round:     winner.DrawOrder - 1,
+				Position:  winner.Position,
+				PrizeName: winner.PrizeName,
+				Index:     winner.DrawIndex,
+				Number:    winner.Number,
+			})
+		}
+		if len(proof.Rounds) > 0 {
+			proof.WinnerIndex = proof.Rounds[0].Index
+		}
+	}
+
+	return proof, nil
+}
+
+// claimInstantPrizes atribui ao comprador as cotas premiadas contidas nos números comprados
+func claimInstantPrizes(tx *sql.Tx, rewardID, userID uuid.UUID, numbers []int) ([]models.InstantPrize, error) {
+	query := `
+		UPDATE reward_instant_prizes
+		SET claimed_by = $1, claimed_at = NOW()
+		WHERE reward_id = $2 AND number = ANY($3) AND claimed_by IS NULL
+		RETURNING id, reward_id, number, name, COALESCE(description, ''), COALESCE(value, 0), claimed_by, claimed_at, created_at
+	`
+
+	rows, err := tx.Query(query, userID, rewardID, pq.Array(numbers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prizes := []models.InstantPrize{}
+	for rows.Next() {
+		var prize models.InstantPrize
+		err := rows.Scan(
+			&prize.ID, &prize.RewardID, &prize.Number, &prize.Name, &prize.Description,
+			&prize.Value, &prize.ClaimedBy, &prize.ClaimedAt, &prize.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		prizes = append(prizes, prize)
+	}
+
+	return prizes, nil
+}
+
+// AddInstantPrizes cadastra cotas premiadas em um prêmio. Números vendidos ou reservados em pedidos pendentes
+// não podem virar cotas premiadas
+func (r *RewardRepository) AddInstantPrizes(rewardID uuid.UUID, prizes []models.InstantPrizeRequest) ([]models.InstantPrize, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	numbers := make([]int, len(prizes))
+	for i, prize := range prizes {
+		numbers[i] = prize.Number
+		if prize.Number < 1 {
+			return nil, fmt.Errorf("o número %d está fora do intervalo do prêmio", prize.Number)
+		}
+	}
+
+	// Bloquear o conjunto de números, como na reserva, para que nenhum pedido ocupe os números durante o cadastro
+	var totalNumbers int
+	err = tx.QueryRow(`SELECT total_numbers FROM reward_details WHERE reward_id = $1 FOR UPDATE`, rewardID).Scan(&totalNumbers)
+	if err != nil {
+		return nil, err
+	}
+	for _, number := range numbers {
+		if number > totalNumbers {
+			return nil, fmt.Errorf("o número %d está fora do intervalo do prêmio", number)
+		}
+	}
+
+	var takenNumber int
+	takenQuery := `
+		SELECT n
+		FROM (
+			SELECT number AS n FROM reward_buyers WHERE reward_id = $1
+			UNION
+			SELECT unnest(numbers) FROM purchases WHERE reward_id = $1 AND payment_status IN ('pending', 'paid')
+		) taken
+		WHERE n = ANY($2)
+		ORDER BY n
+		LIMIT 1
+	`
+	err = tx.QueryRow(takenQuery, rewardID, pq.Array(numbers)).Scan(&takenNumber)
+	if err == nil {
+		return nil, fmt.Errorf("o número %d já foi vendido ou reservado e não pode ser uma cota premiada", takenNumber)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO reward_instant_prizes (reward_id, number, name, description, value, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING id, reward_id, number, name, COALESCE(description, ''), COALESCE(value, 0), claimed_by, claimed_at, created_at
+	`
+
+	created := []models.InstantPrize{}
+	for _, prize := range prizes {
+		var instantPrize models.InstantPrize
+		err := tx.QueryRow(insertQuery, rewardID, prize.Number, prize.Name, prize.Description, prize.Value).Scan(
+			&instantPrize.ID, &instantPrize.RewardID, &instantPrize.Number, &instantPrize.Name, &instantPrize.Description,
+			&instantPrize.Value, &instantPrize.ClaimedBy, &instantPrize.ClaimedAt, &instantPrize.CreatedAt)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				return nil, fmt.Errorf("o número %d já é uma cota premiada", prize.Number)
+			}
+			return nil, err
+		}
+		created = append(created, instantPrize)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ListInstantPrizes busca as cotas premiadas de um prêmio com o comprador de cada cota resgatada
+func (r *RewardRepository) ListInstantPrizes(rewardID uuid.UUID) ([]models.InstantPrize, error) {
+	query := `
+		SELECT ip.id, ip.reward_id, ip.number, ip.name, COALESCE(ip.description, ''), COALESCE(ip.value, 0),
+			ip.claimed_by, ip.claimed_at, ip.created_at,
+			u.id, u.name, u.email, u.role, u.active, u.created_at, u.updated_at
+		FROM reward_instant_prizes ip
+		LEFT JOIN users u ON u.id = ip.claimed_by
+		WHERE ip.reward_id = $1
+		ORDER BY ip.value DESC, ip.created_at
+	`
+
+	rows, err := r.db.Query(query, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prizes := []models.InstantPrize{}
+	for rows.Next() {
+		var prize models.InstantPrize
+		var userID *uuid.UUID
+		var userName, userEmail, userRole *string
+		var userActive *bool
+		var userCreatedAt, userUpdatedAt *time.Time
+
+		err := rows.Scan(
+			&prize.ID, &prize.RewardID, &prize.Number, &prize.Name, &prize.Description, &prize.Value,
+			&prize.ClaimedBy, &prize.ClaimedAt, &prize.CreatedAt,
+			&userID, &userName, &userEmail, &userRole, &userActive, &userCreatedAt, &userUpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if userID != nil {
+			prize.Winner = &models.UserResponse{
+				ID:        *userID,
+				Name:      *userName,
+				Email:     *userEmail,
+				Role:      *userRole,
+				Active:    *userActive,
+				CreatedAt: *userCreatedAt,
+				UpdatedAt: *userUpdatedAt,
+			}
+		}
+		prizes = append(prizes, prize)
+	}
+
+	return prizes, nil
+}
+
+// optionColumns retorna as colunas de reward_details correspondentes às configurações informadas.
+// Textos vazios removem a configuração
+func optionColumns(options models.RewardOptions) map[string]interface{} {
+	columns := make(map[string]interface{})
+	if options.TotalNumbers != nil {
+		columns["total_numbers"] = *options.TotalNumbers
+	}
+	if options.AllocationMode != nil {
+		columns["allocation_mode"] = *options.AllocationMode
+	}
+	if options.MaxPerOrder != nil {
+		columns["max_per_order"] = *options.MaxPerOrder
+	}
+	if options.MaxPerUser != nil {
+		columns["max_per_user"] = *options.MaxPerUser
+	}
+	if options.QuantityStep != nil {
+		columns["quantity_step"] = *options.QuantityStep
+	}
+	if options.TopBuyerPrize != nil {
+		columns["top_buyer_prize"] = nullIfEmpty(*options.TopBuyerPrize)
+	}
+	if options.DailyTopBuyerPrize != nil {
+		columns["daily_top_buyer_prize"] = nullIfEmpty(*options.DailyTopBuyerPrize)
+	}
+	if options.SalesCloseMinutes != nil {
+		columns["sales_close_minutes"] = *options.SalesCloseMinutes
+	}
+	if options.MinSoldNumbers != nil {
+		columns["min_sold_numbers"] = *options.MinSoldNumbers
+	}
+	return columns
+}
+
+// updateOptions atualiza colunas de reward_details dentro de uma transação
+func updateOptions(tx *sql.Tx, rewardID uuid.UUID, columns map[string]interface{}) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(columns))
+	for field := range columns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	setParts := []string{}
+	args := []interface{}{}
+	for i, field := range fields {
+		setParts = append(setParts, fmt.Sprintf("%s = $%d", field, i+1))
+		args = append(args, columns[field])
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", len(args)+1))
+	args = append(args, time.Now(), rewardID)
+
+	query := fmt.Sprintf("UPDATE reward_details SET %s WHERE reward_id = $%d", strings.Join(setParts, ", "), len(args))
+	_, err := tx.Exec(query, args...)
+	return err
+}
+
+// nullIfEmpty converte textos vazios em NULL
+func nullIfEmpty(value string) interface{} {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return value
+}
+
+// salesDayTimezone é o fuso que define os dias de vendas na apuração do maior comprador do dia. Os horários
+// gravados no banco são tratados como UTC
+const salesDayTimezone = "America/Sao_Paulo"
+
+// execer abstrai *sql.DB e *sql.Tx para comandos compartilhados dentro e fora de transações
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// resolveTopBuyers apura os maiores compradores premiados de um prêmio. O desempate é feito pela
+// compra mais antiga e, persistindo o empate, pelo ID do usuário. Apurações já registradas são mantidas
+func resolveTopBuyers(tx *sql.Tx, rewardID uuid.UUID, now time.Time) error {
+	var topBuyerPrize *string
+	optionsQuery := `SELECT top_buyer_prize FROM reward_details WHERE reward_id = $1`
+	err := tx.QueryRow(optionsQuery, rewardID).Scan(&topBuyerPrize)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if topBuyerPrize != nil {
+		leaderQuery := `
+			INSERT INTO reward_top_buyers (reward_id, kind, user_id, prize_name, total_numbers, first_purchase_at, resolved_at)
+			SELECT $1, 'overall', rb.user_id, $2, COUNT(rb.number), MIN(rb.created_at), $3
+			FROM reward_buyers rb
+			WHERE rb.reward_id = $1 AND rb.status = 'sold'
+			GROUP BY rb.user_id
+			ORDER BY COUNT(rb.number) DESC, MIN(rb.created_at) ASC, rb.user_id ASC
+			LIMIT 1
+			ON CONFLICT (reward_id) WHERE kind = 'overall' DO NOTHING
+		`
+		if _, err := tx.Exec(leaderQuery, rewardID, *topBuyerPrize, now); err != nil {
+			return err
+		}
+	}
+
+	_, err = resolveDailyTopBuyers(tx, &rewardID, now)
+	return err
+}
+
+// resolveDailyTopBuyers apura o maior comprador de cada dia de vendas já encerrado, contado no fuso
+// salesDayTimezone pela data de pagamento do pedido. Com as vendas do prêmio encerradas, o dia corrente também
+// é apurado. Sem rewardID, percorre os prêmios publicados ou com vendas encerradas; com ele, apura apenas esse
+// prêmio em qualquer situação. Os dias já apurados são mantidos e o número de dias apurados é retornado
+func resolveDailyTopBuyers(db execer, rewardID *uuid.UUID, now time.Time) (int64, error) {
+	query := `
+		INSERT INTO reward_top_buyers (reward_id, kind, user_id, prize_name, total_numbers, first_purchase_at, reference_date, resolved_at)
+		SELECT DISTINCT ON (d.reward_id, d.sales_day)
+			d.reward_id, 'daily', d.user_id, d.prize_name, d.total_numbers, d.first_purchase_at, d.sales_day, $2
+		FROM (
+			SELECT rb.reward_id, rb.user_id, rd.daily_top_buyer_prize AS prize_name,
+				(COALESCE(p.paid_at, rb.created_at) AT TIME ZONE 'UTC' AT TIME ZONE $3)::date AS sales_day,
+				COUNT(rb.number) AS total_numbers, MIN(COALESCE(p.paid_at, rb.created_at)) AS first_purchase_at,
+				BOOL_OR(r.draw_date - make_interval(mins => rd.sales_close_minutes) <= $2) AS sales_closed
+			FROM reward_buyers rb
+			INNER JOIN rewards r ON r.id = rb.reward_id
+			INNER JOIN reward_details rd ON rd.reward_id = rb.reward_id
+			LEFT JOIN purchases p ON p.id = rb.purchase_id
+			WHERE rb.status = 'sold'
+				AND rd.daily_top_buyer_prize IS NOT NULL
+				AND (($1::uuid IS NULL AND r.status IN ('published', 'sales_closed')) OR rb.reward_id = $1::uuid)
+			GROUP BY rb.reward_id, rb.user_id, rd.daily_top_buyer_prize, sales_day
+		) d
+		WHERE d.sales_closed OR d.sales_day < ($4::timestamp AT TIME ZONE 'UTC' AT TIME ZONE $3)::date
+		ORDER BY d.reward_id, d.sales_day, d.total_numbers DESC, d.first_purchase_at ASC, d.user_id ASC
+		ON CONFLICT (reward_id, reference_date) WHERE kind = 'daily' DO NOTHING
+	`
+
+	var rewardParam interface{}
+	if rewardID != nil {
+		rewardParam = *rewardID
+	}
+
+	result, err := db.Exec(query, rewardParam, now, salesDayTimezone, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetTopBuyerWinners busca os maiores compradores premiados de um prêmio
+func (r *RewardRepository) GetTopBuyerWinners(rewardID uuid.UUID) ([]models.TopBuyerWinner, error) {
+	return r.getTopBuyerWinners(r.db, rewardID)
+}
+
+func (r *RewardRepository) getTopBuyerWinners(q queryer, rewardID uuid.UUID) ([]models.TopBuyerWinner, error) {
+	query := `
+		SELECT tb.kind, tb.prize_name, tb.total_numbers, tb.first_purchase_at, tb.reference_date, tb.resolved_at,
+			u.id, u.name, u.email, u.role, u.active, u.created_at, u.updated_at
+		FROM reward_top_buyers tb
+		INNER JOIN users u ON u.id = tb.user_id
+		WHERE tb.reward_id = $1
+		ORDER BY tb.kind DESC, tb.reference_date
+	`
+
+	rows, err := q.Query(query, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	winners := []models.TopBuyerWinner{}
+	for rows.Next() {
+		var winner models.TopBuyerWinner
+		err := rows.Scan(
+			&winner.Kind, &winner.PrizeName, &winner.TotalNumbers, &winner.FirstPurchaseAt,
+			&winner.ReferenceDate, &winner.ResolvedAt,
+			&winner.User.ID, &winner.User.Name, &winner.User.Email, &winner.User.Role,
+			&winner.User.Active, &winner.User.CreatedAt, &winner.User.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		winners = append(winners, winner)
+	}
+
+	return winners, nil
+}
+
+// AddPackage cadastra um pacote de números em um prêmio
+func (r *RewardRepository) AddPackage(rewardID uuid.UUID, req *models.CreateRewardPackageRequest) (*models.RewardPackage, error) {
+	query := `
+		INSERT INTO reward_packages (reward_id, name, quantity, price)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, reward_id, name, quantity, price, created_at
+	`
+
+	var pkg models.RewardPackage
+	err := r.db.QueryRow(query, rewardID, req.Name, req.Quantity, req.Price).Scan(
+		&pkg.ID, &pkg.RewardID, &pkg.Name, &pkg.Quantity, &pkg.Price, &pkg.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, fmt.Errorf("já existe um pacote de %d números neste prêmio", req.Quantity)
+		}
+		return nil, err
+	}
+
+	return &pkg, nil
+}
+
+// GetPackage busca um pacote ativo de um prêmio
+func (r *RewardRepository) GetPackage(rewardID, packageID uuid.UUID) (*models.RewardPackage, error) {
+	query := `
+		SELECT id, reward_id, name, quantity, price, created_at
+		FROM reward_packages
+		WHERE id = $1 AND reward_id = $2 AND active
+	`
+
+	var pkg models.RewardPackage
+	err := r.db.QueryRow(query, packageID, rewardID).Scan(&pkg.ID, &pkg.RewardID, &pkg.Name, &pkg.Quantity, &pkg.Price, &pkg.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("pacote não encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkg, nil
+}
+
+// GetPackages busca os pacotes ativos de um prêmio, do menor para o maior
+func (r *RewardRepository) GetPackages(rewardID uuid.UUID) ([]models.RewardPackage, error) {
+	query := `
+		SELECT id, reward_id, name, quantity, price, created_at
+		FROM reward_packages
+		WHERE reward_id = $1 AND active
+		ORDER BY quantity
+	`
+
+	rows, err := r.db.Query(query, rewardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	packages := []models.RewardPackage{}
+	for rows.Next() {
+		var pkg models.RewardPackage
+		if err := rows.Scan(&pkg.ID, &pkg.RewardID, &pkg.Name, &pkg.Quantity, &pkg.Price, &pkg.CreatedAt); err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, rows.Err()
+}
+
+// DeactivatePackage retira um pacote da venda. O pacote continua registrado nos pedidos que o usaram
+func (r *RewardRepository) DeactivatePackage(rewardID, packageID uuid.UUID) error {
+	result, err := r.db.Exec(`
+		UPDATE reward_packages SET active = false, updated_at = NOW()
+		WHERE id = $1 AND reward_id = $2 AND active
+	`, packageID, rewardID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("pacote não encontrado")
+	}
+	return nil
 }
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCancelRewardKeepsPaidOrdersForRefund(t *testing.T) {
+	db := openTestDB(t)
+	rewardRepo := NewRewardRepository(db)
+	purchaseRepo := NewPurchaseRepository(db)
+	rewardID := createTestReward(t, db, createTestUser(t, db), models.RewardOptions{TotalNumbers: intPtr(10)})
+	buyerID := createTestUser(t, db)
+	expiresAt := time.Now().Add(time.Hour)
+
+	paid, err := purchaseRepo.Create(rewardID, buyerID, 2, nil, nil, 0, "", expiresAt)
+	if err != nil {
+		t.Fatalf("Create() erro inesperado: %v", err)
+	}
+	if _, err := purchaseRepo.MarkPaid(paid.ID); err != nil {
+		t.Fatalf("MarkPaid() erro inesperado: %v", err)
+	}
+	pending, err := purchaseRepo.Create(rewardID, buyerID, 3, nil, nil, 0, "", expiresAt)
+	if err != nil {
+		t.Fatalf("Create() erro inesperado: %v", err)
+	}
+
+	reward, cancelledPending, err := rewardRepo.CancelReward(rewardID)
+	if err != nil {
+		t.Fatalf("CancelReward() erro inesperado: %v", err)
+	}
+	if reward.Status != models.RewardCancelled {
+		t.Errorf("CancelReward() situação = %s, esperado %s", reward.Status, models.RewardCancelled)
+	}
+	if len(cancelledPending) != 1 || cancelledPending[0].ID != pending.ID || cancelledPending[0].UserID != buyerID ||
+		len(cancelledPending[0].Numbers) != 3 {
+		t.Fatalf("CancelReward() pendentes = %+v, esperado o pedido %s com 3 números", cancelledPending, pending.ID)
+	}
+	assertPurchaseStatus(t, purchaseRepo, pending.ID, models.PaymentCancelled)
+
+	// O pedido pago fica aguardando devolução até ser cancelado
+	waiting, err := purchaseRepo.ListPaidOnCancelledRewards(1000)
+	if err != nil {
+		t.Fatalf("ListPaidOnCancelledRewards() erro inesperado: %v", err)
+	}
+	if !containsPurchase(waiting, paid.ID) {
+		t.Fatalf("ListPaidOnCancelledRewards() não listou o pedido pago %s", paid.ID)
+	}
+
+	if _, err := purchaseRepo.Cancel(paid.ID, nil, "prêmio cancelado"); err != nil {
+		t.Fatalf("Cancel() erro inesperado: %v", err)
+	}
+	waiting, err = purchaseRepo.ListPaidOnCancelledRewards(1000)
+	if err != nil {
+		t.Fatalf("ListPaidOnCancelledRewards() erro inesperado: %v", err)
+	}
+	if containsPurchase(waiting, paid.ID) {
+		t.Errorf("ListPaidOnCancelledRewards() listou o pedido %s já devolvido", paid.ID)
+	}
+}
+
+func containsPurchase(purchases []models.Purchase, id uuid.UUID) bool {
+	for _, purchase := range purchases {
+		if purchase.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/google/uuid"
+)
+
+// settlementColumns lista as colunas lidas por scanSettlement
+const settlementColumns = `s.id, s.reward_id, r.name, s.owner_id, s.paid_purchases, s.gross_amount, s.direct_amount, s.fee_percent, s.fee_amount,
+	s.net_amount, s.status, s.payout_reference, s.paid_at, s.created_at, s.updated_at`
+
+// proceedsQuery apura, para os prêmios de rewards (alias r), o valor bruto dos pedidos pagos à plataforma, o valor
+// pago por PIX direto ao organizador e a taxa da plataforma do prêmio, do organizador ou a padrão ($1).
+// A taxa incide sobre as duas parcelas, mas só a recebida pela plataforma é repassada, então a taxa das vendas
+// por PIX direto é descontada do repasse
+const proceedsQuery = `
+	SELECT r.id, r.name, r.owner_id, sales.paid_purchases, sales.gross_amount, sales.direct_amount, fee.percent,
+		ROUND((sales.gross_amount + sales.direct_amount) * fee.percent / 100, 2) AS fee_amount
+	FROM rewards r
+	INNER JOIN users u ON u.id = r.owner_id
+	LEFT JOIN reward_details rd ON rd.reward_id = r.id
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS paid_purchases,
+			COALESCE(SUM(p.total_amount) FILTER (WHERE p.payment_provider IS DISTINCT FROM 'pix_direct'), 0) AS gross_amount,
+			COALESCE(SUM(p.total_amount) FILTER (WHERE p.payment_provider = 'pix_direct'), 0) AS direct_amount
+		FROM purchases p
+		WHERE p.reward_id = r.id AND p.payment_status = 'paid'
+	) sales
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(rd.platform_fee_percent, u.platform_fee_percent, $1) AS percent
+	) fee
+`
+
+// SettlementRepository implementa as operações de banco de dados dos repasses aos organizadores
+type SettlementRepository struct {
+	db *sql.DB
+}
+
+// NewSettlementRepository cria uma nova instância do repositório de repasses
+func NewSettlementRepository(db *sql.DB) *SettlementRepository {
+	return &SettlementRepository{db: db}
+}
+
+// CreateForReward apura o repasse de um prêmio sorteado. Prêmios já apurados não são alterados
+func (r *SettlementRepository) CreateForReward(rewardID uuid.UUID, defaultFeePercent float64) error {
+	return r.createSettlements(`r.id = $2`, defaultFeePercent, rewardID)
+}
+
+// CreateMissingForOwner apura os repasses dos prêmios sorteados de um organizador que ainda não foram apurados
+func (r *SettlementRepository) CreateMissingForOwner(ownerID uuid.UUID, defaultFeePercent float64) error {
+	return r.createSettlements(`r.owner_id = $2`, defaultFeePercent, ownerID)
+}
+
+func (r *SettlementRepository) createSettlements(condition string, defaultFeePercent float64, arg uuid.UUID) error {
+	query := `
+		INSERT INTO reward_settlements (reward_id, owner_id, paid_purchases, gross_amount, direct_amount, fee_percent, fee_amount, net_amount)
+		SELECT id, owner_id, paid_purchases, gross_amount, direct_amount, percent, fee_amount, gross_amount - fee_amount
+		FROM (` + proceedsQuery + ` WHERE r.status IN ('drawn', 'delivered') AND ` + condition + `) proceeds
+		ON CONFLICT (reward_id) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(query, defaultFeePercent, arg); err != nil {
+		return fmt.Errorf("erro ao apurar repasse: %w", err)
+	}
+	return nil
+}
+
+// ListByOwner lista os repasses de um organizador, do mais recente para o mais antigo
+func (r *SettlementRepository) ListByOwner(ownerID uuid.UUID) ([]models.RewardSettlement, error) {
+	query := `
+		SELECT ` + settlementColumns + `
+		FROM reward_settlements s
+		INNER JOIN rewards r ON r.id = s.reward_id
+		WHERE s.owner_id = $1
+		ORDER BY s.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar repasses: %w", err)
+	}
+	defer rows.Close()
+
+	settlements := []models.RewardSettlement{}
+	for rows.Next() {
+		settlement, err := scanSettlement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear repasse: %w", err)
+		}
+		settlements = append(settlements, *settlement)
+	}
+
+	return settlements, rows.Err()
+}
+
+// ListUpcomingProceeds apura o valor em andamento dos prêmios ainda não sorteados de um organizador
+func (r *SettlementRepository) ListUpcomingProceeds(ownerID uuid.UUID, defaultFeePercent float64) ([]models.RewardProceeds, error) {
+	query := proceedsQuery + ` WHERE r.status IN ('published', 'sales_closed') AND r.owner_id = $2 ORDER BY r.draw_date`
+
+	rows, err := r.db.Query(query, defaultFeePercent, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao apurar vendas: %w", err)
+	}
+	defer rows.Close()
+
+	proceeds := []models.RewardProceeds{}
+	for rows.Next() {
+		var p models.RewardProceeds
+		var owner uuid.UUID
+		err := rows.Scan(&p.RewardID, &p.RewardName, &owner, &p.PaidPurchases, &p.GrossAmount, &p.DirectAmount, &p.FeePercent, &p.FeeAmount)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao escanear vendas: %w", err)
+		}
+		if p.NetAmount, err = p.GrossAmount.Sub(p.FeeAmount); err != nil {
+			return nil, err
+		}
+		proceeds = append(proceeds, p)
+	}
+
+	return proceeds, rows.Err()
+}
+
+// MarkPaid registra o pagamento de um repasse pendente
+func (r *SettlementRepository) MarkPaid(id uuid.UUID, reference string) (*models.RewardSettlement, error) {
+	query := `
+		WITH updated AS (
+			UPDATE reward_settlements
+			SET status = 'paid', payout_reference = $2, paid_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND status = 'pending'
+			RETURNING *
+		)
+		SELECT ` + settlementColumns + `
+		FROM updated s
+		INNER JOIN rewards r ON r.id = s.reward_id
+	`
+
+	settlement, err := scanSettlement(r.db.QueryRow(query, id, reference))
+	if err == nil {
+		return settlement, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("erro ao registrar pagamento do repasse: %w", err)
+	}
+
+	var status string
+	err = r.db.QueryRow(`SELECT status FROM reward_settlements WHERE id = $1`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("repasse não encontrado")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar repasse: %w", err)
+	}
+	return nil, errors.New("repasse já foi pago")
+}
+
+// SetRewardFee define a taxa da plataforma de um prêmio; nil remove a taxa específica
+func (r *SettlementRepository) SetRewardFee(rewardID uuid.UUID, percent *float64) error {
+	result, err := r.db.Exec(`UPDATE reward_details SET platform_fee_percent = $2 WHERE reward_id = $1`, rewardID, percent)
+	if err != nil {
+		return fmt.Errorf("erro ao definir taxa do prêmio: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("prêmio não encontrado")
+	}
+	return nil
+}
+
+// SetOrganizerFee define a taxa da plataforma de um organizador; nil remove a taxa específica
+func (r *SettlementRepository) SetOrganizerFee(userID uuid.UUID, percent *float64) error {
+	result, err := r.db.Exec(`UPDATE users SET platform_fee_percent = $2 WHERE id = $1`, userID, percent)
+	if err != nil {
+		return fmt.Errorf("erro ao definir taxa do organizador: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return errors.New("usuário não encontrado")
+	}
+	return nil
+}
+
+// scanSettlement lê um repasse a partir das colunas de settlementColumns
+func scanSettlement(row rowScanner) (*models.RewardSettlement, error) {
+	var s models.RewardSettlement
+	err := row.Scan(&s.ID, &s.RewardID, &s.RewardName, &s.OwnerID, &s.PaidPurchases, &s.GrossAmount, &s.DirectAmount, &s.FeePercent,
+		&s.FeeAmount, &s.NetAmount, &s.Status, &s.PayoutReference, &s.PaidAt, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+)
+
+// openTestDB conecta ao banco de TEST_DATABASE_URL e aplica as migrações. Sem a variável o teste é ignorado.
+// Cada teste cria seus próprios usuários e prêmios, então o banco pode ser compartilhado entre execuções
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL não definida; testes com banco ignorados")
+	}
+
+	m, err := migrate.New("file://../../migrations", dsn)
+	if err != nil {
+		t.Fatalf("erro ao criar migrator: %v", err)
+	}
+	defer m.Close()
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		t.Fatalf("erro ao executar migrações: %v", err)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("erro ao abrir banco de testes: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// createTestUser cadastra um usuário com e-mail único
+func createTestUser(t *testing.T, db *sql.DB) uuid.UUID {
+	t.Helper()
+
+	user := &models.User{
+		Name:     "Usuário de teste",
+		Email:    uuid.NewString() + "@teste.local",
+		Password: "senha-de-teste",
+		Active:   true,
+	}
+	if err := NewUserRepository(db).Create(user); err != nil {
+		t.Fatalf("erro ao criar usuário: %v", err)
+	}
+	return user.ID
+}
+
+// createTestReward cadastra um prêmio publicado de R$ 10,00 por número, com sorteio em dois dias
+func createTestReward(t *testing.T, db *sql.DB, ownerID uuid.UUID, options models.RewardOptions) uuid.UUID {
+	t.Helper()
+
+	now := time.Now()
+	reward := &models.Reward{
+		ID:        uuid.New(),
+		OwnerID:   ownerID,
+		Name:      "Prêmio de teste",
+		DrawDate:  now.Add(48 * time.Hour),
+		Status:    models.RewardPublished,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := NewRewardRepository(db).Create(reward, money.FromCents(1000), 1, nil, nil, options); err != nil {
+		t.Fatalf("erro ao criar prêmio: %v", err)
+	}
+	return reward.ID
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
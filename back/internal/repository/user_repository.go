@@ -190,4 +190,49 @@ func (r *UserRepository) EmailExists(email string) (bool, error) {
 	}
 
 	return exists, nil
-} 
\ No newline at end of file
+} 
+
+// GetPixSettings busca a chave PIX configurada pelo usuário. Retorna nil se não houver chave
+func (r *UserRepository) GetPixSettings(id uuid.UUID) (*models.PixSettings, error) {
+	query := `SELECT name, pix_key, pix_merchant_city FROM users WHERE id = $1`
+
+	var name string
+	var key, city sql.NullString
+	err := r.db.QueryRow(query, id).Scan(&name, &key, &city)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("usuário não encontrado")
+		}
+		return nil, fmt.Errorf("erro ao buscar chave PIX: %w", err)
+	}
+
+	if !key.Valid || key.String == "" {
+		return nil, nil
+	}
+
+	return &models.PixSettings{
+		PixKey:       key.String,
+		MerchantName: name,
+		MerchantCity: city.String,
+	}, nil
+}
+
+// UpdatePixSettings define a chave PIX do usuário
+func (r *UserRepository) UpdatePixSettings(id uuid.UUID, key, city string) error {
+	query := `UPDATE users SET pix_key = $1, pix_merchant_city = $2, updated_at = $3 WHERE id = $4`
+
+	result, err := r.db.Exec(query, key, city, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar chave PIX: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("usuário não encontrado")
+	}
+
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 // SetupRoutes configura todas as rotas da aplicação
-func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, rewardHandler *handlers.RewardHandler, jwtSecret string) {
+func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, rewardHandler *handlers.RewardHandler, drawSchedulerHandler *handlers.DrawSchedulerHandler, paymentHandler *handlers.PaymentHandler, walletHandler *handlers.WalletHandler, settlementHandler *handlers.SettlementHandler, couponHandler *handlers.CouponHandler, referralHandler *handlers.ReferralHandler, notificationHandler *handlers.NotificationHandler, claimHandler *handlers.PrizeClaimHandler, jwtSecret string) {
 	// Middleware global
 	router.Use(middleware.CORS())
 	router.Use(middleware.Logger())
@@ -44,6 +44,13 @@ func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, rewardHa
 			users.GET("/:id", userHandler.GetByID)
 			users.PUT("/:id", userHandler.Update)
 			users.DELETE("/:id", userHandler.Delete)
+			users.GET("/:id/pix-key", userHandler.GetPixSettings)
+			users.PUT("/:id/pix-key", userHandler.UpdatePixSettings)
+			users.GET("/:id/wallet", walletHandler.GetWallet)
+			users.GET("/:id/wallet/transactions", walletHandler.ListTransactions)
+			users.PUT("/:id/platform-fee", settlementHandler.SetOrganizerFee)
+			users.GET("/:id/referral", referralHandler.GetCode)
+			users.GET("/:id/referral/stats", referralHandler.GetStats)
 		}
 
 		// Rotas de compras (protegidas por autenticação)
@@ -51,6 +58,60 @@ func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, rewardHa
 		purchases.Use(middleware.AuthMiddleware(jwtSecret))
 		{
 			purchases.GET("/user/:user_id", rewardHandler.GetUserPurchases)
+			purchases.GET("/:id", rewardHandler.GetPurchase)
+			purchases.GET("/:id/pix", rewardHandler.GetPurchasePix)
+			purchases.GET("/:id/pix/qrcode", rewardHandler.GetPurchasePixQRCode)
+			purchases.POST("/:id/confirm", rewardHandler.ConfirmPurchase)
+			purchases.POST("/:id/cancel", rewardHandler.CancelPurchase)
+			purchases.GET("/:id/refunds", rewardHandler.ListPurchaseRefunds)
+		}
+
+		// Rotas de repasses aos organizadores (protegidas por autenticação)
+		settlements := api.Group("/settlements")
+		settlements.Use(middleware.AuthMiddleware(jwtSecret))
+		{
+			settlements.GET("/mine", settlementHandler.ListMine)
+			settlements.POST("/:id/pay", settlementHandler.MarkPaid)
+		}
+
+		// Rotas de cupons dos organizadores (protegidas por autenticação)
+		coupons := api.Group("/coupons")
+		coupons.Use(middleware.AuthMiddleware(jwtSecret))
+		{
+			coupons.POST("/", couponHandler.Create)
+			coupons.GET("/mine", couponHandler.ListMine)
+			coupons.PUT("/:id", couponHandler.Update)
+			coupons.GET("/:id/redemptions", couponHandler.ListRedemptions)
+		}
+
+		// Rotas de avisos do usuário (protegidas por autenticação)
+		notifications := api.Group("/notifications")
+		notifications.Use(middleware.AuthMiddleware(jwtSecret))
+		{
+			notifications.GET("/mine", notificationHandler.ListMine)
+			notifications.POST("/:id/read", notificationHandler.MarkRead)
+		}
+
+		// Rotas de resgate dos prêmios sorteados (protegidas por autenticação)
+		claims := api.Group("/claims")
+		claims.Use(middleware.AuthMiddleware(jwtSecret))
+		{
+			claims.GET("/mine", claimHandler.ListMine)
+			claims.GET("/:id", claimHandler.GetByID)
+			claims.POST("/:id/confirm", claimHandler.Confirm)
+			claims.POST("/:id/ship", claimHandler.Ship)
+			claims.POST("/:id/receive", claimHandler.Receive)
+			claims.POST("/:id/redraw", claimHandler.Redraw)
+		}
+
+		// Webhook do provedor de pagamento (autenticado pela assinatura do corpo)
+		api.POST("/payments/webhook", paymentHandler.Webhook)
+
+		// Rotas do agendador de sorteios (protegidas por autenticação)
+		draws := api.Group("/draws")
+		draws.Use(middleware.AuthMiddleware(jwtSecret))
+		{
+			draws.GET("/status", drawSchedulerHandler.Status)
 		}
 
 		// Rotas de autenticação
@@ -68,6 +129,10 @@ func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, rewardHa
 			rewards.GET("/:id", rewardHandler.GetByID)
 			rewards.GET("/:id/details", rewardHandler.GetDetailsByID)
 			rewards.GET("/:id/buyers", rewardHandler.GetBuyers)
+			rewards.GET("/:id/draw/proof", rewardHandler.GetDrawProof)
+			rewards.GET("/:id/instant-prizes", rewardHandler.ListInstantPrizes)
+			rewards.GET("/:id/packages", rewardHandler.ListPackages)
+			rewards.GET("/:id/numbers", rewardHandler.GetNumberAvailability)
 
 			// Rotas protegidas (com autenticação)
 			protectedRewards := rewards.Group("/")
@@ -77,12 +142,23 @@ func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, rewardHa
 				protectedRewards.GET("/mine", rewardHandler.ListMyRewards)
 				protectedRewards.PUT("/:id", rewardHandler.Update)
 				protectedRewards.DELETE("/:id", rewardHandler.Delete)
+				protectedRewards.PUT("/:id/platform-fee", settlementHandler.SetRewardFee)
 
 				// Rotas de compradores protegidas
 				protectedRewards.POST("/:id/buyers/:user_id", rewardHandler.AddBuyer)
 				protectedRewards.DELETE("/:id/buyers/:user_id", rewardHandler.RemoveBuyer)
 				protectedRewards.GET("/:id/buyers/:user_id/numbers", rewardHandler.GetUserNumbers)
+				protectedRewards.POST("/:id/publish", rewardHandler.Publish)
+				protectedRewards.POST("/:id/close-sales", rewardHandler.CloseSales)
+				protectedRewards.POST("/:id/reopen-sales", rewardHandler.ReopenSales)
+				protectedRewards.POST("/:id/cancel", rewardHandler.Cancel)
+				protectedRewards.POST("/:id/postpone", rewardHandler.Postpone)
+				protectedRewards.POST("/:id/deliver", rewardHandler.Deliver)
 				protectedRewards.POST("/:id/draw", rewardHandler.Draw)
+				protectedRewards.GET("/:id/claims", claimHandler.ListByReward)
+				protectedRewards.POST("/:id/instant-prizes", rewardHandler.AddInstantPrizes)
+				protectedRewards.POST("/:id/packages", rewardHandler.AddPackage)
+				protectedRewards.DELETE("/:id/packages/:package_id", rewardHandler.RemovePackage)
 			}
 		}
 	}
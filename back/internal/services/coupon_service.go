@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// CouponService implementa a lógica de negócio dos cupons de desconto dos organizadores
+type CouponService struct {
+	couponRepo *repository.CouponRepository
+	rewardRepo *repository.RewardRepository
+}
+
+// NewCouponService cria uma nova instância do serviço de cupons
+func NewCouponService(couponRepo *repository.CouponRepository, rewardRepo *repository.RewardRepository) *CouponService {
+	return &CouponService{
+		couponRepo: couponRepo,
+		rewardRepo: rewardRepo,
+	}
+}
+
+// Create cadastra um cupom do organizador, para um prêmio dele ou para todos os seus prêmios
+func (s *CouponService) Create(ownerID uuid.UUID, req *models.CreateCouponRequest) (*models.Coupon, error) {
+	if req.RewardID != nil {
+		reward, err := s.rewardRepo.GetByID(*req.RewardID)
+		if err != nil {
+			return nil, errors.New("prêmio não encontrado")
+		}
+		if reward.OwnerID != ownerID {
+			return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+		}
+	}
+
+	coupon := &models.Coupon{
+		OwnerID:        ownerID,
+		RewardID:       req.RewardID,
+		Code:           strings.ToUpper(req.Code),
+		Type:           req.Type,
+		MinQuantity:    req.MinQuantity,
+		MaxUses:        req.MaxUses,
+		MaxUsesPerUser: req.MaxUsesPerUser,
+		StartsAt:       req.StartsAt,
+		ExpiresAt:      req.ExpiresAt,
+	}
+
+	// Cada tipo usa apenas o seu valor de desconto
+	switch req.Type {
+	case models.CouponPercentage:
+		if req.DiscountPercent == nil {
+			return nil, errors.New("cupons percentage exigem discount_percent")
+		}
+		coupon.DiscountPercent = req.DiscountPercent
+	case models.CouponFixed:
+		if req.DiscountAmount == nil {
+			return nil, errors.New("cupons fixed exigem discount_amount")
+		}
+		coupon.DiscountAmount = req.DiscountAmount
+	case models.CouponBonusNumbers:
+		if req.BonusNumbers == nil || req.MinQuantity == nil {
+			return nil, errors.New("cupons bonus_numbers exigem bonus_numbers e min_quantity")
+		}
+		coupon.BonusNumbers = req.BonusNumbers
+	}
+
+	if err := checkCouponValidity(coupon.StartsAt, coupon.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return s.couponRepo.Create(coupon)
+}
+
+// ListMine lista os cupons do organizador com a quantidade de usos de cada um
+func (s *CouponService) ListMine(ownerID uuid.UUID) (*models.CouponListResponse, error) {
+	coupons, err := s.couponRepo.ListByOwner(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CouponListResponse{Coupons: coupons}, nil
+}
+
+// Update atualiza a situação, os limites de uso e a validade de um cupom (apenas o organizador)
+func (s *CouponService) Update(id, ownerID uuid.UUID, req *models.UpdateCouponRequest) (*models.Coupon, error) {
+	coupon, err := s.getOwnCoupon(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Active != nil {
+		coupon.Active = *req.Active
+	}
+	if req.MaxUses != nil {
+		coupon.MaxUses = req.MaxUses
+	}
+	if req.MaxUsesPerUser != nil {
+		coupon.MaxUsesPerUser = req.MaxUsesPerUser
+	}
+	if req.StartsAt != nil {
+		coupon.StartsAt = req.StartsAt
+	}
+	if req.ExpiresAt != nil {
+		coupon.ExpiresAt = req.ExpiresAt
+	}
+
+	if err := checkCouponValidity(coupon.StartsAt, coupon.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepo.Update(coupon); err != nil {
+		return nil, err
+	}
+
+	return s.couponRepo.GetByID(id)
+}
+
+// ListRedemptions lista os resgates de um cupom (apenas o organizador)
+func (s *CouponService) ListRedemptions(id, ownerID uuid.UUID) (*models.CouponRedemptionListResponse, error) {
+	if _, err := s.getOwnCoupon(id, ownerID); err != nil {
+		return nil, err
+	}
+
+	redemptions, err := s.couponRepo.ListRedemptions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CouponRedemptionListResponse{Redemptions: redemptions}, nil
+}
+
+// getOwnCoupon busca um cupom conferindo se pertence ao organizador
+func (s *CouponService) getOwnCoupon(id, ownerID uuid.UUID) (*models.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if coupon.OwnerID != ownerID {
+		return nil, errors.New("apenas o organizador do cupom pode realizar esta operação")
+	}
+	return coupon, nil
+}
+
+// checkCouponValidity confere se a validade do cupom termina depois de começar
+func checkCouponValidity(startsAt, expiresAt *time.Time) error {
+	if startsAt != nil && expiresAt != nil && !expiresAt.After(*startsAt) {
+		return errors.New("a validade do cupom deve terminar depois do início")
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/config"
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// drawBatchSize limita quantos prêmios são sorteados a cada execução do agendador
+const drawBatchSize = 50
+
+// maxRetryDelay limita o intervalo entre novas tentativas de um sorteio que falhou
+const maxRetryDelay = time.Hour
+
+// DrawScheduler executa automaticamente os sorteios cuja data já passou
+type DrawScheduler struct {
+	rewardService *RewardService
+	scheduleRepo  *repository.DrawScheduleRepository
+	cfg           config.DrawConfig
+
+	mu        sync.Mutex
+	lastRunAt *time.Time
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewDrawScheduler cria uma nova instância do agendador de sorteios
+func NewDrawScheduler(rewardService *RewardService, scheduleRepo *repository.DrawScheduleRepository, cfg config.DrawConfig) *DrawScheduler {
+	return &DrawScheduler{
+		rewardService: rewardService,
+		scheduleRepo:  scheduleRepo,
+		cfg:           cfg,
+	}
+}
+
+// Start inicia o agendador em segundo plano, se estiver habilitado
+func (s *DrawScheduler) Start() {
+	if !s.cfg.SchedulerEnabled {
+		log.Println("Agendador de sorteios desabilitado")
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		s.RunOnce()
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	log.Printf("Agendador de sorteios iniciado (intervalo: %s)", s.cfg.Interval)
+}
+
+// Stop interrompe o agendador e aguarda a execução em andamento terminar
+func (s *DrawScheduler) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+}
+
+// RunOnce encerra as vendas dos prêmios no horário de encerramento, apura o maior comprador dos dias de vendas
+// encerrados e sorteia os prêmios vencidos.
+// Se outra instância da API estiver executando, não faz nada
+func (s *DrawScheduler) RunOnce() {
+	acquired, release, err := s.scheduleRepo.TryLock()
+	if err != nil {
+		log.Printf("Erro ao obter lock do agendador de sorteios: %v", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer release()
+
+	now := time.Now()
+	s.mu.Lock()
+	s.lastRunAt = &now
+	s.mu.Unlock()
+
+	closed, err := s.scheduleRepo.CloseDueSales(now)
+	if err != nil {
+		log.Printf("Erro ao encerrar vendas de prêmios: %v", err)
+	} else if closed > 0 {
+		log.Printf("Vendas de %d prêmio(s) encerradas antes do sorteio", closed)
+	}
+
+	resolved, err := s.scheduleRepo.ResolveDailyTopBuyers(now)
+	if err != nil {
+		log.Printf("Erro ao apurar os maiores compradores do dia: %v", err)
+	} else if resolved > 0 {
+		log.Printf("Maior comprador apurado em %d dia(s) de vendas", resolved)
+	}
+
+	rewardIDs, err := s.scheduleRepo.ListDue(now, s.cfg.MaxAttempts, drawBatchSize)
+	if err != nil {
+		log.Printf("Erro ao buscar sorteios pendentes: %v", err)
+		return
+	}
+
+	for _, rewardID := range rewardIDs {
+		result, err := s.rewardService.Draw(rewardID)
+		var belowMinimum *models.MinimumSalesError
+		if errors.As(err, &belowMinimum) && belowMinimum.Cancelled {
+			if err := s.scheduleRepo.ClearFailure(rewardID); err != nil {
+				log.Printf("Erro ao limpar falhas do sorteio do prêmio %s: %v", rewardID, err)
+			}
+			log.Printf("Prêmio %s cancelado no sorteio automático: %v", rewardID, err)
+			continue
+		}
+		if err != nil && err.Error() == "nenhum número foi comprado para este prêmio" {
+			// Sem números vendidos o prêmio nunca poderia ser sorteado e ficaria com as vendas encerradas para sempre
+			if err := s.rewardService.CancelUnsold(rewardID); err != nil {
+				s.recordFailure(rewardID, err)
+				continue
+			}
+			if err := s.scheduleRepo.ClearFailure(rewardID); err != nil {
+				log.Printf("Erro ao limpar falhas do sorteio do prêmio %s: %v", rewardID, err)
+			}
+			log.Printf("Prêmio %s cancelado no sorteio automático: nenhum número vendido", rewardID)
+			continue
+		}
+		if err != nil {
+			s.recordFailure(rewardID, err)
+			continue
+		}
+
+		if err := s.scheduleRepo.ClearFailure(rewardID); err != nil {
+			log.Printf("Erro ao limpar falhas do sorteio do prêmio %s: %v", rewardID, err)
+		}
+		log.Printf("Sorteio automático do prêmio %s realizado. Número vencedor: %d", rewardID, result.WinnerNumber)
+	}
+}
+
+// Status retorna os sorteios pendentes e as falhas registradas
+func (s *DrawScheduler) Status() (*models.DrawSchedulerStatusResponse, error) {
+	now := time.Now()
+
+	pending, err := s.scheduleRepo.ListPending(now)
+	if err != nil {
+		return nil, err
+	}
+
+	failed, err := s.scheduleRepo.ListFailures(s.cfg.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	lastRunAt := s.lastRunAt
+	s.mu.Unlock()
+
+	return &models.DrawSchedulerStatusResponse{
+		Enabled:     s.cfg.SchedulerEnabled,
+		Interval:    s.cfg.Interval.String(),
+		MaxAttempts: s.cfg.MaxAttempts,
+		LastRunAt:   lastRunAt,
+		Pending:     pending,
+		Failed:      failed,
+	}, nil
+}
+
+// recordFailure registra a falha de um sorteio com espera exponencial até a próxima tentativa
+func (s *DrawScheduler) recordFailure(rewardID uuid.UUID, drawErr error) {
+	log.Printf("Erro no sorteio automático do prêmio %s: %v", rewardID, drawErr)
+
+	attempts, err := s.scheduleRepo.GetAttempts(rewardID)
+	if err != nil {
+		log.Printf("Erro ao buscar tentativas do sorteio do prêmio %s: %v", rewardID, err)
+		return
+	}
+
+	now := time.Now()
+	if err := s.scheduleRepo.RecordFailure(rewardID, drawErr, now, now.Add(retryDelay(s.cfg.Interval, attempts))); err != nil {
+		log.Printf("Erro ao registrar falha do sorteio do prêmio %s: %v", rewardID, err)
+	}
+}
+
+// retryDelay calcula a espera até a próxima tentativa: o intervalo do agendador dobrado a cada tentativa já feita,
+// limitado a maxRetryDelay
+func retryDelay(interval time.Duration, attempts int) time.Duration {
+	delay := interval << attempts
+	if delay <= 0 || delay > maxRetryDelay || delay>>attempts != interval {
+		return maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		attempts int
+		want     time.Duration
+	}{
+		{name: "primeira falha", interval: time.Minute, attempts: 0, want: time.Minute},
+		{name: "dobra a cada tentativa", interval: time.Minute, attempts: 3, want: 8 * time.Minute},
+		{name: "limitado a uma hora", interval: time.Minute, attempts: 6, want: maxRetryDelay},
+		{name: "intervalo maior que o limite", interval: 2 * time.Hour, attempts: 0, want: maxRetryDelay},
+		{name: "deslocamento que estoura", interval: time.Minute, attempts: 40, want: maxRetryDelay},
+		{name: "tentativas além do tamanho da duração", interval: time.Second, attempts: 100, want: maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryDelay(tt.interval, tt.attempts); got != tt.want {
+				t.Errorf("retryDelay(%s, %d) = %s, esperado %s", tt.interval, tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"math"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// NotificationService implementa o envio e a consulta dos avisos aos usuários
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewNotificationService cria uma nova instância do serviço de avisos
+func NewNotificationService(notificationRepo *repository.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// Notify registra um aviso a um usuário
+func (s *NotificationService) Notify(notification *models.Notification) error {
+	return s.notificationRepo.Create(notification)
+}
+
+// ListMine lista os avisos de um usuário com paginação
+func (s *NotificationService) ListMine(userID uuid.UUID, page, limit int) (*models.NotificationListResponse, error) {
+	if limit > 100 {
+		limit = 100
+	}
+
+	notifications, total, unread, err := s.notificationRepo.ListByUser(userID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &models.NotificationListResponse{
+		Notifications: notifications,
+		Unread:        unread,
+		Pagination: models.Pagination{
+			Page:    page,
+			Limit:   limit,
+			Total:   total,
+			Pages:   pages,
+			HasNext: page < pages,
+			HasPrev: page > 1,
+		},
+	}, nil
+}
+
+// MarkRead marca um aviso do usuário como lido
+func (s *NotificationService) MarkRead(id, userID uuid.UUID) (*models.Notification, error) {
+	return s.notificationRepo.MarkRead(id, userID)
+}
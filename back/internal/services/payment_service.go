@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/payments"
+	"github.com/cauamistura/BNUPremios/internal/pix"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// paymentTimeout limita o tempo de cada chamada ao provedor de pagamento
+const paymentTimeout = 15 * time.Second
+
+// Erros de validação dos webhooks de pagamento
+var (
+	ErrInvalidWebhookSignature = errors.New("assinatura do webhook inválida")
+	ErrInvalidWebhookPayload   = errors.New("notificação inválida")
+)
+
+// PaymentRefundedError indica que o pagamento recebido não pôde ser aplicado ao pedido e a devolução já foi
+// registrada. Para o provedor a notificação está tratada; a mensagem é a do motivo original
+type PaymentRefundedError struct {
+	Err error
+}
+
+func (e *PaymentRefundedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PaymentRefundedError) Unwrap() error {
+	return e.Err
+}
+
+// PaymentService liga os pedidos de compra às cobranças do provedor de pagamento
+type PaymentService struct {
+	purchaseRepo  *repository.PurchaseRepository
+	userRepo      *repository.UserRepository
+	refundRepo    *repository.RefundRepository
+	ledgerRepo    *repository.LedgerRepository
+	provider      payments.PaymentProvider
+	webhookSecret string
+	directPixTTL  time.Duration
+}
+
+// NewPaymentService cria uma nova instância do serviço de pagamentos. directPixTTL é o prazo de reserva dos pedidos
+// de PIX direto, maior que o normal porque o recebimento é conferido pelo organizador
+func NewPaymentService(purchaseRepo *repository.PurchaseRepository, userRepo *repository.UserRepository, refundRepo *repository.RefundRepository, ledgerRepo *repository.LedgerRepository, provider payments.PaymentProvider, webhookSecret string, directPixTTL time.Duration) *PaymentService {
+	return &PaymentService{
+		purchaseRepo:  purchaseRepo,
+		userRepo:      userRepo,
+		refundRepo:    refundRepo,
+		ledgerRepo:    ledgerRepo,
+		provider:      provider,
+		webhookSecret: webhookSecret,
+		directPixTTL:  directPixTTL,
+	}
+}
+
+// CreateCharge cria a cobrança de um pedido pendente e a registra no pedido. Se o organizador
+// configurou uma chave PIX, a cobrança é um BR Code estático para essa chave; senão é criada no provedor
+func (s *PaymentService) CreateCharge(purchase *models.Purchase, organizerID uuid.UUID) error {
+	settings, err := s.userRepo.GetPixSettings(organizerID)
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		return s.createDirectCharge(purchase, settings)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+	defer cancel()
+
+	charge, err := s.provider.CreateCharge(ctx, payments.ChargeRequest{
+		TxID:        chargeTxID(purchase.ID),
+		Amount:      purchase.TotalAmount,
+		Description: chargeDescription(purchase),
+		ExpiresIn:   time.Until(purchase.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	provider := s.provider.Name()
+	if err := s.purchaseRepo.SetCharge(purchase.ID, provider, charge.ID, charge.CopyPaste); err != nil {
+		return err
+	}
+
+	purchase.PaymentProvider = &provider
+	purchase.ChargeID = &charge.ID
+	if charge.CopyPaste != "" {
+		purchase.PixCopyPaste = &charge.CopyPaste
+	}
+
+	return nil
+}
+
+// createDirectCharge gera o BR Code estático do pedido para a chave PIX do organizador e prorroga a reserva
+// pelo prazo de confirmação do organizador
+func (s *PaymentService) createDirectCharge(purchase *models.Purchase, settings *models.PixSettings) error {
+	txid := chargeTxID(purchase.ID)[:pix.MaxStaticTxIDLength]
+	code, err := pix.Payload{
+		Key:          settings.PixKey,
+		MerchantName: settings.MerchantName,
+		MerchantCity: settings.MerchantCity,
+		Amount:       purchase.TotalAmount,
+		TxID:         txid,
+		Description:  chargeDescription(purchase),
+	}.Encode()
+	if err != nil {
+		return err
+	}
+
+	provider := payments.DirectPixProvider
+	if err := s.purchaseRepo.SetCharge(purchase.ID, provider, txid, code); err != nil {
+		return err
+	}
+
+	expiresAt := purchase.PurchaseDate.Add(s.directPixTTL)
+	if err := s.purchaseRepo.ExtendReservation(purchase.ID, expiresAt); err != nil {
+		return err
+	}
+	purchase.ExpiresAt = expiresAt
+
+	purchase.PaymentProvider = &provider
+	purchase.ChargeID = &txid
+	purchase.PixCopyPaste = &code
+
+	return nil
+}
+
+// Settle consulta a cobrança de um pedido e, se estiver paga, marca o pedido como pago.
+// Retorna nil quando o pagamento ainda não foi confirmado pelo provedor, e *PaymentRefundedError quando o
+// pagamento não pôde ser aplicado e o valor foi devolvido.
+// Pedidos de PIX direto não podem ser consultados: quem chama já verificou que o organizador confirmou o recebimento
+func (s *PaymentService) Settle(purchase *models.Purchase) (*models.BuyNumbersResult, error) {
+	if purchase.ChargeID == nil {
+		return nil, errors.New("compra não possui cobrança")
+	}
+
+	if IsDirectPix(purchase) {
+		return s.purchaseRepo.MarkPaid(purchase.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+	defer cancel()
+
+	charge, err := s.provider.GetCharge(ctx, *purchase.ChargeID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar cobrança: %w", err)
+	}
+
+	if charge.Status != payments.ChargePaid {
+		return nil, nil
+	}
+
+	if cmp, err := charge.PaidAmount.Cmp(purchase.TotalAmount); err != nil || cmp < 0 {
+		underpaid := fmt.Errorf("valor pago (%s) menor que o total da compra (%s)", charge.PaidAmount, purchase.TotalAmount)
+		if s.refundUnfulfilled(purchase, charge, "valor pago menor que o total da compra") {
+			return nil, &PaymentRefundedError{Err: underpaid}
+		}
+		return nil, underpaid
+	}
+
+	result, err := s.purchaseRepo.MarkPaid(purchase.ID)
+	if err != nil {
+		// O comprador pagou, mas os números já foram liberados: o valor é devolvido
+		var reason string
+		switch err.Error() {
+		case "reserva expirada":
+			reason = "pagamento recebido após o fim da reserva"
+		case "compra cancelada":
+			reason = "pagamento recebido após o cancelamento da compra"
+		case "não é possível comprar números de um prêmio já completado":
+			reason = "pagamento recebido após o sorteio do prêmio"
+		case "prêmio cancelado":
+			reason = "pagamento recebido após o cancelamento do prêmio"
+		}
+		if reason != "" && s.refundUnfulfilled(purchase, charge, reason) {
+			return nil, &PaymentRefundedError{Err: err}
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// HandleWebhook valida a assinatura de uma notificação do provedor e liquida os pedidos das cobranças informadas.
+// A situação de cada cobrança é sempre confirmada no provedor, sem confiar apenas no conteúdo da notificação
+func (s *PaymentService) HandleWebhook(body []byte, signature string) (int, error) {
+	if !payments.VerifyWebhook(s.webhookSecret, body, strings.TrimPrefix(signature, "sha256=")) {
+		return 0, ErrInvalidWebhookSignature
+	}
+
+	var req models.PaymentWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+
+	settled := 0
+	var failures []string
+	for _, pix := range req.Pix {
+		purchase, err := s.purchaseRepo.GetByChargeID(s.provider.Name(), pix.TxID)
+		if err != nil {
+			log.Printf("Webhook de pagamento para cobrança desconhecida %s", pix.TxID)
+			continue
+		}
+
+		if purchase.PaymentStatus == models.PaymentPaid {
+			continue
+		}
+
+		result, err := s.Settle(purchase)
+		if err != nil {
+			// Pagamentos já devolvidos estão tratados: o provedor não deve reenviar a notificação
+			var refunded *PaymentRefundedError
+			if errors.As(err, &refunded) {
+				log.Printf("Pagamento da compra %s recebido pelo webhook foi devolvido: %v", purchase.ID, err)
+				continue
+			}
+			log.Printf("Erro ao liquidar compra %s pelo webhook: %v", purchase.ID, err)
+			failures = append(failures, pix.TxID)
+			continue
+		}
+		if result != nil {
+			settled++
+		}
+	}
+
+	if len(failures) > 0 {
+		return settled, fmt.Errorf("não foi possível liquidar as cobranças: %s", strings.Join(failures, ", "))
+	}
+
+	return settled, nil
+}
+
+// Refund processa uma devolução registrada para um pedido. Pedidos pagos com a carteira são devolvidos
+// à carteira; pedidos de PIX direto não têm provedor para devolver o valor e a devolução fica como manual,
+// a cargo do organizador
+func (s *PaymentService) Refund(purchase *models.Purchase, refund *models.PurchaseRefund) *models.PurchaseRefund {
+	var status, failure string
+	switch {
+	case IsDirectPix(purchase):
+		status = models.RefundManual
+	case isWalletPayment(purchase):
+		status, failure = s.refundToWallet(purchase, refund)
+	default:
+		status, failure = s.refundCharge(purchase, refund)
+	}
+
+	if err := s.refundRepo.UpdateStatus(refund.ID, status, failure); err != nil {
+		log.Printf("Erro ao registrar situação da devolução %s: %v", refund.ID, err)
+		return refund
+	}
+
+	refund.Status = status
+	if failure != "" {
+		refund.FailureReason = &failure
+	}
+	return refund
+}
+
+// RecordLateDirectPix registra a devolução devida de um pedido de PIX direto cujo recebimento o organizador
+// confirmou depois que o pedido expirou ou foi cancelado: os números já foram liberados, então o valor recebido
+// fica como devolução manual, a cargo do organizador. Confirmações repetidas retornam a devolução já registrada
+func (s *PaymentService) RecordLateDirectPix(purchase *models.Purchase, organizerID uuid.UUID, reason string) (*models.PurchaseRefund, error) {
+	refunds, err := s.refundRepo.ListByPurchase(purchase.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar devoluções da compra: %w", err)
+	}
+	for i := range refunds {
+		if refunds[i].Status != models.RefundFailed {
+			return &refunds[i], nil
+		}
+	}
+
+	provider := payments.DirectPixProvider
+	refund, err := s.refundRepo.Create(purchase.ID, purchase.TotalAmount, &provider, reason, &organizerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao registrar devolução da compra: %w", err)
+	}
+
+	return s.Refund(purchase, refund), nil
+}
+
+// ListRefunds lista as devoluções de um pedido
+func (s *PaymentService) ListRefunds(purchaseID uuid.UUID) ([]models.PurchaseRefund, error) {
+	return s.refundRepo.ListByPurchase(purchaseID)
+}
+
+// refundToWallet credita na carteira do comprador o valor de uma devolução
+func (s *PaymentService) refundToWallet(purchase *models.Purchase, refund *models.PurchaseRefund) (string, string) {
+	_, err := s.ledgerRepo.Credit(purchase.UserID, models.LedgerAccountSales, models.LedgerRefund, &refund.ID, "Devolução de compra", refund.Amount)
+	if err != nil {
+		log.Printf("Erro ao devolver compra %s para a carteira: %v", purchase.ID, err)
+		return models.RefundFailed, err.Error()
+	}
+	return models.RefundCompleted, ""
+}
+
+// refundCharge solicita ao provedor a devolução da cobrança do pedido
+func (s *PaymentService) refundCharge(purchase *models.Purchase, refund *models.PurchaseRefund) (string, string) {
+	if purchase.ChargeID == nil {
+		return models.RefundFailed, "compra não possui cobrança"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), paymentTimeout)
+	defer cancel()
+
+	result, err := s.provider.Refund(ctx, *purchase.ChargeID, refundID(refund.ID), refund.Amount)
+	if err != nil {
+		log.Printf("Erro ao devolver pagamento da compra %s: %v", purchase.ID, err)
+		return models.RefundFailed, err.Error()
+	}
+
+	switch result.Status {
+	case payments.RefundCompleted:
+		return models.RefundCompleted, ""
+	case payments.RefundFailed:
+		return models.RefundFailed, "devolução recusada pelo provedor"
+	}
+	return models.RefundProcessing, ""
+}
+
+// refundUnfulfilled registra e solicita a devolução de uma cobrança paga cujo pedido não pode mais ser atendido.
+// Retorna true quando a devolução está registrada, inclusive por uma notificação anterior do mesmo pagamento
+func (s *PaymentService) refundUnfulfilled(purchase *models.Purchase, charge *payments.Charge, reason string) bool {
+	// Notificações repetidas do mesmo pagamento não geram uma nova devolução
+	refunds, err := s.refundRepo.ListByPurchase(purchase.ID)
+	if err != nil {
+		log.Printf("Erro ao buscar devoluções da compra %s: %v", purchase.ID, err)
+		return false
+	}
+	for _, existing := range refunds {
+		if existing.Status != models.RefundFailed {
+			return true
+		}
+	}
+
+	// Sem valor recebido não há o que devolver
+	if !charge.PaidAmount.IsPositive() {
+		return false
+	}
+
+	provider := s.provider.Name()
+	refund, err := s.refundRepo.Create(purchase.ID, charge.PaidAmount, &provider, reason, nil)
+	if err != nil {
+		log.Printf("Erro ao registrar devolução da compra %s: %v", purchase.ID, err)
+		return false
+	}
+
+	refund = s.Refund(purchase, refund)
+	log.Printf("Pagamento da compra %s devolvido (devolução %s, situação %s)", purchase.ID, refund.ID, refund.Status)
+	return true
+}
+
+// PixCode devolve o código PIX "copia e cola" de um pedido pendente
+func (s *PaymentService) PixCode(purchase *models.Purchase) (string, error) {
+	if purchase.PaymentStatus != models.PaymentPending {
+		return "", errors.New("compra não está pendente")
+	}
+	if purchase.PixCopyPaste == nil || *purchase.PixCopyPaste == "" {
+		return "", errors.New("compra não possui código PIX")
+	}
+
+	return *purchase.PixCopyPaste, nil
+}
+
+// IsDirectPix indica se o pedido é pago por PIX direto na chave do organizador
+func IsDirectPix(purchase *models.Purchase) bool {
+	return purchase.PaymentProvider != nil && *purchase.PaymentProvider == payments.DirectPixProvider
+}
+
+// isWalletPayment indica se o pedido foi pago com o saldo da carteira
+func isWalletPayment(purchase *models.Purchase) bool {
+	return purchase.PaymentProvider != nil && *purchase.PaymentProvider == payments.WalletProvider
+}
+
+// chargeDescription descreve a cobrança para o pagador
+func chargeDescription(purchase *models.Purchase) string {
+	return fmt.Sprintf("%d números - %s", purchase.Quantity, purchase.RewardName)
+}
+
+// refundID deriva o identificador da devolução no provedor (até 35 caracteres alfanuméricos, como exige o PIX)
+func refundID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
+
+// chargeTxID deriva o txid da cobrança a partir do ID do pedido (32 caracteres alfanuméricos, como exige o PIX)
+func chargeTxID(purchaseID uuid.UUID) string {
+	return strings.ReplaceAll(purchaseID.String(), "-", "")
+}
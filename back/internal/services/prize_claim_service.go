@@ -0,0 +1,243 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// PrizeClaimService implementa o resgate e a entrega dos prêmios sorteados
+type PrizeClaimService struct {
+	claimRepo           *repository.PrizeClaimRepository
+	rewardRepo          *repository.RewardRepository
+	notificationService *NotificationService
+	claimDeadline       time.Duration
+}
+
+// NewPrizeClaimService cria uma nova instância do serviço de resgates. claimDeadline é o prazo que cada ganhador
+// tem, a partir do sorteio, para confirmar os dados de entrega
+func NewPrizeClaimService(claimRepo *repository.PrizeClaimRepository, rewardRepo *repository.RewardRepository, notificationService *NotificationService, claimDeadline time.Duration) *PrizeClaimService {
+	return &PrizeClaimService{
+		claimRepo:           claimRepo,
+		rewardRepo:          rewardRepo,
+		notificationService: notificationService,
+		claimDeadline:       claimDeadline,
+	}
+}
+
+// Deadline retorna o fim do prazo de resgate de um prêmio sorteado em from
+func (s *PrizeClaimService) Deadline(from time.Time) time.Time {
+	return from.Add(s.claimDeadline)
+}
+
+// NotifyWinners avisa os ganhadores com resgate pendente de um prêmio recém-sorteado
+func (s *PrizeClaimService) NotifyWinners(rewardID uuid.UUID) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		log.Printf("Erro ao buscar prêmio %s para avisar os ganhadores: %v", rewardID, err)
+		return
+	}
+
+	claims, err := s.claimRepo.ListByReward(rewardID)
+	if err != nil {
+		log.Printf("Erro ao buscar resgates do prêmio %s: %v", rewardID, err)
+		return
+	}
+
+	for i := range claims {
+		if claims[i].Status == models.ClaimPending {
+			s.notifyWinner(reward, &claims[i])
+		}
+	}
+}
+
+// ListByReward lista os resgates de um prêmio, com os dados de entrega (organizador ou administrador)
+func (s *PrizeClaimService) ListByReward(rewardID, actorID uuid.UUID, isAdmin bool) (*models.PrizeClaimListResponse, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	claims, err := s.claimRepo.ListByReward(rewardID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PrizeClaimListResponse{Claims: claims}, nil
+}
+
+// ListMine lista os resgates dos prêmios ganhos pelo usuário
+func (s *PrizeClaimService) ListMine(userID uuid.UUID) (*models.PrizeClaimListResponse, error) {
+	claims, err := s.claimRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PrizeClaimListResponse{Claims: claims}, nil
+}
+
+// Get busca um resgate visível ao ganhador, ao organizador do prêmio ou a um administrador
+func (s *PrizeClaimService) Get(id, actorID uuid.UUID, isAdmin bool) (*models.PrizeClaim, error) {
+	claim, reward, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if claim.UserID != actorID && reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("resgate não encontrado")
+	}
+	return claim, nil
+}
+
+// Confirm registra os dados de entrega informados pelo ganhador dentro do prazo de resgate e avisa o organizador
+func (s *PrizeClaimService) Confirm(id, userID uuid.UUID, req *models.ConfirmClaimRequest) (*models.PrizeClaim, error) {
+	claim, reward, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if claim.UserID != userID {
+		return nil, errors.New("apenas o ganhador pode realizar esta operação")
+	}
+	if claim.Status != models.ClaimPending {
+		return nil, errors.New("dados de entrega do prêmio já confirmados")
+	}
+	if !time.Now().Before(claim.ClaimDeadline) {
+		return nil, errors.New("prazo de resgate do prêmio encerrado")
+	}
+
+	confirmed, err := s.claimRepo.Confirm(id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(reward.OwnerID, models.NotificationPrizeClaimed, "Prêmio resgatado",
+		fmt.Sprintf("O ganhador do número %d confirmou os dados de entrega de \"%s\" no prêmio \"%s\". Registre o envio ou a entrega em mãos.",
+			confirmed.Number, confirmed.PrizeName, reward.Name), confirmed)
+
+	return confirmed, nil
+}
+
+// Ship registra o envio ou a entrega em mãos de um resgate confirmado (organizador ou administrador) e avisa o ganhador
+func (s *PrizeClaimService) Ship(id, actorID uuid.UUID, isAdmin bool, req *models.ShipClaimRequest) (*models.PrizeClaim, error) {
+	claim, reward, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+	if claim.Status != models.ClaimConfirmed {
+		return nil, errors.New("o ganhador ainda não confirmou os dados de entrega")
+	}
+	if req.Method == models.DeliveryShipping && req.TrackingCode == "" {
+		return nil, errors.New("código de rastreio é obrigatório para envios")
+	}
+
+	shipped, err := s.claimRepo.Ship(id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("\"%s\" do prêmio \"%s\" foi entregue em mãos. Confirme o recebimento.", shipped.PrizeName, reward.Name)
+	if req.Method == models.DeliveryShipping {
+		message = fmt.Sprintf("\"%s\" do prêmio \"%s\" foi enviado (rastreio %s). Confirme o recebimento quando chegar.",
+			shipped.PrizeName, reward.Name, shipped.TrackingCode)
+	}
+	s.notify(shipped.UserID, models.NotificationPrizeShipped, "Prêmio enviado", message, shipped)
+
+	return shipped, nil
+}
+
+// Receive registra o recebimento confirmado pelo ganhador e avisa o organizador. Com todos os resgates recebidos,
+// o prêmio passa para delivered
+func (s *PrizeClaimService) Receive(id, userID uuid.UUID) (*models.PrizeClaim, error) {
+	claim, reward, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if claim.UserID != userID {
+		return nil, errors.New("apenas o ganhador pode realizar esta operação")
+	}
+	if claim.Status != models.ClaimShipped {
+		return nil, errors.New("o prêmio ainda não foi enviado")
+	}
+
+	received, delivered, err := s.claimRepo.Receive(id)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("O ganhador do número %d confirmou o recebimento de \"%s\" no prêmio \"%s\".",
+		received.Number, received.PrizeName, reward.Name)
+	if delivered {
+		message += " Todos os prêmios foram entregues."
+	}
+	s.notify(reward.OwnerID, models.NotificationPrizeReceived, "Prêmio recebido", message, received)
+
+	return received, nil
+}
+
+// Redraw sorteia um novo ganhador para um resgate com prazo vencido sem os dados de entrega (organizador ou
+// administrador). O ganhador anterior perde o prêmio e o novo recebe o mesmo prazo de resgate a partir de agora
+func (s *PrizeClaimService) Redraw(id, actorID uuid.UUID, isAdmin bool) (*models.RedrawClaimResponse, error) {
+	_, reward, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	forfeited, replacement, err := s.claimRepo.Redraw(id, s.Deadline(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(forfeited.UserID, models.NotificationPrizeForfeited, "Prazo de resgate encerrado",
+		fmt.Sprintf("O prazo para resgatar \"%s\" do prêmio \"%s\" com o número %d terminou e o prêmio foi sorteado novamente.",
+			forfeited.PrizeName, reward.Name, forfeited.Number), forfeited)
+	s.notifyWinner(reward, replacement)
+
+	return &models.RedrawClaimResponse{Forfeited: *forfeited, Claim: *replacement}, nil
+}
+
+// load busca um resgate e o prêmio a que pertence
+func (s *PrizeClaimService) load(id uuid.UUID) (*models.PrizeClaim, *models.Reward, error) {
+	claim, err := s.claimRepo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reward, err := s.rewardRepo.GetByID(claim.RewardID)
+	if err != nil {
+		return nil, nil, errors.New("prêmio não encontrado")
+	}
+	return claim, reward, nil
+}
+
+// notifyWinner avisa o ganhador de um resgate pendente do prazo para confirmar os dados de entrega
+func (s *PrizeClaimService) notifyWinner(reward *models.Reward, claim *models.PrizeClaim) {
+	s.notify(claim.UserID, models.NotificationPrizeWon, "Você ganhou!",
+		fmt.Sprintf("Seu número %d ganhou \"%s\" no prêmio \"%s\". Confirme os dados de entrega até %s.",
+			claim.Number, claim.PrizeName, reward.Name, claim.ClaimDeadline.Local().Format("02/01/2006 15:04")), claim)
+}
+
+// notify registra um aviso sobre um resgate; falhas ficam apenas no log
+func (s *PrizeClaimService) notify(userID uuid.UUID, notificationType, title, message string, claim *models.PrizeClaim) {
+	rewardID := claim.RewardID
+	err := s.notificationService.Notify(&models.Notification{
+		UserID:   userID,
+		Type:     notificationType,
+		Title:    title,
+		Message:  message,
+		RewardID: &rewardID,
+	})
+	if err != nil {
+		log.Printf("Erro ao avisar o usuário %s sobre o resgate %s: %v", userID, claim.ID, err)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+)
+
+// checkPurchaseRules confere um pedido contra as regras de compra do prêmio, considerando os números que o usuário já possui.
+// Retorna todas as regras não atendidas de uma vez para que a interface possa explicá-las
+func checkPurchaseRules(rules *models.PurchaseRules, quantity, owned int) error {
+	violations := []models.PurchaseRuleViolation{}
+
+	if quantity < rules.MinPerOrder {
+		violations = append(violations, models.PurchaseRuleViolation{
+			Rule:    models.RuleMinPerOrder,
+			Limit:   rules.MinPerOrder,
+			Message: fmt.Sprintf("o pedido deve ter no mínimo %d números", rules.MinPerOrder),
+		})
+	}
+
+	if rules.MaxPerOrder > 0 && quantity > rules.MaxPerOrder {
+		violations = append(violations, models.PurchaseRuleViolation{
+			Rule:    models.RuleMaxPerOrder,
+			Limit:   rules.MaxPerOrder,
+			Message: fmt.Sprintf("o pedido deve ter no máximo %d números", rules.MaxPerOrder),
+		})
+	}
+
+	if rules.QuantityStep > 1 && quantity%rules.QuantityStep != 0 {
+		violations = append(violations, models.PurchaseRuleViolation{
+			Rule:    models.RuleQuantityStep,
+			Limit:   rules.QuantityStep,
+			Message: fmt.Sprintf("a quantidade deve ser múltipla de %d", rules.QuantityStep),
+		})
+	}
+
+	if rules.MaxPerUser > 0 && owned+quantity > rules.MaxPerUser {
+		violations = append(violations, models.MaxPerUserViolation(rules.MaxPerUser, owned))
+	}
+
+	if len(violations) > 0 {
+		return &models.PurchaseRulesError{Violations: violations}
+	}
+
+	return nil
+}
+
+// validatePurchaseRuleOptions garante que as regras configuradas no prêmio são coerentes entre si
+func validatePurchaseRuleOptions(minQuota *int, options models.RewardOptions) error {
+	if minQuota != nil && options.MaxPerOrder != nil && *options.MaxPerOrder < *minQuota {
+		return fmt.Errorf("máximo por pedido (%d) não pode ser menor que o mínimo por pedido (%d)", *options.MaxPerOrder, *minQuota)
+	}
+	if options.MaxPerOrder != nil && options.MaxPerUser != nil && *options.MaxPerUser < *options.MaxPerOrder {
+		return fmt.Errorf("máximo por usuário (%d) não pode ser menor que o máximo por pedido (%d)", *options.MaxPerUser, *options.MaxPerOrder)
+	}
+	if options.QuantityStep != nil && options.MaxPerOrder != nil && *options.QuantityStep > *options.MaxPerOrder {
+		return fmt.Errorf("múltiplo de compra (%d) não pode ser maior que o máximo por pedido (%d)", *options.QuantityStep, *options.MaxPerOrder)
+	}
+	if options.MinSoldNumbers != nil && options.TotalNumbers != nil && *options.MinSoldNumbers > *options.TotalNumbers {
+		return fmt.Errorf("mínimo de números vendidos (%d) não pode ser maior que o total de números (%d)", *options.MinSoldNumbers, *options.TotalNumbers)
+	}
+	return nil
+}
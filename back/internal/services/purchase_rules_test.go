@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+)
+
+func TestCheckPurchaseRules(t *testing.T) {
+	rules := &models.PurchaseRules{MinPerOrder: 2, MaxPerOrder: 20, MaxPerUser: 30, QuantityStep: 2}
+
+	tests := []struct {
+		name      string
+		rules     *models.PurchaseRules
+		quantity  int
+		owned     int
+		wantRules []string
+	}{
+		{name: "dentro das regras", rules: rules, quantity: 10, owned: 10},
+		{name: "completa o limite por usuário", rules: rules, quantity: 20, owned: 10},
+		{name: "abaixo do mínimo", rules: rules, quantity: 0, wantRules: []string{models.RuleMinPerOrder}},
+		{name: "acima do máximo por pedido", rules: rules, quantity: 22, wantRules: []string{models.RuleMaxPerOrder}},
+		{name: "fora do múltiplo", rules: rules, quantity: 7, wantRules: []string{models.RuleQuantityStep}},
+		{name: "ultrapassa o limite por usuário", rules: rules, quantity: 12, owned: 20, wantRules: []string{models.RuleMaxPerUser}},
+		{
+			name:      "todas as violações de uma vez",
+			rules:     rules,
+			quantity:  21,
+			owned:     25,
+			wantRules: []string{models.RuleMaxPerOrder, models.RuleQuantityStep, models.RuleMaxPerUser},
+		},
+		{name: "limites zerados não se aplicam", rules: &models.PurchaseRules{MinPerOrder: 1}, quantity: 1000, owned: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPurchaseRules(tt.rules, tt.quantity, tt.owned)
+			if len(tt.wantRules) == 0 {
+				if err != nil {
+					t.Fatalf("checkPurchaseRules() erro inesperado: %v", err)
+				}
+				return
+			}
+
+			var rulesErr *models.PurchaseRulesError
+			if !errors.As(err, &rulesErr) {
+				t.Fatalf("checkPurchaseRules() erro = %v, esperado PurchaseRulesError", err)
+			}
+			if len(rulesErr.Violations) != len(tt.wantRules) {
+				t.Fatalf("checkPurchaseRules() = %+v, esperado as regras %v", rulesErr.Violations, tt.wantRules)
+			}
+			for i, violation := range rulesErr.Violations {
+				if violation.Rule != tt.wantRules[i] {
+					t.Errorf("violação %d = %s, esperado %s", i, violation.Rule, tt.wantRules[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/money"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Códigos de indicação usam letras maiúsculas e dígitos sem os caracteres que se confundem (0, O, 1, I)
+const (
+	referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referralCodeLength   = 8
+	referralCodeAttempts = 5
+)
+
+// ReferralService implementa a lógica de negócio do programa de indicação
+type ReferralService struct {
+	referralRepo *repository.ReferralRepository
+	terms        models.ReferralTerms
+}
+
+// NewReferralService cria uma nova instância do serviço de indicações com o bônus dado a quem indica
+func NewReferralService(referralRepo *repository.ReferralRepository, terms models.ReferralTerms) (*ReferralService, error) {
+	switch terms.RewardType {
+	case models.ReferralBonusNumbers:
+		if terms.BonusNumbers <= 0 {
+			return nil, errors.New("quantidade de números bônus da indicação deve ser maior que zero")
+		}
+	case models.ReferralWalletCredit:
+		if !terms.CreditAmount.IsPositive() {
+			return nil, errors.New("crédito da indicação deve ser maior que zero")
+		}
+	default:
+		return nil, fmt.Errorf("tipo de bônus de indicação desconhecido: %s", terms.RewardType)
+	}
+	if terms.CreditAmount.IsNegative() {
+		return nil, errors.New("crédito da indicação não pode ser negativo")
+	}
+
+	return &ReferralService{referralRepo: referralRepo, terms: terms}, nil
+}
+
+// GetCode busca o código de indicação de um usuário, gerando-o no primeiro acesso
+func (s *ReferralService) GetCode(userID uuid.UUID) (*models.ReferralCodeResponse, error) {
+	code, err := s.ensureCode(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReferralCodeResponse{UserID: userID, Code: code}, nil
+}
+
+// GetStats lista as indicações de um usuário com o total de bônus recebidos
+func (s *ReferralService) GetStats(userID uuid.UUID) (*models.ReferralStatsResponse, error) {
+	code, err := s.ensureCode(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	referrals, err := s.referralRepo.ListByReferrer(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.ReferralStatsResponse{
+		Code:          code,
+		Total:         len(referrals),
+		AwardedAmount: money.New(0, s.terms.CreditAmount.Currency()),
+		Referrals:     referrals,
+	}
+	for _, referral := range referrals {
+		switch referral.Status {
+		case models.ReferralPending:
+			stats.Pending++
+			continue
+		case models.ReferralRevoked:
+			stats.Revoked++
+			continue
+		}
+		stats.Rewarded++
+		stats.AwardedNumbers += referral.AwardedNumbers
+		if stats.AwardedAmount, err = stats.AwardedAmount.Add(referral.AwardedAmount); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// ResolveReferrer confere o código de indicação informado no cadastro do e-mail email e retorna quem indicou.
+// Códigos de contas na mesma caixa de e-mail do novo usuário são recusados como autoindicação
+func (s *ReferralService) ResolveReferrer(code, email string) (uuid.UUID, error) {
+	referrer, err := s.referralRepo.GetReferrerByCode(strings.TrimSpace(code))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if mailbox(referrer.Email) == mailbox(email) {
+		return uuid.Nil, errors.New("não é possível usar o próprio código de indicação")
+	}
+
+	return referrer.ID, nil
+}
+
+// Record registra a indicação de um usuário recém-cadastrado com o bônus vigente
+func (s *ReferralService) Record(referrerID, referredID uuid.UUID) error {
+	if referrerID == referredID {
+		return errors.New("não é possível usar o próprio código de indicação")
+	}
+
+	return s.referralRepo.Create(referrerID, referredID, s.terms)
+}
+
+// ensureCode retorna o código de indicação do usuário, gerando um novo se ainda não existir
+func (s *ReferralService) ensureCode(userID uuid.UUID) (string, error) {
+	code, err := s.referralRepo.GetCode(userID)
+	if err != nil || code != "" {
+		return code, err
+	}
+
+	for attempt := 0; attempt < referralCodeAttempts; attempt++ {
+		candidate, err := newReferralCode()
+		if err != nil {
+			return "", err
+		}
+
+		code, err = s.referralRepo.SetCode(userID, candidate)
+		if err == nil {
+			return code, nil
+		}
+		if err.Error() != "código de indicação já está em uso" {
+			return "", err
+		}
+	}
+
+	return "", errors.New("não foi possível gerar o código de indicação")
+}
+
+// newReferralCode sorteia um código de indicação
+func newReferralCode() (string, error) {
+	max := big.NewInt(int64(len(referralCodeAlphabet)))
+	code := make([]byte, referralCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("erro ao gerar código de indicação: %w", err)
+		}
+		code[i] = referralCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// mailbox normaliza um e-mail para a caixa que o recebe: sem diferenciar maiúsculas, sem o sufixo "+tag"
+// e, no Gmail, sem os pontos do nome, que são ignorados pelo provedor
+func mailbox(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// ReservationSweeper expira periodicamente os pedidos não pagos e libera os números reservados
+type ReservationSweeper struct {
+	rewardService *RewardService
+	interval      time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReservationSweeper cria uma nova instância do limpador de reservas
+func NewReservationSweeper(rewardService *RewardService, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{
+		rewardService: rewardService,
+		interval:      interval,
+	}
+}
+
+// Start inicia o limpador de reservas em segundo plano
+func (s *ReservationSweeper) Start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.RunOnce()
+		for {
+			select {
+			case <-ticker.C:
+				s.RunOnce()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	log.Printf("Limpador de reservas iniciado (intervalo: %s)", s.interval)
+}
+
+// Stop interrompe o limpador e aguarda a execução em andamento terminar
+func (s *ReservationSweeper) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+}
+
+// RunOnce libera os números dos pedidos com reserva vencida e refaz as devoluções que falharam no cancelamento de
+// prêmios. Pode rodar em várias instâncias ao mesmo tempo, pois cada pedido só é expirado ou devolvido uma vez
+func (s *ReservationSweeper) RunOnce() {
+	released, err := s.rewardService.ExpirePurchases()
+	if err != nil {
+		log.Printf("Erro ao liberar reservas vencidas: %v", err)
+	} else if released > 0 {
+		log.Printf("%d números de reservas vencidas devolvidos aos prêmios", released)
+	}
+
+	refunded, err := s.rewardService.RetryCancelledRewardRefunds()
+	if err != nil {
+		log.Printf("Erro ao refazer devoluções de prêmios cancelados: %v", err)
+	} else if refunded > 0 {
+		log.Printf("%d pedido(s) de prêmios cancelados devolvido(s) em nova tentativa", refunded)
+	}
+}
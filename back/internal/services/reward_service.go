@@ -1,38 +1,83 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/cauamistura/BNUPremios/internal/draw"
 	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/pix"
 	"github.com/cauamistura/BNUPremios/internal/repository"
 	"github.com/google/uuid"
 )
 
+// refundRetryBatchSize limita quantas devoluções de prêmios cancelados são refeitas a cada execução do limpador
+const refundRetryBatchSize = 50
+
 type RewardService struct {
-	rewardRepo *repository.RewardRepository
+	rewardRepo          *repository.RewardRepository
+	purchaseRepo        *repository.PurchaseRepository
+	paymentService      *PaymentService
+	settlementService   *SettlementService
+	notificationService *NotificationService
+	claimService        *PrizeClaimService
+	reservationTTL      time.Duration
+	maxPostponements    int
 }
 
-func NewRewardService(rewardRepo *repository.RewardRepository) *RewardService {
-	return &RewardService{rewardRepo: rewardRepo}
+func NewRewardService(rewardRepo *repository.RewardRepository, purchaseRepo *repository.PurchaseRepository, paymentService *PaymentService, settlementService *SettlementService, notificationService *NotificationService, claimService *PrizeClaimService, reservationTTL time.Duration, maxPostponements int) *RewardService {
+	return &RewardService{
+		rewardRepo:          rewardRepo,
+		purchaseRepo:        purchaseRepo,
+		paymentService:      paymentService,
+		settlementService:   settlementService,
+		notificationService: notificationService,
+		claimService:        claimService,
+		reservationTTL:      reservationTTL,
+		maxPostponements:    maxPostponements,
+	}
 }
 
-// Create cria um novo prêmio
+// Create cria um novo prêmio, como rascunho se nenhuma situação inicial for informada
 func (s *RewardService) Create(req *models.CreateRewardRequest, ownerID uuid.UUID) (*models.RewardResponse, error) {
+	// Gerar a semente secreta do sorteio e publicar apenas o seu hash
+	seed, err := draw.GenerateSeed()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar semente do sorteio: %w", err)
+	}
+	seedHash := draw.HashSeed(seed)
+
 	reward := &models.Reward{
-		ID:          uuid.New(),
-		OwnerID:     ownerID,
-		Name:        req.Name,
-		Description: req.Description,
-		Image:       req.Image,
-		DrawDate:    req.DrawDate,
-		Completed:   false,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-
-	if err := s.rewardRepo.Create(reward, req.Price, req.MinQuota, req.Images); err != nil {
+		ID:           uuid.New(),
+		OwnerID:      ownerID,
+		Name:         req.Name,
+		Description:  req.Description,
+		Image:        req.Image,
+		DrawDate:     req.DrawDate,
+		Status:       models.RewardDraft,
+		DrawSeed:     &seed,
+		DrawSeedHash: &seedHash,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if req.Status != "" {
+		reward.Status = req.Status
+	}
+	reward.StatusChangedAt = reward.CreatedAt
+
+	if err := validatePrizes(req.Prizes); err != nil {
+		return nil, &RewardValidationError{Err: err}
+	}
+
+	if err := validatePurchaseRuleOptions(&req.MinQuota, req.RewardOptions); err != nil {
+		return nil, &RewardValidationError{Err: err}
+	}
+
+	if err := s.rewardRepo.Create(reward, req.Price, req.MinQuota, req.Images, req.Prizes, req.RewardOptions); err != nil {
 		return nil, fmt.Errorf("erro ao criar prêmio: %w", err)
 	}
 
@@ -69,8 +114,9 @@ func (s *RewardService) GetDetailsByIDWithoutBuyers(id uuid.UUID) (*models.Rewar
 	return s.ToRewardDetailsWithoutBuyersResponse(rewardDetails), nil
 }
 
-// List busca todos os prêmios com paginação
-func (s *RewardService) List(page, limit int, search string) (*models.RewardListResponse, error) {
+// List busca os prêmios visíveis ao público com paginação, opcionalmente filtrados por situação.
+// Rascunhos só aparecem na listagem do próprio organizador
+func (s *RewardService) List(page, limit int, search, status string) (*models.RewardListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -81,7 +127,15 @@ func (s *RewardService) List(page, limit int, search string) (*models.RewardList
 		limit = 100
 	}
 
-	rewards, total, err := s.rewardRepo.List(page, limit, search)
+	statuses := []string{models.RewardPublished, models.RewardSalesClosed, models.RewardDrawn, models.RewardDelivered}
+	if status != "" {
+		if !models.IsValidRewardStatus(status) || status == models.RewardDraft {
+			return nil, errors.New("situação do prêmio inválida")
+		}
+		statuses = []string{status}
+	}
+
+	rewards, total, err := s.rewardRepo.List(page, limit, search, statuses)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao listar prêmios: %w", err)
 	}
@@ -112,35 +166,178 @@ func (s *RewardService) List(page, limit int, search string) (*models.RewardList
 	}, nil
 }
 
-// Draw realiza o sorteio de um prêmio
+// ManualDraw realiza o sorteio pedido pelo organizador ou por um administrador. O sorteio manual só acontece
+// depois do encerramento das vendas e com a data do sorteio atingida
+func (s *RewardService) ManualDraw(rewardID, actorID uuid.UUID, isAdmin bool) (*models.DrawRewardResponse, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+	if time.Now().Before(reward.DrawDate) {
+		return nil, errors.New("a data do sorteio ainda não chegou")
+	}
+
+	return s.Draw(rewardID)
+}
+
+// Draw realiza o sorteio de um prêmio. Prêmios que chegam à data do sorteio sem o mínimo de números vendidos
+// são cancelados, com os pedidos pagos devolvidos, e retornam *models.MinimumSalesError com Cancelled
 func (s *RewardService) Draw(rewardID uuid.UUID) (*models.DrawRewardResponse, error) {
 	// Verificar se o prêmio existe
-	_, err := s.rewardRepo.GetByID(rewardID)
+	reward, err := s.rewardRepo.GetByID(rewardID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Realizar o sorteio
-	result, err := s.rewardRepo.DrawReward(rewardID)
+	result, err := s.rewardRepo.DrawReward(rewardID, s.claimService.Deadline(time.Now()))
 	if err != nil {
+		var belowMinimum *models.MinimumSalesError
+		if errors.As(err, &belowMinimum) && !time.Now().Before(reward.DrawDate) {
+			return nil, s.cancelBelowMinimum(reward, belowMinimum)
+		}
+		if err.Error() == "nenhum número foi comprado para este prêmio" {
+			return nil, err
+		}
 		return nil, fmt.Errorf("erro ao realizar sorteio: %w", err)
 	}
 
+	// Apurar o repasse ao organizador; se falhar, é apurado na próxima consulta do relatório de repasses
+	if err := s.settlementService.CreateForReward(rewardID); err != nil {
+		log.Printf("Erro ao apurar repasse do prêmio %s: %v", rewardID, err)
+	}
+
+	s.claimService.NotifyWinners(rewardID)
+
 	return result, nil
 }
 
-// Update atualiza um prêmio
-func (s *RewardService) Update(id uuid.UUID, req *models.UpdateRewardRequest) (*models.RewardResponse, error) {
+// cancelBelowMinimum cancela um prêmio que chegou à data do sorteio sem o mínimo de vendas e avisa o organizador
+func (s *RewardService) cancelBelowMinimum(reward *models.Reward, belowMinimum *models.MinimumSalesError) error {
+	reason := fmt.Sprintf("mínimo de %d números vendidos não atingido", belowMinimum.Minimum)
+	result, err := s.cancelReward(reward, nil, reason)
+	if err != nil {
+		return fmt.Errorf("erro ao cancelar prêmio abaixo do mínimo de vendas: %w", err)
+	}
+	belowMinimum.Cancelled = true
+
+	message := fmt.Sprintf("O prêmio \"%s\" foi cancelado automaticamente na data do sorteio por não atingir o mínimo de %d números vendidos (%d vendidos). %d pedido(s) pago(s) cancelado(s) e devolvido(s).",
+		reward.Name, belowMinimum.Minimum, belowMinimum.Sold, len(result.Purchases))
+	if len(result.Failures) > 0 {
+		message += fmt.Sprintf(" %d pedido(s) não puderam ser devolvidos agora e serão devolvidos automaticamente em nova tentativa.", len(result.Failures))
+	}
+	rewardID := reward.ID
+	err = s.notificationService.Notify(&models.Notification{
+		UserID:   reward.OwnerID,
+		Type:     models.NotificationRewardCancelled,
+		Title:    "Prêmio cancelado por vendas abaixo do mínimo",
+		Message:  message,
+		RewardID: &rewardID,
+	})
+	if err != nil {
+		log.Printf("Erro ao avisar o organizador sobre o cancelamento do prêmio %s: %v", reward.ID, err)
+	}
+
+	return belowMinimum
+}
+
+// CancelUnsold cancela um prêmio que chegou à data do sorteio sem nenhum número vendido, avisando o organizador e
+// os compradores de pedidos pendentes. Usado pelo sorteio automático, já que o prêmio não tem como ser sorteado
+func (s *RewardService) CancelUnsold(rewardID uuid.UUID) error {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.cancelReward(reward, nil, "nenhum número vendido até a data do sorteio"); err != nil {
+		return fmt.Errorf("erro ao cancelar prêmio sem vendas: %w", err)
+	}
+
+	err = s.notificationService.Notify(&models.Notification{
+		UserID:   reward.OwnerID,
+		Type:     models.NotificationRewardCancelled,
+		Title:    "Prêmio cancelado sem vendas",
+		Message:  fmt.Sprintf("O prêmio \"%s\" foi cancelado automaticamente na data do sorteio porque nenhum número foi vendido.", reward.Name),
+		RewardID: &rewardID,
+	})
+	if err != nil {
+		log.Printf("Erro ao avisar o organizador sobre o cancelamento do prêmio %s: %v", reward.ID, err)
+	}
+
+	return nil
+}
+
+// GetDrawProof busca a prova pública do sorteio de um prêmio
+func (s *RewardService) GetDrawProof(rewardID uuid.UUID) (*models.DrawProofResponse, error) {
+	proof, err := s.rewardRepo.GetDrawProof(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+
+	return proof, nil
+}
+
+// RewardValidationError indica dados de um prêmio que não passam nas regras de cadastro; a mensagem é a da regra
+type RewardValidationError struct {
+	Err error
+}
+
+func (e *RewardValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RewardValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Update atualiza um prêmio do organizador ou, para administradores, de qualquer prêmio
+func (s *RewardService) Update(id, actorID uuid.UUID, isAdmin bool, req *models.UpdateRewardRequest) (*models.RewardResponse, error) {
 	// Verificar se o prêmio existe
 	reward, err := s.rewardRepo.GetByID(id)
 	if err != nil {
-		return nil, err
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
 	}
 
 	// Verificar se o prêmio já foi sorteado
-	if reward.WinnerNumber != nil {
+	if reward.IsDrawn() {
 		return nil, errors.New("não é possível editar um prêmio que já foi sorteado")
 	}
+	if reward.Status == models.RewardCancelled {
+		return nil, errors.New("não é possível editar um prêmio cancelado")
+	}
+
+	// Depois de publicado, o sorteio só muda de data pelo adiamento, que exige motivo e avisa os compradores
+	if req.DrawDate != nil && !req.DrawDate.Equal(reward.DrawDate) && reward.Status != models.RewardDraft {
+		return nil, errors.New("use o adiamento do sorteio para alterar a data de um prêmio publicado")
+	}
+
+	if err := validatePrizes(req.Prizes); err != nil {
+		return nil, &RewardValidationError{Err: err}
+	}
+
+	if err := validatePurchaseRuleOptions(req.MinQuota, req.RewardOptions); err != nil {
+		return nil, &RewardValidationError{Err: err}
+	}
+
+	// O conjunto de números não pode encolher abaixo de um número já vendido
+	if req.TotalNumbers != nil || req.MinSoldNumbers != nil {
+		total, _, highest, err := s.rewardRepo.GetNumberStats(id)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar números vendidos: %w", err)
+		}
+		if req.TotalNumbers != nil && *req.TotalNumbers < highest {
+			return nil, &RewardValidationError{Err: errors.New("total de números não pode ser menor que o maior número já vendido")}
+		}
+		if req.TotalNumbers == nil && req.MinSoldNumbers != nil && *req.MinSoldNumbers > total {
+			return nil, &RewardValidationError{Err: fmt.Errorf("mínimo de números vendidos (%d) não pode ser maior que o total de números (%d)", *req.MinSoldNumbers, total)}
+		}
+	}
 
 	// Construir map de atualizações
 	updates := make(map[string]interface{})
@@ -156,9 +353,6 @@ func (s *RewardService) Update(id uuid.UUID, req *models.UpdateRewardRequest) (*
 	if req.DrawDate != nil {
 		updates["draw_date"] = *req.DrawDate
 	}
-	if req.Completed != nil {
-		updates["completed"] = *req.Completed
-	}
 
 	// Atualizar no banco se houver mudanças
 	if len(updates) > 0 {
@@ -167,9 +361,9 @@ func (s *RewardService) Update(id uuid.UUID, req *models.UpdateRewardRequest) (*
 		}
 	}
 
-	// Atualizar detalhes do prêmio (price, min_quota, images) se fornecidos
-	if req.Price != nil || req.MinQuota != nil || len(req.Images) > 0 {
-		if err := s.rewardRepo.UpdateDetails(id, req.Price, req.MinQuota, req.Images); err != nil {
+	// Atualizar detalhes do prêmio (price, min_quota, images, prizes e configurações opcionais)
+	if req.Price != nil || req.MinQuota != nil || len(req.Images) > 0 || len(req.Prizes) > 0 || req.RewardOptions != (models.RewardOptions{}) {
+		if err := s.rewardRepo.UpdateDetails(id, req.Price, req.MinQuota, req.Images, req.Prizes, req.RewardOptions); err != nil {
 			return nil, fmt.Errorf("erro ao atualizar detalhes do prêmio: %w", err)
 		}
 	}
@@ -183,50 +377,335 @@ func (s *RewardService) Update(id uuid.UUID, req *models.UpdateRewardRequest) (*
 	return s.toRewardResponse(updatedReward), nil
 }
 
-// Delete remove um prêmio
-func (s *RewardService) Delete(id uuid.UUID) error {
+// Delete remove um prêmio do organizador ou, para administradores, de qualquer prêmio
+func (s *RewardService) Delete(id, actorID uuid.UUID, isAdmin bool) error {
 	// Verificar se o prêmio existe
 	reward, err := s.rewardRepo.GetByID(id)
 	if err != nil {
-		return err
+		return errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return errors.New("apenas o organizador do prêmio pode realizar esta operação")
 	}
 
 	// Verificar se o prêmio já foi sorteado
-	if reward.WinnerNumber != nil {
+	if reward.IsDrawn() {
 		return errors.New("não é possível deletar um prêmio que já foi sorteado")
 	}
 
 	if err := s.rewardRepo.Delete(id); err != nil {
+		if strings.HasPrefix(err.Error(), "não é possível deletar") || err.Error() == "prêmio não encontrado" {
+			return err
+		}
 		return fmt.Errorf("erro ao deletar prêmio: %w", err)
 	}
 
 	return nil
 }
 
-// AddBuyer adiciona um comprador a um prêmio
-func (s *RewardService) AddBuyer(rewardID, userID uuid.UUID, number int) error {
-	// Verificar se o prêmio existe
-	_, err := s.rewardRepo.GetByID(rewardID)
+// RemoveBuyer cancela todos os pedidos pendentes ou pagos de um comprador em um prêmio, devolvendo os valores pagos.
+// Pode ser feito pelo próprio comprador ou por um administrador
+func (s *RewardService) RemoveBuyer(rewardID, buyerID, actorID uuid.UUID, isAdmin bool, reason string) ([]models.CancelPurchaseResponse, error) {
+	if actorID != buyerID && !isAdmin {
+		return nil, errors.New("apenas o comprador ou um administrador pode cancelar a compra")
+	}
+
+	purchases, err := s.purchaseRepo.ListActiveByRewardAndUser(rewardID, buyerID)
 	if err != nil {
-		return errors.New("prêmio não encontrado")
+		return nil, fmt.Errorf("erro ao buscar compras: %w", err)
+	}
+	if len(purchases) == 0 {
+		return nil, errors.New("comprador não possui compras neste prêmio")
 	}
 
-	if err := s.rewardRepo.AddBuyer(rewardID, userID, number); err != nil {
-		return fmt.Errorf("erro ao adicionar comprador: %w", err)
+	cancelled := []models.CancelPurchaseResponse{}
+	for i := range purchases {
+		result, err := s.cancelPurchase(&purchases[i], &actorID, reason)
+		if err != nil {
+			return cancelled, err
+		}
+		cancelled = append(cancelled, *result)
 	}
 
-	return nil
+	return cancelled, nil
 }
 
-// RemoveBuyer remove um comprador de um prêmio
-func (s *RewardService) RemoveBuyer(rewardID, userID uuid.UUID) error {
-	if err := s.rewardRepo.RemoveBuyer(rewardID, userID); err != nil {
-		return fmt.Errorf("erro ao remover comprador: %w", err)
+// Publish coloca um rascunho à venda (organizador ou administrador)
+func (s *RewardService) Publish(rewardID, actorID uuid.UUID, isAdmin bool) (*models.RewardResponse, error) {
+	if err := s.checkSalesOpen(rewardID); err != nil {
+		return nil, err
+	}
+
+	return s.transition(rewardID, actorID, isAdmin, models.RewardPublished)
+}
+
+// CloseSales encerra as vendas de um prêmio publicado. Reservas feitas antes ainda podem ser pagas
+func (s *RewardService) CloseSales(rewardID, actorID uuid.UUID, isAdmin bool) (*models.RewardResponse, error) {
+	return s.transition(rewardID, actorID, isAdmin, models.RewardSalesClosed)
+}
+
+// ReopenSales volta a vender números de um prêmio com vendas encerradas
+func (s *RewardService) ReopenSales(rewardID, actorID uuid.UUID, isAdmin bool) (*models.RewardResponse, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.Status != models.RewardSalesClosed {
+		return nil, &models.RewardStatusError{From: reward.Status, To: models.RewardPublished}
+	}
+	if err := s.checkSalesOpen(rewardID); err != nil {
+		return nil, err
 	}
 
+	return s.transition(rewardID, actorID, isAdmin, models.RewardPublished)
+}
+
+// MarkDelivered registra a entrega do prêmio sorteado aos ganhadores, depois que todos os resgates foram recebidos
+// ou perdidos
+func (s *RewardService) MarkDelivered(rewardID, actorID uuid.UUID, isAdmin bool) (*models.RewardResponse, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	updated, err := s.rewardRepo.MarkDelivered(rewardID)
+	if err != nil {
+		var statusErr *models.RewardStatusError
+		if errors.As(err, &statusErr) {
+			return nil, err
+		}
+		switch err.Error() {
+		case "prêmio não encontrado", "há resgates do prêmio ainda não recebidos pelos ganhadores":
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao registrar entrega do prêmio: %w", err)
+	}
+
+	return s.toRewardResponse(updated), nil
+}
+
+// Postpone adia o sorteio de um prêmio publicado (organizador ou administrador) até o limite de adiamentos
+// configurado, registra o motivo no histórico e avisa todos os compradores da nova data
+func (s *RewardService) Postpone(rewardID, actorID uuid.UUID, isAdmin bool, req *models.PostponeDrawRequest) (*models.PostponeDrawResponse, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+	if !req.DrawDate.After(time.Now()) {
+		return nil, errors.New("a nova data do sorteio deve estar no futuro")
+	}
+
+	change, postponements, err := s.rewardRepo.PostponeDraw(rewardID, req.DrawDate, req.Reason, &actorID, s.maxPostponements)
+	if err != nil {
+		switch err.Error() {
+		case "prêmio não encontrado",
+			"a data do sorteio de um rascunho é alterada pela edição do prêmio",
+			"não é possível adiar o sorteio de um prêmio cancelado",
+			"não é possível adiar o sorteio de um prêmio já sorteado",
+			"a nova data do sorteio deve ser posterior à data atual",
+			"limite de adiamentos do sorteio atingido":
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao adiar sorteio: %w", err)
+	}
+
+	updated, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PostponeDrawResponse{
+		Reward:           *s.toRewardResponse(updated),
+		Change:           *change,
+		Postponements:    postponements,
+		MaxPostponements: s.maxPostponements,
+	}
+
+	// O adiamento já foi registrado; falhas ao avisar um comprador ficam apenas no log
+	buyerIDs, err := s.purchaseRepo.ListBuyerIDs(rewardID)
+	if err != nil {
+		log.Printf("Erro ao buscar compradores do prêmio adiado %s: %v", rewardID, err)
+		return response, nil
+	}
+	message := fmt.Sprintf("O sorteio do prêmio \"%s\" foi adiado de %s para %s. Motivo: %s",
+		updated.Name, change.PreviousDate.Local().Format("02/01/2006 15:04"), change.NewDate.Local().Format("02/01/2006 15:04"), change.Reason)
+	for _, buyerID := range buyerIDs {
+		err := s.notificationService.Notify(&models.Notification{
+			UserID:   buyerID,
+			Type:     models.NotificationDrawPostponed,
+			Title:    "Sorteio adiado",
+			Message:  message,
+			RewardID: &updated.ID,
+		})
+		if err != nil {
+			log.Printf("Erro ao avisar o comprador %s sobre o adiamento do prêmio %s: %v", buyerID, rewardID, err)
+			continue
+		}
+		response.NotifiedBuyers++
+	}
+
+	return response, nil
+}
+
+// Cancel cancela um prêmio ainda não sorteado (organizador ou administrador). Os pedidos pendentes são cancelados
+// e os pagos são cancelados um a um com devolução; os que falharem são listados para nova tentativa individual
+func (s *RewardService) Cancel(rewardID, actorID uuid.UUID, isAdmin bool, reason string) (*models.CancelRewardResponse, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	return s.cancelReward(reward, &actorID, reason)
+}
+
+// cancelReward cancela o prêmio, cancela e devolve seus pedidos pagos e avisa os compradores, inclusive os de
+// pedidos pendentes. As devoluções que falharem são refeitas por RetryCancelledRewardRefunds.
+// actorID é nil nos cancelamentos automáticos
+func (s *RewardService) cancelReward(reward *models.Reward, actorID *uuid.UUID, reason string) (*models.CancelRewardResponse, error) {
+	cancelled, pending, err := s.rewardRepo.CancelReward(reward.ID)
+	if err != nil {
+		var statusErr *models.RewardStatusError
+		if errors.As(err, &statusErr) || err.Error() == "prêmio não encontrado" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao cancelar prêmio: %w", err)
+	}
+
+	response := &models.CancelRewardResponse{
+		Reward:    *s.toRewardResponse(cancelled),
+		Purchases: []models.CancelPurchaseResponse{},
+	}
+
+	purchases, err := s.purchaseRepo.ListPaidByReward(reward.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar compras do prêmio: %w", err)
+	}
+	if reason == "" {
+		reason = "prêmio cancelado"
+	}
+	for i := range pending {
+		s.notifyCancelledPurchase(reward, &pending[i], nil, reason)
+	}
+	for i := range purchases {
+		result, err := s.cancelPurchase(&purchases[i], actorID, reason)
+		if err != nil {
+			log.Printf("Erro ao cancelar compra %s do prêmio cancelado %s: %v", purchases[i].ID, reward.ID, err)
+			response.Failures = append(response.Failures, purchases[i].ID.String())
+			s.notifyCancelledPurchase(reward, &purchases[i], nil, reason)
+			continue
+		}
+		response.Purchases = append(response.Purchases, *result)
+		s.notifyCancelledPurchase(reward, &purchases[i], result.Refund, reason)
+	}
+
+	return response, nil
+}
+
+// notifyCancelledPurchase avisa o comprador de que o pedido foi cancelado junto com o prêmio e como o valor será devolvido.
+// Sem refund, o pedido estava pendente ou a devolução falhou e será refeita automaticamente
+func (s *RewardService) notifyCancelledPurchase(reward *models.Reward, purchase *models.Purchase, refund *models.PurchaseRefund, reason string) {
+	message := fmt.Sprintf("O prêmio \"%s\" foi cancelado (%s). ", reward.Name, reason)
+	switch {
+	case purchase.PaymentStatus == models.PaymentCancelled:
+		message += fmt.Sprintf("Seu pedido de %d número(s) aguardando pagamento foi cancelado e nada foi cobrado.", len(purchase.Numbers))
+	case refund == nil && purchase.TotalAmount.IsPositive():
+		message += fmt.Sprintf("Seu pedido de %d número(s) será cancelado e o valor de R$ %s devolvido em breve.", len(purchase.Numbers), purchase.TotalAmount)
+	case refund == nil:
+		message += fmt.Sprintf("Seu pedido de %d número(s) foi cancelado.", len(purchase.Numbers))
+	case refund.Status == models.RefundManual:
+		message += fmt.Sprintf("Seu pedido de %d número(s) foi cancelado e o valor de R$ %s será devolvido pelo organizador.", len(purchase.Numbers), refund.Amount)
+	default:
+		message += fmt.Sprintf("Seu pedido de %d número(s) foi cancelado e o valor de R$ %s será devolvido.", len(purchase.Numbers), refund.Amount)
+	}
+
+	rewardID, purchaseID := reward.ID, purchase.ID
+	err := s.notificationService.Notify(&models.Notification{
+		UserID:     purchase.UserID,
+		Type:       models.NotificationRewardCancelled,
+		Title:      "Prêmio cancelado",
+		Message:    message,
+		RewardID:   &rewardID,
+		PurchaseID: &purchaseID,
+	})
+	if err != nil {
+		log.Printf("Erro ao avisar o comprador da compra %s sobre o cancelamento do prêmio: %v", purchase.ID, err)
+	}
+}
+
+// RetryCancelledRewardRefunds cancela e devolve os pedidos que continuaram pagos em prêmios cancelados porque a
+// devolução falhou no cancelamento, avisando os compradores. Retorna quantos pedidos foram devolvidos
+func (s *RewardService) RetryCancelledRewardRefunds() (int, error) {
+	purchases, err := s.purchaseRepo.ListPaidOnCancelledRewards(refundRetryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar compras de prêmios cancelados: %w", err)
+	}
+
+	refunded := 0
+	for i := range purchases {
+		purchase := &purchases[i]
+		result, err := s.cancelPurchase(purchase, nil, "prêmio cancelado")
+		if err != nil {
+			// Outra instância pode ter devolvido o pedido primeiro
+			if err.Error() != "compra não pode ser cancelada" {
+				log.Printf("Erro ao devolver compra %s do prêmio cancelado %s: %v", purchase.ID, purchase.RewardID, err)
+			}
+			continue
+		}
+		refunded++
+
+		reward := &models.Reward{ID: purchase.RewardID, Name: purchase.RewardName}
+		s.notifyCancelledPurchase(reward, purchase, result.Refund, "prêmio cancelado")
+	}
+
+	return refunded, nil
+}
+
+// checkSalesOpen recusa colocar à venda um prêmio cujo horário de encerramento das vendas já passou
+func (s *RewardService) checkSalesOpen(rewardID uuid.UUID) error {
+	closeAt, err := s.rewardRepo.GetSalesCloseAt(rewardID)
+	if err == sql.ErrNoRows {
+		return errors.New("prêmio não encontrado")
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar encerramento das vendas: %w", err)
+	}
+	if !time.Now().Before(closeAt) {
+		return errors.New("prazo de vendas do prêmio encerrado")
+	}
 	return nil
 }
 
+// transition muda a situação de um prêmio do organizador ou, para administradores, de qualquer prêmio
+func (s *RewardService) transition(rewardID, actorID uuid.UUID, isAdmin bool, to string) (*models.RewardResponse, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+	if reward.OwnerID != actorID && !isAdmin {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	updated, err := s.rewardRepo.UpdateStatus(rewardID, to)
+	if err != nil {
+		var statusErr *models.RewardStatusError
+		if errors.As(err, &statusErr) || err.Error() == "prêmio não encontrado" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao atualizar situação do prêmio: %w", err)
+	}
+
+	return s.toRewardResponse(updated), nil
+}
+
 // GetBuyers busca todos os compradores de um prêmio
 func (s *RewardService) GetBuyers(rewardID uuid.UUID) ([]models.BuyerWithNumber, error) {
 	buyers, err := s.rewardRepo.GetBuyers(rewardID)
@@ -237,27 +716,357 @@ func (s *RewardService) GetBuyers(rewardID uuid.UUID) ([]models.BuyerWithNumber,
 	return buyers, nil
 }
 
-// BuyNumbers compra uma quantidade específica de números para um usuário
-func (s *RewardService) BuyNumbers(rewardID, userID uuid.UUID, quantity int) ([]int, error) {
+// BuyNumbers cria um pedido de compra para um usuário, pela quantidade ou pelos números escolhidos.
+// Os números ficam reservados até o pagamento do pedido ou o fim do prazo da reserva. Apenas o próprio
+// usuário ou um administrador compra em seu nome, e a carteira só paga pedidos do próprio dono. Pedidos pagos na hora
+// (sem valor ou com a carteira) retornam também as cotas premiadas resgatadas pelos seus números
+func (s *RewardService) BuyNumbers(rewardID, userID, actorID uuid.UUID, isAdmin bool, req *models.BuyNumbersRequest) (*models.Purchase, []models.InstantPrize, error) {
+	if actorID != userID && !isAdmin {
+		return nil, nil, errors.New("apenas o próprio usuário ou um administrador pode comprar em seu nome")
+	}
+	if req.PaymentMethod == models.PaymentMethodWallet && actorID != userID {
+		return nil, nil, errors.New("o pagamento com a carteira só pode ser feito pelo próprio comprador")
+	}
+
 	// Verificar se o prêmio existe
-	_, err := s.rewardRepo.GetByID(rewardID)
+	reward, err := s.rewardRepo.GetByID(rewardID)
 	if err != nil {
-		return nil, errors.New("prêmio não encontrado")
+		return nil, nil, errors.New("prêmio não encontrado")
+	}
+
+	if len(req.Numbers) > 0 && req.Quantity > 0 && req.Quantity != len(req.Numbers) {
+		return nil, nil, errors.New("quantidade não corresponde aos números escolhidos")
 	}
 
-	if quantity <= 0 {
-		return nil, errors.New("quantidade deve ser maior que zero")
+	// Pedidos de pacote levam a quantidade do pacote
+	requested := req.Quantity
+	if req.PackageID != nil {
+		pkg, err := s.rewardRepo.GetPackage(rewardID, *req.PackageID)
+		if err != nil {
+			if err.Error() == "pacote não encontrado" {
+				return nil, nil, err
+			}
+			return nil, nil, fmt.Errorf("erro ao buscar pacote: %w", err)
+		}
+		if (requested > 0 && requested != pkg.Quantity) || (len(req.Numbers) > 0 && len(req.Numbers) != pkg.Quantity) {
+			return nil, nil, errors.New("quantidade não corresponde ao pacote")
+		}
+		requested = pkg.Quantity
+	}
+
+	if len(req.Numbers) == 0 && requested <= 0 {
+		return nil, nil, errors.New("quantidade deve ser maior que zero")
+	}
+
+	chosen := make(map[int]bool)
+	for _, number := range req.Numbers {
+		if chosen[number] {
+			return nil, nil, fmt.Errorf("o número %d foi informado mais de uma vez", number)
+		}
+		chosen[number] = true
+	}
+
+	quantity := requested
+	if len(req.Numbers) > 0 {
+		quantity = len(req.Numbers)
+	}
+
+	// Conferir as regras de compra do prêmio
+	rules, err := s.rewardRepo.GetPurchaseRules(rewardID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar regras de compra: %w", err)
+	}
+	owned, err := s.rewardRepo.CountUserNumbers(rewardID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar números do usuário: %w", err)
+	}
+	if err := checkPurchaseRules(rules, quantity, owned); err != nil {
+		return nil, nil, err
+	}
+
+	expiresAt := time.Now().Add(s.reservationTTL)
+	purchase, err := s.purchaseRepo.Create(rewardID, userID, requested, req.Numbers, req.PackageID, rules.MaxPerUser, strings.TrimSpace(req.CouponCode), expiresAt)
+	if err != nil {
+		var unavailable *models.UnavailableNumbersError
+		var violations *models.PurchaseRulesError
+		var couponErr *models.CouponError
+		if errors.As(err, &unavailable) || errors.As(err, &violations) || errors.As(err, &couponErr) {
+			return nil, nil, err
+		}
+		switch err.Error() {
+		case "não é possível comprar números de um prêmio já completado",
+			"prêmio ainda não está à venda",
+			"vendas do prêmio encerradas",
+			"prazo de vendas do prêmio encerrado",
+			"prêmio cancelado",
+			"prêmio esgotado",
+			"quantidade solicitada excede os números disponíveis",
+			"pacote não encontrado",
+			"quantidade não corresponde ao pacote":
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("erro ao comprar números: %w", err)
+	}
+
+	// Pedidos sem valor não passam pelo provedor de pagamento
+	if purchase.TotalAmount.IsZero() {
+		result, err := s.purchaseRepo.MarkPaid(purchase.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("erro ao confirmar compra: %w", err)
+		}
+		return s.paidPurchase(result)
+	}
+
+	// Pedidos pagos com a carteira são debitados na hora; sem saldo os números voltam ao conjunto
+	if req.PaymentMethod == models.PaymentMethodWallet {
+		result, err := s.purchaseRepo.PayFromWallet(purchase.ID)
+		if err != nil {
+			if _, cancelErr := s.purchaseRepo.Cancel(purchase.ID, nil, "falha no pagamento com a carteira"); cancelErr != nil {
+				log.Printf("Erro ao cancelar compra %s não paga pela carteira: %v", purchase.ID, cancelErr)
+			}
+			if err.Error() == "saldo insuficiente na carteira" {
+				return nil, nil, err
+			}
+			return nil, nil, fmt.Errorf("erro ao pagar com a carteira: %w", err)
+		}
+		return s.paidPurchase(result)
 	}
 
-	numbers, err := s.rewardRepo.BuyNumbers(rewardID, userID, quantity)
+	// Criar a cobrança; sem ela o pedido não pode ser pago e os números voltam ao conjunto
+	if err := s.paymentService.CreateCharge(purchase, reward.OwnerID); err != nil {
+		if _, cancelErr := s.purchaseRepo.Cancel(purchase.ID, nil, "falha ao criar cobrança"); cancelErr != nil {
+			log.Printf("Erro ao cancelar compra %s sem cobrança: %v", purchase.ID, cancelErr)
+		}
+		return nil, nil, fmt.Errorf("erro ao criar cobrança: %w", err)
+	}
+
+	return purchase, []models.InstantPrize{}, nil
+}
+
+// paidPurchase busca um pedido pago na hora da compra, com as cotas premiadas resgatadas no pagamento
+func (s *RewardService) paidPurchase(result *models.BuyNumbersResult) (*models.Purchase, []models.InstantPrize, error) {
+	purchase, err := s.purchaseRepo.GetByID(result.PurchaseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return purchase, result.InstantPrizes, nil
+}
+
+// ConfirmPurchase consulta o pagamento de um pedido pendente do usuário e, se confirmado pelo provedor,
+// torna seus números vendidos. Pedidos pagos por PIX direto são confirmados pelo organizador do prêmio
+func (s *RewardService) ConfirmPurchase(purchaseID, userID uuid.UUID) (*models.BuyNumbersResult, error) {
+	purchase, err := s.purchaseRepo.GetByID(purchaseID)
+	if err != nil {
+		return nil, errors.New("compra não encontrada")
+	}
+
+	if IsDirectPix(purchase) {
+		reward, err := s.rewardRepo.GetByID(purchase.RewardID)
+		if err != nil {
+			return nil, errors.New("prêmio não encontrado")
+		}
+		if reward.OwnerID != userID {
+			if purchase.UserID == userID {
+				return nil, errors.New("pagamento aguardando confirmação do organizador")
+			}
+			return nil, errors.New("compra pertence a outro usuário")
+		}
+	} else if purchase.UserID != userID {
+		return nil, errors.New("compra pertence a outro usuário")
+	}
+
+	// O organizador recebeu o PIX de um pedido que já expirou ou foi cancelado: os números foram liberados
+	// e o valor passa a ser uma devolução devida ao comprador
+	if IsDirectPix(purchase) && (purchase.PaymentStatus == models.PaymentExpired || purchase.PaymentStatus == models.PaymentCancelled) {
+		reason := "pagamento recebido após o fim da reserva"
+		if purchase.PaymentStatus == models.PaymentCancelled {
+			reason = "pagamento recebido após o cancelamento da compra"
+		}
+		if _, err := s.paymentService.RecordLateDirectPix(purchase, userID, reason); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("pagamento recebido após a liberação dos números; devolva o valor ao comprador")
+	}
+
+	if purchase.PaymentStatus == models.PaymentExpired {
+		return nil, errors.New("reserva expirada")
+	}
+	if purchase.PaymentStatus != models.PaymentPending {
+		return nil, errors.New("compra não está pendente")
+	}
+
+	result, err := s.paymentService.Settle(purchase)
 	if err != nil {
-		if err.Error() == "não é possível comprar números de um prêmio já completado" {
+		switch err.Error() {
+		case "não é possível comprar números de um prêmio já completado",
+			"prêmio ainda não está à venda",
+			"prêmio cancelado",
+			"compra não está pendente",
+			"reserva expirada":
 			return nil, err
 		}
-		return nil, fmt.Errorf("erro ao comprar números: %w", err)
+		return nil, fmt.Errorf("erro ao confirmar compra: %w", err)
+	}
+	if result == nil {
+		return nil, errors.New("pagamento ainda não confirmado")
 	}
 
-	return numbers, nil
+	return result, nil
+}
+
+// GetPurchase busca um pedido de compra do usuário
+func (s *RewardService) GetPurchase(purchaseID, userID uuid.UUID) (*models.Purchase, error) {
+	purchase, err := s.purchaseRepo.GetByID(purchaseID)
+	if err != nil {
+		return nil, errors.New("compra não encontrada")
+	}
+
+	if purchase.UserID != userID {
+		return nil, errors.New("compra pertence a outro usuário")
+	}
+
+	return purchase, nil
+}
+
+// CancelPurchase cancela um pedido pendente ou pago e devolve seus números ao conjunto. Pedidos pagos
+// ganham uma devolução pelo provedor de pagamento. Pode ser feito pelo comprador ou por um administrador
+func (s *RewardService) CancelPurchase(purchaseID, actorID uuid.UUID, isAdmin bool, reason string) (*models.CancelPurchaseResponse, error) {
+	purchase, err := s.purchaseRepo.GetByID(purchaseID)
+	if err != nil {
+		return nil, errors.New("compra não encontrada")
+	}
+
+	if purchase.UserID != actorID && !isAdmin {
+		return nil, errors.New("apenas o comprador ou um administrador pode cancelar a compra")
+	}
+
+	return s.cancelPurchase(purchase, &actorID, reason)
+}
+
+// ListPurchaseRefunds lista as devoluções de um pedido do usuário
+func (s *RewardService) ListPurchaseRefunds(purchaseID, userID uuid.UUID, isAdmin bool) (*models.PurchaseRefundListResponse, error) {
+	purchase, err := s.purchaseRepo.GetByID(purchaseID)
+	if err != nil {
+		return nil, errors.New("compra não encontrada")
+	}
+
+	if purchase.UserID != userID && !isAdmin {
+		return nil, errors.New("compra pertence a outro usuário")
+	}
+
+	refunds, err := s.paymentService.ListRefunds(purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar devoluções: %w", err)
+	}
+
+	return &models.PurchaseRefundListResponse{Refunds: refunds}, nil
+}
+
+// cancelPurchase cancela o pedido e solicita a devolução do valor pago, se houver. actorID é nil nos cancelamentos automáticos
+func (s *RewardService) cancelPurchase(purchase *models.Purchase, actorID *uuid.UUID, reason string) (*models.CancelPurchaseResponse, error) {
+	refund, err := s.purchaseRepo.Cancel(purchase.ID, actorID, reason)
+	if err != nil {
+		switch err.Error() {
+		case "compra não encontrada",
+			"compra não pode ser cancelada",
+			"não é possível cancelar compras de um prêmio já sorteado",
+			"compras com cota premiada resgatada não podem ser canceladas":
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao cancelar compra: %w", err)
+	}
+
+	if refund != nil {
+		refund = s.paymentService.Refund(purchase, refund)
+	}
+
+	cancelled, err := s.purchaseRepo.GetByID(purchase.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar compra: %w", err)
+	}
+
+	return &models.CancelPurchaseResponse{Purchase: cancelled, Refund: refund}, nil
+}
+
+// GetPurchasePix busca o código PIX "copia e cola" de um pedido pendente do usuário
+func (s *RewardService) GetPurchasePix(purchaseID, userID uuid.UUID) (*models.PurchasePixResponse, error) {
+	purchase, err := s.GetPurchase(purchaseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.paymentService.PixCode(purchase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PurchasePixResponse{
+		PurchaseID:      purchase.ID,
+		PaymentProvider: *purchase.PaymentProvider,
+		TxID:            *purchase.ChargeID,
+		Amount:          purchase.TotalAmount,
+		PixCopyPaste:    code,
+		ExpiresAt:       purchase.ExpiresAt,
+	}, nil
+}
+
+// GetPurchasePixQRCode gera a imagem PNG do QR Code PIX de um pedido pendente do usuário
+func (s *RewardService) GetPurchasePixQRCode(purchaseID, userID uuid.UUID, size int) ([]byte, error) {
+	purchase, err := s.GetPurchase(purchaseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.paymentService.PixCode(purchase)
+	if err != nil {
+		return nil, err
+	}
+
+	png, err := pix.QRCodePNG(code, size)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar QR Code: %w", err)
+	}
+
+	return png, nil
+}
+
+// ExpirePurchases devolve ao conjunto os números dos pedidos não pagos com reserva vencida
+func (s *RewardService) ExpirePurchases() (int64, error) {
+	return s.purchaseRepo.ExpirePending(time.Now())
+}
+
+// GetNumberAvailability lista os números livres de um prêmio dentro do intervalo informado
+func (s *RewardService) GetNumberAvailability(rewardID uuid.UUID, from, to int) (*models.NumberAvailabilityResponse, error) {
+	// Verificar se o prêmio existe
+	_, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+
+	total, sold, _, err := s.rewardRepo.GetNumberStats(rewardID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar números do prêmio: %w", err)
+	}
+
+	if to > total {
+		to = total
+	}
+
+	available := []int{}
+	if from <= to {
+		available, err = s.rewardRepo.GetAvailableNumbers(rewardID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar números disponíveis: %w", err)
+		}
+	}
+
+	return &models.NumberAvailabilityResponse{
+		TotalNumbers: total,
+		SoldNumbers:  sold,
+		From:         from,
+		To:           to,
+		Available:    available,
+	}, nil
 }
 
 // GetUserNumbers busca os números específicos de um usuário em um prêmio
@@ -276,6 +1085,141 @@ func (s *RewardService) GetUserNumbers(rewardID, userID uuid.UUID) ([]int, error
 	return numbers, nil
 }
 
+// AddInstantPrizes cadastra cotas premiadas em um prêmio (apenas o organizador)
+func (s *RewardService) AddInstantPrizes(rewardID, userID uuid.UUID, req *models.CreateInstantPrizesRequest) ([]models.InstantPrize, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+
+	if reward.OwnerID != userID {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	if reward.IsDrawn() {
+		return nil, errors.New("não é possível cadastrar cotas premiadas em um prêmio que já foi sorteado")
+	}
+
+	total, _, _, err := s.rewardRepo.GetNumberStats(rewardID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar números do prêmio: %w", err)
+	}
+
+	numbers := make(map[int]bool)
+	for _, prize := range req.Prizes {
+		if prize.Number < 1 || prize.Number > total {
+			return nil, fmt.Errorf("o número %d está fora do intervalo do prêmio", prize.Number)
+		}
+		if numbers[prize.Number] {
+			return nil, fmt.Errorf("o número %d foi informado mais de uma vez", prize.Number)
+		}
+		numbers[prize.Number] = true
+	}
+
+	prizes, err := s.rewardRepo.AddInstantPrizes(rewardID, req.Prizes)
+	if err != nil {
+		return nil, err
+	}
+
+	return prizes, nil
+}
+
+// ListInstantPrizes lista as cotas premiadas de um prêmio sem revelar os números ainda não comprados
+func (s *RewardService) ListInstantPrizes(rewardID uuid.UUID) (*models.InstantPrizeListResponse, error) {
+	if _, err := s.rewardRepo.GetByID(rewardID); err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+
+	prizes, err := s.rewardRepo.ListInstantPrizes(rewardID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar cotas premiadas: %w", err)
+	}
+
+	response := &models.InstantPrizeListResponse{
+		Prizes: []models.InstantPrizeResponse{},
+		Total:  len(prizes),
+	}
+	for _, prize := range prizes {
+		item := models.InstantPrizeResponse{
+			ID:          prize.ID,
+			Name:        prize.Name,
+			Description: prize.Description,
+			Value:       prize.Value,
+			Claimed:     prize.ClaimedBy != nil,
+		}
+		if item.Claimed {
+			number := prize.Number
+			item.Number = &number
+			item.Winner = prize.Winner
+			item.ClaimedAt = prize.ClaimedAt
+			response.Claimed++
+		}
+		response.Prizes = append(response.Prizes, item)
+	}
+	response.Unclaimed = response.Total - response.Claimed
+
+	return response, nil
+}
+
+// AddPackage cadastra um pacote de números com preço próprio em um prêmio (apenas o organizador)
+func (s *RewardService) AddPackage(rewardID, userID uuid.UUID, req *models.CreateRewardPackageRequest) (*models.RewardPackage, error) {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+
+	if reward.OwnerID != userID {
+		return nil, errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	if reward.IsDrawn() {
+		return nil, errors.New("não é possível cadastrar pacotes em um prêmio que já foi sorteado")
+	}
+
+	total, _, _, err := s.rewardRepo.GetNumberStats(rewardID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar números do prêmio: %w", err)
+	}
+	if req.Quantity > total {
+		return nil, errors.New("quantidade do pacote excede os números do prêmio")
+	}
+
+	pkg, err := s.rewardRepo.AddPackage(rewardID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg, nil
+}
+
+// RemovePackage desativa um pacote de um prêmio (apenas o organizador). Pedidos já feitos não são alterados
+func (s *RewardService) RemovePackage(rewardID, packageID, userID uuid.UUID) error {
+	reward, err := s.rewardRepo.GetByID(rewardID)
+	if err != nil {
+		return errors.New("prêmio não encontrado")
+	}
+
+	if reward.OwnerID != userID {
+		return errors.New("apenas o organizador do prêmio pode realizar esta operação")
+	}
+
+	return s.rewardRepo.DeactivatePackage(rewardID, packageID)
+}
+
+// ListPackages lista os pacotes ativos de um prêmio
+func (s *RewardService) ListPackages(rewardID uuid.UUID) (*models.RewardPackageListResponse, error) {
+	if _, err := s.rewardRepo.GetByID(rewardID); err != nil {
+		return nil, errors.New("prêmio não encontrado")
+	}
+
+	packages, err := s.rewardRepo.GetPackages(rewardID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pacotes: %w", err)
+	}
+
+	return &models.RewardPackageListResponse{Packages: packages}, nil
+}
+
 // GetUserPurchases busca todas as compras de um usuário
 func (s *RewardService) GetUserPurchases(userID uuid.UUID, page, limit int) (*models.PurchaseListResponse, error) {
 	if page < 1 {
@@ -288,7 +1232,7 @@ func (s *RewardService) GetUserPurchases(userID uuid.UUID, page, limit int) (*mo
 		limit = 100
 	}
 
-	purchases, total, err := s.rewardRepo.GetUserPurchases(userID, page, limit)
+	purchases, total, err := s.purchaseRepo.ListByUser(userID, page, limit)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar compras do usuário: %w", err)
 	}
@@ -354,18 +1298,35 @@ func (s *RewardService) ListByOwner(ownerID uuid.UUID, page, limit int) (*models
 	}, nil
 }
 
+// validatePrizes verifica se as faixas de premiação possuem posições distintas
+func validatePrizes(prizes []models.RewardPrizeRequest) error {
+	positions := make(map[int]bool)
+	for _, prize := range prizes {
+		if positions[prize.Position] {
+			return fmt.Errorf("posição de premiação duplicada: %d", prize.Position)
+		}
+		positions[prize.Position] = true
+	}
+	return nil
+}
+
 // toRewardResponse converte Reward para RewardResponse
 func (s *RewardService) toRewardResponse(reward *models.Reward) *models.RewardResponse {
 	return &models.RewardResponse{
-		ID:          reward.ID,
-		OwnerID:     reward.OwnerID,
-		Name:        reward.Name,
-		Description: reward.Description,
-		Image:       reward.Image,
-		DrawDate:    reward.DrawDate,
-		Completed:   reward.Completed,
-		CreatedAt:   reward.CreatedAt,
-		UpdatedAt:   reward.UpdatedAt,
+		ID:              reward.ID,
+		OwnerID:         reward.OwnerID,
+		Name:            reward.Name,
+		Description:     reward.Description,
+		Image:           reward.Image,
+		DrawDate:        reward.DrawDate,
+		Status:          reward.Status,
+		StatusChangedAt: reward.StatusChangedAt,
+		SoldOut:         reward.SoldOut,
+		WinnerNumber:    reward.WinnerNumber,
+		DrawnAt:         reward.DrawnAt,
+		DrawSeedHash:    reward.DrawSeedHash,
+		CreatedAt:       reward.CreatedAt,
+		UpdatedAt:       reward.UpdatedAt,
 	}
 }
 
@@ -378,8 +1339,15 @@ func (s *RewardService) toRewardDetailsResponse(rewardDetails *models.RewardDeta
 		Images:         rewardDetails.Images,
 		Price:          rewardDetails.Price,
 		MinQuota:       rewardDetails.MinQuota,
+		RewardOptions:  rewardDetails.RewardOptions,
+		SoldNumbers:    rewardDetails.SoldNumbers,
+		SalesCloseAt:   rewardDetails.SalesCloseAt,
+		Prizes:         rewardDetails.Prizes,
+		Packages:       rewardDetails.Packages,
 		Buyers:         rewardDetails.Buyers,
-		WinnerUser:     rewardDetails.WinnerUser,
+		Winners:        rewardDetails.Winners,
+		TopBuyers:      rewardDetails.TopBuyers,
+		DateChanges:    rewardDetails.DateChanges,
 	}
 }
 
@@ -392,7 +1360,14 @@ func (s *RewardService) ToRewardDetailsWithoutBuyersResponse(rewardDetails *mode
 		Images:         rewardDetails.Images,
 		Price:          rewardDetails.Price,
 		MinQuota:       rewardDetails.MinQuota,
-		WinnerUser:     rewardDetails.WinnerUser,
+		RewardOptions:  rewardDetails.RewardOptions,
+		SoldNumbers:    rewardDetails.SoldNumbers,
+		SalesCloseAt:   rewardDetails.SalesCloseAt,
+		Prizes:         rewardDetails.Prizes,
+		Packages:       rewardDetails.Packages,
+		Winners:        rewardDetails.Winners,
+		TopBuyers:      rewardDetails.TopBuyers,
+		DateChanges:    rewardDetails.DateChanges,
 	}
 }
 
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// SettlementService implementa a apuração dos repasses aos organizadores e das taxas da plataforma
+type SettlementService struct {
+	settlementRepo    *repository.SettlementRepository
+	defaultFeePercent float64
+}
+
+// NewSettlementService cria uma nova instância do serviço de repasses
+func NewSettlementService(settlementRepo *repository.SettlementRepository, defaultFeePercent float64) *SettlementService {
+	return &SettlementService{settlementRepo: settlementRepo, defaultFeePercent: defaultFeePercent}
+}
+
+// CreateForReward apura o repasse de um prêmio recém-sorteado
+func (s *SettlementService) CreateForReward(rewardID uuid.UUID) error {
+	return s.settlementRepo.CreateForReward(rewardID, s.defaultFeePercent)
+}
+
+// GetPayoutReport monta o relatório de repasses de um organizador. Prêmios sorteados cujo repasse
+// não foi apurado no sorteio são apurados aqui
+func (s *SettlementService) GetPayoutReport(ownerID uuid.UUID) (*models.PayoutReportResponse, error) {
+	if err := s.settlementRepo.CreateMissingForOwner(ownerID, s.defaultFeePercent); err != nil {
+		return nil, err
+	}
+
+	settlements, err := s.settlementRepo.ListByOwner(ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming, err := s.settlementRepo.ListUpcomingProceeds(ownerID, s.defaultFeePercent)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary models.PayoutSummary
+	for _, settlement := range settlements {
+		total := &summary.PendingAmount
+		if settlement.Status == models.SettlementPaid {
+			total = &summary.PaidAmount
+		}
+		if *total, err = total.Add(settlement.NetAmount); err != nil {
+			return nil, fmt.Errorf("erro ao totalizar repasses: %w", err)
+		}
+	}
+	for _, proceeds := range upcoming {
+		if summary.UpcomingAmount, err = summary.UpcomingAmount.Add(proceeds.NetAmount); err != nil {
+			return nil, fmt.Errorf("erro ao totalizar repasses: %w", err)
+		}
+	}
+
+	return &models.PayoutReportResponse{
+		Summary:     summary,
+		Settlements: settlements,
+		Upcoming:    upcoming,
+	}, nil
+}
+
+// MarkPaid registra o pagamento de um repasse pendente (apenas administradores)
+func (s *SettlementService) MarkPaid(settlementID uuid.UUID, isAdmin bool, req *models.MarkSettlementPaidRequest) (*models.RewardSettlement, error) {
+	if !isAdmin {
+		return nil, errors.New("apenas administradores podem realizar esta operação")
+	}
+
+	return s.settlementRepo.MarkPaid(settlementID, req.Reference)
+}
+
+// SetRewardFee define a taxa da plataforma de um prêmio (apenas administradores)
+func (s *SettlementService) SetRewardFee(rewardID uuid.UUID, isAdmin bool, req *models.UpdatePlatformFeeRequest) error {
+	if !isAdmin {
+		return errors.New("apenas administradores podem realizar esta operação")
+	}
+
+	return s.settlementRepo.SetRewardFee(rewardID, req.PlatformFeePercent)
+}
+
+// SetOrganizerFee define a taxa da plataforma de um organizador (apenas administradores)
+func (s *SettlementService) SetOrganizerFee(userID uuid.UUID, isAdmin bool, req *models.UpdatePlatformFeeRequest) error {
+	if !isAdmin {
+		return errors.New("apenas administradores podem realizar esta operação")
+	}
+
+	return s.settlementRepo.SetOrganizerFee(userID, req.PlatformFeePercent)
+}
@@ -3,11 +3,14 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/pix"
 	"github.com/cauamistura/BNUPremios/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -16,13 +19,14 @@ import (
 
 // UserService implementa a lógica de negócio para usuários
 type UserService struct {
-	userRepo  *repository.UserRepository
-	jwtSecret string
+	userRepo        *repository.UserRepository
+	referralService *ReferralService
+	jwtSecret       string
 }
 
 // NewUserService cria uma nova instância do serviço de usuários
-func NewUserService(userRepo *repository.UserRepository, jwtSecret string) *UserService {
-	return &UserService{userRepo: userRepo, jwtSecret: jwtSecret}
+func NewUserService(userRepo *repository.UserRepository, referralService *ReferralService, jwtSecret string) *UserService {
+	return &UserService{userRepo: userRepo, referralService: referralService, jwtSecret: jwtSecret}
 }
 
 // Create cria um novo usuário
@@ -175,6 +179,38 @@ func (s *UserService) Delete(id string) error {
 	return s.userRepo.Delete(userID)
 }
 
+// GetPixSettings busca a chave PIX do usuário
+func (s *UserService) GetPixSettings(userID uuid.UUID) (*models.PixSettings, error) {
+	settings, err := s.userRepo.GetPixSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, errors.New("chave PIX não configurada")
+	}
+
+	return settings, nil
+}
+
+// UpdatePixSettings valida e define a chave PIX em que o usuário recebe os pagamentos dos seus prêmios
+func (s *UserService) UpdatePixSettings(userID uuid.UUID, req *models.UpdatePixSettingsRequest) (*models.PixSettings, error) {
+	key, err := pix.NormalizeKey(req.PixKey)
+	if err != nil {
+		return nil, err
+	}
+
+	city := strings.TrimSpace(req.MerchantCity)
+	if city == "" {
+		return nil, errors.New("cidade do recebedor é obrigatória")
+	}
+
+	if err := s.userRepo.UpdatePixSettings(userID, key, city); err != nil {
+		return nil, err
+	}
+
+	return s.GetPixSettings(userID)
+}
+
 // Login autentica um usuário
 func (s *UserService) Login(loginReq *models.LoginRequest) (*models.LoginResponse, error) {
 	// Buscar usuário por email
@@ -213,8 +249,18 @@ func (s *UserService) Login(loginReq *models.LoginRequest) (*models.LoginRespons
 	}, nil
 }
 
-// Register registra um novo usuário
+// Register registra um novo usuário. Com código de indicação, registra quem o indicou
 func (s *UserService) Register(registerReq *models.RegisterRequest) (*models.UserResponse, error) {
+	// O código é conferido antes do cadastro para que códigos inválidos não criem o usuário
+	var referrerID uuid.UUID
+	if code := strings.TrimSpace(registerReq.ReferralCode); code != "" {
+		id, err := s.referralService.ResolveReferrer(code, registerReq.Email)
+		if err != nil {
+			return nil, err
+		}
+		referrerID = id
+	}
+
 	user := &models.User{
 		Name:     registerReq.Name,
 		Email:    registerReq.Email,
@@ -223,7 +269,18 @@ func (s *UserService) Register(registerReq *models.RegisterRequest) (*models.Use
 		Active:   true,
 	}
 
-	return s.Create(user)
+	response, err := s.Create(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if referrerID != uuid.Nil {
+		if err := s.referralService.Record(referrerID, response.ID); err != nil {
+			log.Printf("Erro ao registrar indicação do usuário %s: %v", response.ID, err)
+		}
+	}
+
+	return response, nil
 }
 
 // toUserResponse converte User para UserResponse
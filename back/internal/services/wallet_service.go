@@ -0,0 +1,60 @@
+package services
+
+import (
+	"github.com/cauamistura/BNUPremios/internal/models"
+	"github.com/cauamistura/BNUPremios/internal/repository"
+	"github.com/google/uuid"
+)
+
+// WalletService implementa a consulta das carteiras dos usuários
+type WalletService struct {
+	ledgerRepo *repository.LedgerRepository
+}
+
+// NewWalletService cria uma nova instância do serviço de carteiras
+func NewWalletService(ledgerRepo *repository.LedgerRepository) *WalletService {
+	return &WalletService{ledgerRepo: ledgerRepo}
+}
+
+// GetWallet busca o saldo da carteira de um usuário
+func (s *WalletService) GetWallet(userID uuid.UUID) (*models.Wallet, error) {
+	return s.ledgerRepo.GetWallet(userID)
+}
+
+// ListTransactions lista os lançamentos da carteira de um usuário com paginação
+func (s *WalletService) ListTransactions(userID uuid.UUID, page, limit int) (*models.LedgerEntryListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	wallet, err := s.ledgerRepo.GetWallet(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, total, err := s.ledgerRepo.ListEntries(userID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := (total + limit - 1) / limit
+
+	return &models.LedgerEntryListResponse{
+		Wallet:  *wallet,
+		Entries: entries,
+		Pagination: models.Pagination{
+			Page:    page,
+			Limit:   limit,
+			Total:   total,
+			Pages:   pages,
+			HasNext: page < pages,
+			HasPrev: page > 1,
+		},
+	}, nil
+}
@@ -0,0 +1,3 @@
+ALTER TABLE rewards DROP COLUMN IF EXISTS draw_numbers_digest;
+ALTER TABLE rewards DROP COLUMN IF EXISTS draw_seed_hash;
+ALTER TABLE rewards DROP COLUMN IF EXISTS draw_seed;
@@ -0,0 +1,4 @@
+-- Compromisso do sorteio (commit-reveal): o hash da semente é publicado na criação e a semente é revelada no sorteio
+ALTER TABLE rewards ADD COLUMN draw_seed VARCHAR(64);
+ALTER TABLE rewards ADD COLUMN draw_seed_hash VARCHAR(64);
+ALTER TABLE rewards ADD COLUMN draw_numbers_digest VARCHAR(64);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_rewards_pending_draw;
+DROP INDEX IF EXISTS idx_reward_draw_failures_next_attempt_at;
+DROP TABLE IF EXISTS reward_draw_failures;
@@ -0,0 +1,13 @@
+-- Registro de falhas do sorteio automático para nova tentativa
+CREATE TABLE IF NOT EXISTS reward_draw_failures (
+    reward_id UUID PRIMARY KEY REFERENCES rewards(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_draw_failures_next_attempt_at ON reward_draw_failures(next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_rewards_pending_draw ON rewards(draw_date) WHERE winner_number IS NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_reward_winners_user_id;
+DROP INDEX IF EXISTS idx_reward_prizes_reward_id;
+
+DROP TABLE IF EXISTS reward_winners;
+DROP TABLE IF EXISTS reward_prizes;
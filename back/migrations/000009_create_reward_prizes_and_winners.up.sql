@@ -0,0 +1,38 @@
+-- Faixas de premiação (1º, 2º, 3º lugar, prêmios de consolação...)
+CREATE TABLE IF NOT EXISTS reward_prizes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reward_id UUID NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    name VARCHAR(255) NOT NULL,
+    image VARCHAR(500),
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (reward_id, position)
+);
+
+-- Números vencedores de cada sorteio, na ordem em que foram sorteados
+CREATE TABLE IF NOT EXISTS reward_winners (
+    reward_id UUID NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    prize_id UUID REFERENCES reward_prizes(id) ON DELETE SET NULL,
+    position INTEGER NOT NULL,
+    prize_name VARCHAR(255) NOT NULL,
+    draw_order INTEGER NOT NULL,
+    draw_index INTEGER,
+    number INTEGER NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    drawn_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (reward_id, number),
+    UNIQUE (reward_id, draw_order)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_prizes_reward_id ON reward_prizes(reward_id);
+CREATE INDEX IF NOT EXISTS idx_reward_winners_user_id ON reward_winners(user_id);
+
+-- Migrar os vencedores dos sorteios já realizados
+INSERT INTO reward_winners (reward_id, position, prize_name, draw_order, number, user_id, drawn_at)
+SELECT r.id, 1, r.name, 1, r.winner_number, rb.user_id, COALESCE(r.drawn_at, CURRENT_TIMESTAMP)
+FROM rewards r
+INNER JOIN reward_buyers rb ON rb.reward_id = r.id AND rb.number = r.winner_number
+WHERE r.winner_number IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reward_instant_prizes_reward_id;
+DROP TABLE IF EXISTS reward_instant_prizes;
@@ -0,0 +1,15 @@
+-- Cotas premiadas: números que dão um prêmio instantâneo a quem os comprar
+CREATE TABLE IF NOT EXISTS reward_instant_prizes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reward_id UUID NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL CHECK (number > 0),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    value DECIMAL(10,2) DEFAULT 0.00,
+    claimed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    claimed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (reward_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_instant_prizes_reward_id ON reward_instant_prizes(reward_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reward_top_buyers;
+
+ALTER TABLE reward_details DROP COLUMN IF EXISTS daily_top_buyer_prize;
+ALTER TABLE reward_details DROP COLUMN IF EXISTS top_buyer_prize;
@@ -0,0 +1,16 @@
+-- Prêmios opcionais para o maior comprador do prêmio e o maior comprador do dia
+ALTER TABLE reward_details ADD COLUMN top_buyer_prize VARCHAR(255);
+ALTER TABLE reward_details ADD COLUMN daily_top_buyer_prize VARCHAR(255);
+
+-- Maiores compradores apurados no encerramento das vendas ou no sorteio
+CREATE TABLE IF NOT EXISTS reward_top_buyers (
+    reward_id UUID NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('overall', 'daily')),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    prize_name VARCHAR(255) NOT NULL,
+    total_numbers INTEGER NOT NULL,
+    first_purchase_at TIMESTAMP NOT NULL,
+    reference_date DATE,
+    resolved_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (reward_id, kind)
+);
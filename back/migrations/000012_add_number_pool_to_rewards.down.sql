@@ -0,0 +1,4 @@
+ALTER TABLE rewards DROP COLUMN IF EXISTS sold_out;
+
+ALTER TABLE reward_details DROP CONSTRAINT IF EXISTS check_total_numbers;
+ALTER TABLE reward_details DROP COLUMN IF EXISTS total_numbers;
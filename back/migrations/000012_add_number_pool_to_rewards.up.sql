@@ -0,0 +1,18 @@
+-- Quantidade total de números disponíveis para venda em cada prêmio
+ALTER TABLE reward_details ADD COLUMN total_numbers INTEGER NOT NULL DEFAULT 10000;
+ALTER TABLE reward_details ADD CONSTRAINT check_total_numbers CHECK (total_numbers > 0);
+
+-- Prêmios existentes precisam comportar os números já vendidos
+UPDATE reward_details rd
+SET total_numbers = GREATEST(rd.total_numbers, sold.max_number)
+FROM (SELECT reward_id, MAX(number) AS max_number FROM reward_buyers GROUP BY reward_id) sold
+WHERE sold.reward_id = rd.reward_id;
+
+-- Indica que todos os números do prêmio foram vendidos
+ALTER TABLE rewards ADD COLUMN sold_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE rewards r
+SET sold_out = TRUE
+FROM reward_details rd
+WHERE rd.reward_id = r.id
+    AND (SELECT COUNT(*) FROM reward_buyers rb WHERE rb.reward_id = r.id) >= rd.total_numbers;
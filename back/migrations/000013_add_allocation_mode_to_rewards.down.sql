@@ -0,0 +1,2 @@
+ALTER TABLE reward_details DROP CONSTRAINT IF EXISTS check_allocation_mode;
+ALTER TABLE reward_details DROP COLUMN IF EXISTS allocation_mode;
@@ -0,0 +1,3 @@
+-- Forma de distribuição dos números comprados por quantidade: sequencial ou aleatória
+ALTER TABLE reward_details ADD COLUMN allocation_mode VARCHAR(20) NOT NULL DEFAULT 'sequential';
+ALTER TABLE reward_details ADD CONSTRAINT check_allocation_mode CHECK (allocation_mode IN ('sequential', 'random'));
@@ -0,0 +1,7 @@
+ALTER TABLE reward_details DROP CONSTRAINT IF EXISTS check_quantity_step;
+ALTER TABLE reward_details DROP CONSTRAINT IF EXISTS check_max_per_user;
+ALTER TABLE reward_details DROP CONSTRAINT IF EXISTS check_max_per_order;
+
+ALTER TABLE reward_details DROP COLUMN IF EXISTS quantity_step;
+ALTER TABLE reward_details DROP COLUMN IF EXISTS max_per_user;
+ALTER TABLE reward_details DROP COLUMN IF EXISTS max_per_order;
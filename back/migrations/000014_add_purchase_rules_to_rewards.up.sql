@@ -0,0 +1,8 @@
+-- Regras de compra por prêmio (min_quota já define o mínimo de números por pedido)
+ALTER TABLE reward_details ADD COLUMN max_per_order INTEGER;
+ALTER TABLE reward_details ADD COLUMN max_per_user INTEGER;
+ALTER TABLE reward_details ADD COLUMN quantity_step INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE reward_details ADD CONSTRAINT check_max_per_order CHECK (max_per_order IS NULL OR max_per_order > 0);
+ALTER TABLE reward_details ADD CONSTRAINT check_max_per_user CHECK (max_per_user IS NULL OR max_per_user > 0);
+ALTER TABLE reward_details ADD CONSTRAINT check_quantity_step CHECK (quantity_step > 0);
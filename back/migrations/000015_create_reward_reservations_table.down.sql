@@ -0,0 +1,8 @@
+DELETE FROM reward_buyers WHERE status = 'reserved';
+
+DROP INDEX IF EXISTS idx_reward_buyers_reservation_id;
+ALTER TABLE reward_buyers DROP COLUMN IF EXISTS reservation_id;
+ALTER TABLE reward_buyers DROP CONSTRAINT IF EXISTS check_reward_buyers_status;
+ALTER TABLE reward_buyers DROP COLUMN IF EXISTS status;
+
+DROP TABLE IF EXISTS reward_reservations;
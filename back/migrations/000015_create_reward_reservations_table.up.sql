@@ -0,0 +1,21 @@
+-- Reservas temporárias de números aguardando a confirmação da compra
+CREATE TABLE IF NOT EXISTS reward_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reward_id UUID NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'reserved' CHECK (status IN ('reserved', 'confirmed', 'expired', 'cancelled')),
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_reservations_pending ON reward_reservations(expires_at) WHERE status = 'reserved';
+CREATE INDEX IF NOT EXISTS idx_reward_reservations_reward_id ON reward_reservations(reward_id);
+
+-- Números reservados ocupam o conjunto, mas só contam no sorteio e nas listas de compradores depois de vendidos
+ALTER TABLE reward_buyers ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'sold';
+ALTER TABLE reward_buyers ADD CONSTRAINT check_reward_buyers_status CHECK (status IN ('reserved', 'sold'));
+ALTER TABLE reward_buyers ADD COLUMN reservation_id UUID REFERENCES reward_reservations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_reward_buyers_reservation_id ON reward_buyers(reservation_id);
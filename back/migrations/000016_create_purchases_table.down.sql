@@ -0,0 +1,23 @@
+ALTER INDEX IF EXISTS idx_reward_buyers_purchase_id RENAME TO idx_reward_buyers_reservation_id;
+ALTER TABLE reward_buyers RENAME COLUMN purchase_id TO reservation_id;
+
+DROP INDEX IF EXISTS idx_purchases_user_id;
+DROP INDEX IF EXISTS idx_purchases_pending;
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS total_amount;
+ALTER TABLE purchases DROP COLUMN IF EXISTS unit_price;
+ALTER TABLE purchases DROP COLUMN IF EXISTS quantity;
+ALTER TABLE purchases DROP COLUMN IF EXISTS numbers;
+
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS check_purchases_payment_status;
+UPDATE purchases
+SET payment_status = CASE payment_status WHEN 'pending' THEN 'reserved' WHEN 'paid' THEN 'confirmed' ELSE payment_status END;
+ALTER TABLE purchases ALTER COLUMN payment_status SET DEFAULT 'reserved';
+ALTER TABLE purchases ADD CONSTRAINT reward_reservations_status_check CHECK (payment_status IN ('reserved', 'confirmed', 'expired', 'cancelled'));
+
+ALTER INDEX idx_purchases_reward_id RENAME TO idx_reward_reservations_reward_id;
+ALTER TABLE purchases RENAME COLUMN paid_at TO confirmed_at;
+ALTER TABLE purchases RENAME COLUMN payment_status TO status;
+ALTER TABLE purchases RENAME TO reward_reservations;
+
+CREATE INDEX IF NOT EXISTS idx_reward_reservations_pending ON reward_reservations(expires_at) WHERE status = 'reserved';
@@ -0,0 +1,48 @@
+-- As reservas passam a ser pedidos de compra com valor e situação de pagamento próprios
+ALTER TABLE reward_reservations RENAME TO purchases;
+ALTER TABLE purchases RENAME COLUMN status TO payment_status;
+ALTER TABLE purchases RENAME COLUMN confirmed_at TO paid_at;
+DROP INDEX IF EXISTS idx_reward_reservations_pending;
+ALTER INDEX idx_reward_reservations_reward_id RENAME TO idx_purchases_reward_id;
+
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS reward_reservations_status_check;
+UPDATE purchases
+SET payment_status = CASE payment_status WHEN 'reserved' THEN 'pending' WHEN 'confirmed' THEN 'paid' ELSE payment_status END;
+ALTER TABLE purchases ALTER COLUMN payment_status SET DEFAULT 'pending';
+ALTER TABLE purchases ADD CONSTRAINT check_purchases_payment_status CHECK (payment_status IN ('pending', 'paid', 'expired', 'cancelled'));
+
+-- Números alocados e valores no momento da compra
+ALTER TABLE purchases ADD COLUMN numbers INTEGER[] NOT NULL DEFAULT '{}';
+ALTER TABLE purchases ADD COLUMN quantity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE purchases ADD COLUMN unit_price DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+ALTER TABLE purchases ADD COLUMN total_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+
+CREATE INDEX IF NOT EXISTS idx_purchases_pending ON purchases(expires_at) WHERE payment_status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_purchases_user_id ON purchases(user_id, created_at DESC);
+
+ALTER TABLE reward_buyers RENAME COLUMN reservation_id TO purchase_id;
+ALTER INDEX idx_reward_buyers_reservation_id RENAME TO idx_reward_buyers_purchase_id;
+
+-- Números vendidos antes dos pedidos viram um pedido pago por prêmio e comprador
+WITH legacy AS (
+    SELECT reward_id, user_id, MIN(created_at) AS created_at
+    FROM reward_buyers
+    WHERE purchase_id IS NULL
+    GROUP BY reward_id, user_id
+),
+inserted AS (
+    INSERT INTO purchases (reward_id, user_id, payment_status, expires_at, paid_at, created_at, updated_at)
+    SELECT reward_id, user_id, 'paid', created_at, created_at, created_at, NOW()
+    FROM legacy
+    RETURNING id, reward_id, user_id
+)
+UPDATE reward_buyers rb
+SET purchase_id = i.id
+FROM inserted i
+WHERE rb.purchase_id IS NULL AND rb.reward_id = i.reward_id AND rb.user_id = i.user_id;
+
+UPDATE purchases p
+SET numbers = COALESCE((SELECT array_agg(rb.number ORDER BY rb.number) FROM reward_buyers rb WHERE rb.purchase_id = p.id), '{}'),
+    unit_price = COALESCE((SELECT rd.price FROM reward_details rd WHERE rd.reward_id = p.reward_id), 0);
+
+UPDATE purchases SET quantity = cardinality(numbers), total_amount = cardinality(numbers) * unit_price;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_purchases_charge_id;
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS pix_copy_paste;
+ALTER TABLE purchases DROP COLUMN IF EXISTS charge_id;
+ALTER TABLE purchases DROP COLUMN IF EXISTS payment_provider;
@@ -0,0 +1,6 @@
+-- Cobrança criada no provedor de pagamento para cada pedido
+ALTER TABLE purchases ADD COLUMN payment_provider VARCHAR(30);
+ALTER TABLE purchases ADD COLUMN charge_id VARCHAR(64);
+ALTER TABLE purchases ADD COLUMN pix_copy_paste TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_purchases_charge_id ON purchases(payment_provider, charge_id) WHERE charge_id IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS pix_merchant_city;
+ALTER TABLE users DROP COLUMN IF EXISTS pix_key;
@@ -0,0 +1,3 @@
+-- Chave PIX do organizador para receber os pagamentos diretamente
+ALTER TABLE users ADD COLUMN pix_key VARCHAR(77);
+ALTER TABLE users ADD COLUMN pix_merchant_city VARCHAR(15);
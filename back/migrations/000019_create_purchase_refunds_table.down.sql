@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS purchase_refunds;
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS cancel_reason;
+ALTER TABLE purchases DROP COLUMN IF EXISTS cancelled_by;
+ALTER TABLE purchases DROP COLUMN IF EXISTS cancelled_at;
+
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS check_purchases_payment_status;
+UPDATE purchases SET payment_status = 'cancelled' WHERE payment_status = 'refunded';
+ALTER TABLE purchases ADD CONSTRAINT check_purchases_payment_status CHECK (payment_status IN ('pending', 'paid', 'expired', 'cancelled'));
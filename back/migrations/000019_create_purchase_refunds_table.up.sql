@@ -0,0 +1,22 @@
+-- Pedidos cancelados continuam registrados, com quem cancelou e o motivo
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS check_purchases_payment_status;
+ALTER TABLE purchases ADD CONSTRAINT check_purchases_payment_status CHECK (payment_status IN ('pending', 'paid', 'expired', 'cancelled', 'refunded'));
+ALTER TABLE purchases ADD COLUMN cancelled_at TIMESTAMP;
+ALTER TABLE purchases ADD COLUMN cancelled_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE purchases ADD COLUMN cancel_reason TEXT;
+
+-- Devoluções dos valores pagos, criadas no cancelamento ou quando um pagamento não pode ser aceito
+CREATE TABLE IF NOT EXISTS purchase_refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL CHECK (amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'processing' CHECK (status IN ('processing', 'completed', 'failed', 'manual')),
+    provider VARCHAR(30),
+    reason TEXT,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    failure_reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_refunds_purchase_id ON purchase_refunds(purchase_id);
@@ -0,0 +1,87 @@
+-- Contas do razão: uma carteira por usuário e contas do sistema que são a contrapartida dos lançamentos
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(50) UNIQUE,
+    user_id UUID UNIQUE REFERENCES users(id) ON DELETE RESTRICT,
+    balance DECIMAL(12,2) NOT NULL DEFAULT 0.00,
+    allow_negative BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_ledger_accounts_owner CHECK ((code IS NULL) <> (user_id IS NULL)),
+    CONSTRAINT check_ledger_accounts_balance CHECK (allow_negative OR balance >= 0)
+);
+
+INSERT INTO ledger_accounts (code, allow_negative) VALUES
+    ('sales', true),
+    ('prizes', true),
+    ('referrals', true)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type VARCHAR(30) NOT NULL,
+    reference_id UUID,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_transactions_reference ON ledger_transactions(type, reference_id);
+
+-- Lançamentos com sinal: positivo credita e negativo debita a conta
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id UUID NOT NULL REFERENCES ledger_transactions(id),
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id),
+    amount DECIMAL(12,2) NOT NULL CHECK (amount <> 0),
+    balance_after DECIMAL(12,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries(account_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction ON ledger_entries(transaction_id);
+
+-- O saldo da conta acompanha cada lançamento, que guarda o saldo resultante
+CREATE OR REPLACE FUNCTION ledger_apply_entry() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE ledger_accounts SET balance = balance + NEW.amount
+    WHERE id = NEW.account_id
+    RETURNING balance INTO NEW.balance_after;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ledger_entries_apply
+    BEFORE INSERT ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_apply_entry();
+
+-- Cada transação precisa fechar em zero ao final da transação do banco
+CREATE OR REPLACE FUNCTION ledger_check_balanced() RETURNS TRIGGER AS $$
+DECLARE
+    total DECIMAL(12,2);
+BEGIN
+    SELECT COALESCE(SUM(amount), 0) INTO total FROM ledger_entries WHERE transaction_id = NEW.transaction_id;
+    IF total <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % is not balanced (%)', NEW.transaction_id, total;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trg_ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_check_balanced();
+
+-- O razão só recebe inclusões
+CREATE OR REPLACE FUNCTION ledger_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+CREATE TRIGGER trg_ledger_transactions_append_only
+    BEFORE UPDATE OR DELETE ON ledger_transactions
+    FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
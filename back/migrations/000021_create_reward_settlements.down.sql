@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reward_settlements;
+
+ALTER TABLE users DROP COLUMN IF EXISTS platform_fee_percent;
+ALTER TABLE reward_details DROP COLUMN IF EXISTS platform_fee_percent;
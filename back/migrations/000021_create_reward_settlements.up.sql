@@ -0,0 +1,22 @@
+-- Taxa da plataforma (percentual) por prêmio ou por organizador; sem nenhuma vale PLATFORM_FEE_PERCENT
+ALTER TABLE reward_details ADD COLUMN platform_fee_percent DECIMAL(5,2) CHECK (platform_fee_percent BETWEEN 0 AND 100);
+ALTER TABLE users ADD COLUMN platform_fee_percent DECIMAL(5,2) CHECK (platform_fee_percent BETWEEN 0 AND 100);
+
+-- Repasse ao organizador, apurado uma única vez quando o prêmio é sorteado
+CREATE TABLE IF NOT EXISTS reward_settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reward_id UUID NOT NULL UNIQUE REFERENCES rewards(id) ON DELETE RESTRICT,
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    paid_purchases INTEGER NOT NULL DEFAULT 0,
+    gross_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00,
+    fee_percent DECIMAL(5,2) NOT NULL,
+    fee_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00,
+    net_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid')),
+    payout_reference VARCHAR(255),
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_settlements_owner ON reward_settlements(owner_id, created_at DESC);
@@ -0,0 +1,6 @@
+ALTER TABLE purchases DROP COLUMN IF EXISTS bonus_numbers;
+ALTER TABLE purchases DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE purchases DROP COLUMN IF EXISTS coupon_id;
+
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
@@ -0,0 +1,46 @@
+-- Cupons de desconto dos organizadores: valem para um prêmio (reward_id) ou para todos os prêmios do organizador
+CREATE TABLE IF NOT EXISTS coupons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reward_id UUID REFERENCES rewards(id) ON DELETE CASCADE,
+    code VARCHAR(30) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percentage', 'fixed', 'bonus_numbers')),
+    discount_percent DECIMAL(5,2) CHECK (discount_percent > 0 AND discount_percent <= 100),
+    discount_amount DECIMAL(10,2) CHECK (discount_amount > 0),
+    bonus_numbers INTEGER CHECK (bonus_numbers > 0),
+    min_quantity INTEGER CHECK (min_quantity > 0),
+    max_uses INTEGER CHECK (max_uses > 0),
+    max_uses_per_user INTEGER CHECK (max_uses_per_user > 0),
+    starts_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (
+        (type = 'percentage' AND discount_percent IS NOT NULL) OR
+        (type = 'fixed' AND discount_amount IS NOT NULL) OR
+        (type = 'bonus_numbers' AND bonus_numbers IS NOT NULL AND min_quantity IS NOT NULL)
+    ),
+    CHECK (expires_at IS NULL OR starts_at IS NULL OR expires_at > starts_at)
+);
+
+-- O código é único entre os cupons do organizador, sem diferenciar maiúsculas
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_owner_code ON coupons(owner_id, UPPER(code));
+
+-- Resgates dos cupons: um por pedido de compra
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    coupon_id UUID NOT NULL REFERENCES coupons(id) ON DELETE CASCADE,
+    purchase_id UUID NOT NULL UNIQUE REFERENCES purchases(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    bonus_numbers INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon ON coupon_redemptions(coupon_id, user_id);
+
+-- Desconto e números bônus aplicados no pedido
+ALTER TABLE purchases ADD COLUMN coupon_id UUID REFERENCES coupons(id) ON DELETE SET NULL;
+ALTER TABLE purchases ADD COLUMN discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00;
+ALTER TABLE purchases ADD COLUMN bonus_numbers INTEGER NOT NULL DEFAULT 0;
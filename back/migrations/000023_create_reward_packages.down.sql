@@ -0,0 +1,3 @@
+ALTER TABLE purchases DROP COLUMN IF EXISTS package_id;
+
+DROP TABLE IF EXISTS reward_packages;
@@ -0,0 +1,16 @@
+-- Pacotes de números com preço próprio ("50 números por R$ 20,00")
+CREATE TABLE IF NOT EXISTS reward_packages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reward_id UUID NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price DECIMAL(10,2) NOT NULL CHECK (price > 0),
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Cada quantidade tem no máximo um pacote ativo por prêmio; pacotes removidos ficam inativos no histórico dos pedidos
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reward_packages_reward_quantity ON reward_packages(reward_id, quantity) WHERE active;
+
+ALTER TABLE purchases ADD COLUMN package_id UUID REFERENCES reward_packages(id) ON DELETE SET NULL;
@@ -0,0 +1,24 @@
+-- Código de indicação de cada usuário, gerado no primeiro acesso
+ALTER TABLE users ADD COLUMN referral_code VARCHAR(12) UNIQUE;
+
+-- Indicações: cada usuário é indicado no máximo uma vez. As condições do bônus valem as do momento da indicação
+CREATE TABLE IF NOT EXISTS referrals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    referrer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referred_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    reward_type VARCHAR(20) NOT NULL CHECK (reward_type IN ('bonus_numbers', 'wallet_credit')),
+    bonus_numbers INTEGER NOT NULL DEFAULT 0 CHECK (bonus_numbers >= 0),
+    credit_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00 CHECK (credit_amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rewarded')),
+    reward_id UUID REFERENCES rewards(id) ON DELETE SET NULL,
+    purchase_id UUID REFERENCES purchases(id) ON DELETE SET NULL,
+    bonus_purchase_id UUID REFERENCES purchases(id) ON DELETE SET NULL,
+    awarded_numbers INTEGER NOT NULL DEFAULT 0,
+    awarded_amount DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    ledger_transaction_id UUID REFERENCES ledger_transactions(id),
+    rewarded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_referrals_self CHECK (referrer_id <> referred_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals(referrer_id, created_at DESC);
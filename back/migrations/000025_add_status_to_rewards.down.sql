@@ -0,0 +1,9 @@
+ALTER TABLE rewards ADD COLUMN completed BOOLEAN DEFAULT FALSE;
+UPDATE rewards SET completed = status IN ('drawn', 'delivered');
+CREATE INDEX IF NOT EXISTS idx_rewards_completed ON rewards(completed);
+
+DROP INDEX IF EXISTS idx_rewards_status;
+ALTER TABLE rewards DROP CONSTRAINT IF EXISTS check_rewards_drawn;
+ALTER TABLE rewards DROP CONSTRAINT IF EXISTS check_rewards_status;
+ALTER TABLE rewards DROP COLUMN IF EXISTS status_changed_at;
+ALTER TABLE rewards DROP COLUMN IF EXISTS status;
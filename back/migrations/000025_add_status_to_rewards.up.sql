@@ -0,0 +1,23 @@
+-- Situação do prêmio no seu ciclo de vida, no lugar do campo completed
+ALTER TABLE rewards ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft';
+ALTER TABLE rewards ADD COLUMN status_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Prêmios existentes já estavam à venda ou sorteados; os marcados como completados sem sorteio
+-- ficam com as vendas encerradas, aguardando o sorteio
+UPDATE rewards
+SET status = CASE
+        WHEN winner_number IS NOT NULL THEN 'drawn'
+        WHEN completed THEN 'sales_closed'
+        ELSE 'published'
+    END,
+    status_changed_at = COALESCE(drawn_at, updated_at, CURRENT_TIMESTAMP);
+
+ALTER TABLE rewards ADD CONSTRAINT check_rewards_status
+    CHECK (status IN ('draft', 'published', 'sales_closed', 'drawn', 'cancelled', 'delivered'));
+ALTER TABLE rewards ADD CONSTRAINT check_rewards_drawn
+    CHECK ((status IN ('drawn', 'delivered')) = (winner_number IS NOT NULL));
+
+DROP INDEX IF EXISTS idx_rewards_completed;
+ALTER TABLE rewards DROP COLUMN completed;
+
+CREATE INDEX IF NOT EXISTS idx_rewards_status ON rewards(status, draw_date);
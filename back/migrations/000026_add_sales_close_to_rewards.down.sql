@@ -0,0 +1,3 @@
+ALTER TABLE reward_details DROP CONSTRAINT IF EXISTS check_sales_close_minutes;
+
+ALTER TABLE reward_details DROP COLUMN IF EXISTS sales_close_minutes;
@@ -0,0 +1,4 @@
+-- Minutos antes de draw_date em que as vendas do prêmio são encerradas (zero encerra na data do sorteio)
+ALTER TABLE reward_details ADD COLUMN sales_close_minutes INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE reward_details ADD CONSTRAINT check_sales_close_minutes CHECK (sales_close_minutes >= 0);
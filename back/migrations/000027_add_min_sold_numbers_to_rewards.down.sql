@@ -0,0 +1,3 @@
+ALTER TABLE reward_details DROP CONSTRAINT IF EXISTS check_min_sold_numbers;
+
+ALTER TABLE reward_details DROP COLUMN IF EXISTS min_sold_numbers;
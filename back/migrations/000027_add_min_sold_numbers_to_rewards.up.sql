@@ -0,0 +1,4 @@
+-- Mínimo de números vendidos para o sorteio acontecer; sem ele o prêmio é cancelado na data do sorteio
+ALTER TABLE reward_details ADD COLUMN min_sold_numbers INTEGER;
+
+ALTER TABLE reward_details ADD CONSTRAINT check_min_sold_numbers CHECK (min_sold_numbers IS NULL OR min_sold_numbers > 0);
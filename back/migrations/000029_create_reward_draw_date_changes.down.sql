@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reward_draw_date_changes_reward;
+DROP TABLE IF EXISTS reward_draw_date_changes;
@@ -0,0 +1,13 @@
+-- Histórico de adiamentos da data do sorteio
+CREATE TABLE IF NOT EXISTS reward_draw_date_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reward_id UUID NOT NULL REFERENCES rewards(id) ON DELETE CASCADE,
+    previous_date TIMESTAMP NOT NULL,
+    new_date TIMESTAMP NOT NULL,
+    reason TEXT NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_draw_date_postponed CHECK (new_date > previous_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_draw_date_changes_reward ON reward_draw_date_changes(reward_id, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_prize_claims_user;
+DROP TABLE IF EXISTS prize_claims;
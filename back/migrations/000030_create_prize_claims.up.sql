@@ -0,0 +1,42 @@
+-- Resgate e entrega dos prêmios sorteados, um por número vencedor
+CREATE TABLE IF NOT EXISTS prize_claims (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reward_id UUID NOT NULL,
+    number INTEGER NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    prize_name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'confirmed', 'shipped', 'received', 'forfeited')),
+    claim_deadline TIMESTAMP NOT NULL,
+    recipient_name VARCHAR(255),
+    recipient_phone VARCHAR(30),
+    delivery_address TEXT,
+    delivery_notes TEXT,
+    confirmed_at TIMESTAMP,
+    delivery_method VARCHAR(20) CHECK (delivery_method IN ('shipping', 'handover')),
+    tracking_code VARCHAR(100),
+    shipping_notes TEXT,
+    proof_images TEXT[] NOT NULL DEFAULT '{}',
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP,
+    forfeited_at TIMESTAMP,
+    replaced_by UUID REFERENCES prize_claims(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (reward_id, number),
+    FOREIGN KEY (reward_id, number) REFERENCES reward_winners(reward_id, number) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_prize_claims_user ON prize_claims(user_id, created_at DESC);
+
+-- Prêmios já sorteados recebem um resgate por vencedor; os já entregues são considerados recebidos
+INSERT INTO prize_claims (reward_id, number, user_id, position, prize_name, status, claim_deadline, received_at)
+SELECT rw.reward_id, rw.number, rw.user_id, rw.position, rw.prize_name,
+    CASE WHEN r.status = 'delivered' THEN 'received' ELSE 'pending' END,
+    CURRENT_TIMESTAMP + INTERVAL '7 days',
+    CASE WHEN r.status = 'delivered' THEN r.status_changed_at END
+FROM reward_winners rw
+INNER JOIN rewards r ON r.id = rw.reward_id
+WHERE r.status IN ('drawn', 'delivered')
+ON CONFLICT DO NOTHING;
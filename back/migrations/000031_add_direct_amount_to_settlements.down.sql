@@ -0,0 +1,6 @@
+UPDATE reward_settlements
+SET gross_amount = gross_amount + direct_amount,
+    net_amount = gross_amount + direct_amount - fee_amount
+WHERE direct_amount > 0 AND status = 'pending';
+
+ALTER TABLE reward_settlements DROP COLUMN IF EXISTS direct_amount;
@@ -0,0 +1,16 @@
+-- Valor pago por PIX direto ao organizador: não entra no valor repassado, mas paga a taxa da plataforma
+ALTER TABLE reward_settlements ADD COLUMN direct_amount DECIMAL(12,2) NOT NULL DEFAULT 0.00;
+
+-- Corrigir os repasses ainda não pagos que contaram as vendas por PIX direto como recebidas pela plataforma
+UPDATE reward_settlements s
+SET direct_amount = direct.amount,
+    gross_amount = s.gross_amount - direct.amount,
+    net_amount = s.gross_amount - direct.amount - s.fee_amount,
+    updated_at = CURRENT_TIMESTAMP
+FROM (
+    SELECT reward_id, SUM(total_amount) AS amount
+    FROM purchases
+    WHERE payment_status = 'paid' AND payment_provider = 'pix_direct'
+    GROUP BY reward_id
+) direct
+WHERE direct.reward_id = s.reward_id AND s.status = 'pending';
@@ -0,0 +1,4 @@
+UPDATE referrals SET status = 'rewarded' WHERE status = 'revoked';
+ALTER TABLE referrals DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE referrals DROP CONSTRAINT IF EXISTS referrals_status_check;
+ALTER TABLE referrals ADD CONSTRAINT referrals_status_check CHECK (status IN ('pending', 'rewarded'));
@@ -0,0 +1,4 @@
+-- Indicações cujo pedido foi cancelado ou devolvido têm o bônus desfeito e não rendem outro bônus
+ALTER TABLE referrals DROP CONSTRAINT IF EXISTS referrals_status_check;
+ALTER TABLE referrals ADD CONSTRAINT referrals_status_check CHECK (status IN ('pending', 'rewarded', 'revoked'));
+ALTER TABLE referrals ADD COLUMN revoked_at TIMESTAMP;
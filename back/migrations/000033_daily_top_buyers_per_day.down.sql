@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_reward_top_buyers_daily;
+DROP INDEX IF EXISTS idx_reward_top_buyers_overall;
+ALTER TABLE reward_top_buyers DROP CONSTRAINT IF EXISTS check_reward_top_buyers_reference_date;
+
+-- Mantém apenas o último dia apurado de cada prêmio
+DELETE FROM reward_top_buyers tb
+WHERE tb.kind = 'daily'
+    AND EXISTS (
+        SELECT 1 FROM reward_top_buyers newer
+        WHERE newer.reward_id = tb.reward_id AND newer.kind = 'daily' AND newer.reference_date > tb.reference_date
+    );
+
+ALTER TABLE reward_top_buyers ADD CONSTRAINT reward_top_buyers_pkey PRIMARY KEY (reward_id, kind);
@@ -0,0 +1,7 @@
+-- O maior comprador do dia passa a ser apurado em cada dia de vendas, com um registro por dia
+ALTER TABLE reward_top_buyers DROP CONSTRAINT IF EXISTS reward_top_buyers_pkey;
+ALTER TABLE reward_top_buyers ADD CONSTRAINT check_reward_top_buyers_reference_date
+    CHECK (kind = 'overall' OR reference_date IS NOT NULL);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reward_top_buyers_overall ON reward_top_buyers(reward_id) WHERE kind = 'overall';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reward_top_buyers_daily ON reward_top_buyers(reward_id, reference_date) WHERE kind = 'daily';
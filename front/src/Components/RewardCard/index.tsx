@@ -13,6 +13,7 @@ interface RewardCardProps {
 
 const RewardCard: React.FC<RewardCardProps> = ({ reward, routeItem, onEdit, onDelete, onDraw}) => {
     const navigate = useNavigate();
+    const drawn = reward.status === 'drawn' || reward.status === 'delivered';
     
     const formatDate = (date: string) => {
         return new Date(date).toLocaleDateString('pt-BR', {
@@ -60,8 +61,8 @@ const RewardCard: React.FC<RewardCardProps> = ({ reward, routeItem, onEdit, onDe
                     <p className="reward-description">{reward.description}</p>
                     <div className="reward-status">
                         <p className="reward-date">Data do Sorteio: {formatDate(reward.draw_date)}</p>
-                        <span className={`reward-card-status-badge ${reward.completed ? 'completed' : 'pending'}`}>
-                            {reward.completed ? 'Sorteado' : 'Disponível'}
+                        <span className={`reward-card-status-badge ${drawn ? 'completed' : 'pending'}`}>
+                            {drawn ? 'Sorteado' : reward.status === 'published' ? 'Disponível' : 'Indisponível'}
                         </span>
                     </div>
                 </div>
@@ -86,7 +87,7 @@ const RewardCard: React.FC<RewardCardProps> = ({ reward, routeItem, onEdit, onDe
                         🗑️
                     </button>
                 )}
-                {onDraw && !reward.completed && (
+                {onDraw && !drawn && reward.status !== 'cancelled' && reward.status !== 'draft' && (
                     <button 
                         className="draw-button"
                         onClick={handleDrawClick}
@@ -1,5 +1,7 @@
 import type { Buyer } from "./Buyers";
 
+export type RewardStatus = 'draft' | 'published' | 'sales_closed' | 'drawn' | 'cancelled' | 'delivered';
+
 export interface Reward {
     id: string;
     owner_id: string;
@@ -7,12 +9,57 @@ export interface Reward {
     description: string;
     image: string;
     draw_date: string;
-    completed: boolean;
+    status: RewardStatus;
+    status_changed_at: string;
     created_at: string;
     updated_at: string;
     images: string[];
     price: number;
     min_quota: number;
+    sales_close_minutes?: number;
+    sales_close_at?: string;
+    min_sold_numbers?: number;
+    sold_numbers?: number;
+    draw_date_changes?: DrawDateChange[];
+}
+
+export interface DrawDateChange {
+    id: string;
+    reward_id: string;
+    previous_date: string;
+    new_date: string;
+    reason: string;
+    changed_by?: string;
+    created_at: string;
+}
+
+export type PrizeClaimStatus = 'pending' | 'confirmed' | 'shipped' | 'received' | 'forfeited';
+
+// Resgate do prêmio de um número vencedor
+export interface PrizeClaim {
+    id: string;
+    reward_id: string;
+    number: number;
+    user_id: string;
+    position: number;
+    prize_name: string;
+    status: PrizeClaimStatus;
+    claim_deadline: string;
+    recipient_name?: string;
+    recipient_phone?: string;
+    delivery_address?: string;
+    delivery_notes?: string;
+    confirmed_at?: string;
+    delivery_method?: 'shipping' | 'handover';
+    tracking_code?: string;
+    shipping_notes?: string;
+    proof_images: string[];
+    shipped_at?: string;
+    received_at?: string;
+    forfeited_at?: string;
+    replaced_by?: string;
+    created_at: string;
+    updated_at: string;
 }
 
 // Objeto para detalhes do prêmio com compradores
@@ -8,13 +8,23 @@ export interface User {
 }
 
 export interface Purchase {
-    id: number;
+    id: string;
     rewardId: string;
     rewardName: string;
     rewardImage: string;
     numbers: number[];
+    quantity: number;
+    unitPrice: number;
     purchaseDate: string;
     totalAmount: number;
+    discountAmount: number;
+    bonusNumbers: number;
+    couponCode?: string;
+    packageId?: string;
+    paymentStatus: 'pending' | 'paid' | 'expired' | 'cancelled' | 'refunded';
+    paidAt?: string;
+    cancelledAt?: string;
+    cancelReason?: string;
     status: 'active' | 'completed' | 'cancelled';
 }
 
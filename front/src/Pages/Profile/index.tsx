@@ -10,7 +10,7 @@ const Profile: React.FC = () => {
     const { showError } = useToastContext();
     const [purchases, setPurchases] = useState<Purchase[]>([]);
     const [loading, setLoading] = useState(true);
-    const [expandedPurchases, setExpandedPurchases] = useState<Set<number>>(new Set());
+    const [expandedPurchases, setExpandedPurchases] = useState<Set<string>>(new Set());
 
     useEffect(() => {
         const fetchUserPurchases = async () => {
@@ -43,7 +43,7 @@ const Profile: React.FC = () => {
         }
     }, [authUser?.id, authLoading]);
 
-    const togglePurchaseExpansion = (purchaseId: number) => {
+    const togglePurchaseExpansion = (purchaseId: string) => {
         setExpandedPurchases(prev => {
             const newSet = new Set(prev);
             if (newSet.has(purchaseId)) {
@@ -115,6 +115,8 @@ export default function RewardDetails() {
         );
     }
         
+    const drawn = reward.status === 'drawn' || reward.status === 'delivered';
+
     return (
         <div className="reward-details-container">
             <div className="reward-details-content">
@@ -130,13 +132,13 @@ export default function RewardDetails() {
                         <div className="reward-details-meta">
                             <div className="reward-details-date">
                                 <strong>Data do Sorteio:</strong> {formatDate(reward.draw_date)}
-                                <span className={`status-badge ${reward.completed ? 'completed' : 'pending'}`}> 
-                                    {reward.completed ? 'Sorteado' : 'Disponível'}
+                                <span className={`status-badge ${drawn ? 'completed' : 'pending'}`}> 
+                                    {drawn ? 'Sorteado' : reward.status === 'published' ? 'Disponível' : 'Indisponível'}
                                 </span>
                             </div>
                             
                             {/* Mostrar ganhador quando o prêmio estiver sorteado */}
-                            {reward.completed && reward.winner_user && (
+                            {drawn && reward.winner_user && (
                                 <div className="reward-winner-info">
                                     <h3>🎉 Ganhador do Sorteio</h3>
                                     <div className="winner-details">
@@ -147,8 +149,8 @@ export default function RewardDetails() {
                             )}
                         </div>
                         
-                        {/* Mostrar informações de compra apenas se o prêmio não estiver sorteado */}
-                        {!reward.completed && (
+                        {/* Mostrar informações de compra apenas se o prêmio estiver à venda */}
+                        {reward.status === 'published' && (
                             <>
                                 <div className="reward-price-box">
                                     <div className="reward-price-sober">
@@ -173,7 +175,7 @@ export default function RewardDetails() {
                         )}
                         
                         {/* Mostrar mensagem quando o prêmio estiver sorteado */}
-                        {reward.completed && (
+                        {drawn && (
                             <div className="reward-completed-message">
                                 <p>🎯 Este sorteio já foi realizado. Obrigado por participar!</p>
                             </div>
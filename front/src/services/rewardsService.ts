@@ -152,9 +152,10 @@ export const rewardsService = {
         price: number;        
     }): Promise<Reward> {
         try {
+            // Prêmios criados pelo formulário já são publicados
             const response = await authenticatedFetch('/rewards', {
                 method: 'POST',
-                body: JSON.stringify(data)
+                body: JSON.stringify({ ...data, status: 'published' })
             });
             
             if (!response.ok) {